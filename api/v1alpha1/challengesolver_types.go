@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ChallengeSolverType names a supported anti-bot solver implementation
+// +kubebuilder:validation:Enum=flaresolverr
+type ChallengeSolverType string
+
+const (
+	// ChallengeSolverFlareSolverr solves challenges with FlareSolverr
+	ChallengeSolverFlareSolverr ChallengeSolverType = "flaresolverr"
+)
+
+// ChallengeSolverSpec defines the desired state of ChallengeSolver
+type ChallengeSolverSpec struct {
+	// Type of the solver service
+	// +kubebuilder:default=flaresolverr
+	// +optional
+	Type ChallengeSolverType `json:"type,omitempty"`
+
+	// Endpoint of the solver service (e.g. http://flaresolverr:8191)
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Endpoint string `json:"endpoint"`
+
+	// Timeout bounds how long the solver may spend on a single challenge
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// MaxSessions bounds the browser sessions kept open at once, the least
+	// recently used one is closed to make room. Zero means unbounded.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxSessions int `json:"maxSessions,omitempty"`
+
+	// Indexers using this solver, as "namespace/name" or a bare name
+	// matching the indexer in any namespace
+	// +optional
+	Indexers []string `json:"indexers,omitempty"`
+
+	// IndexerSelector selects indexers using this solver by label
+	// +optional
+	IndexerSelector *metav1.LabelSelector `json:"indexerSelector,omitempty"`
+}
+
+// ChallengeSolverStatus defines the observed state of ChallengeSolver
+type ChallengeSolverStatus struct {
+	// Conditions store the status conditions of the ChallengeSolver
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// LastChecked is when the solver was last health checked
+	// +optional
+	LastChecked metav1.Time `json:"lastChecked,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Endpoint",type="string",JSONPath=".spec.endpoint"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].message",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ChallengeSolver is the Schema for the challengesolvers API
+type ChallengeSolver struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ChallengeSolverSpec   `json:"spec,omitempty"`
+	Status ChallengeSolverStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ChallengeSolverList contains a list of ChallengeSolver
+type ChallengeSolverList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ChallengeSolver `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ChallengeSolver{}, &ChallengeSolverList{})
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CustomFormatSpec defines the desired state of CustomFormat
+type CustomFormatSpec struct {
+	// Conditions a result must all meet to match the format
+	// +kubebuilder:validation:MinItems=1
+	Conditions []FormatCondition `json:"conditions"`
+
+	// Score added to matching results. Negative scores penalize them.
+	Score int `json:"score"`
+}
+
+// FormatCondition checks one attribute of a result
+// +kubebuilder:validation:XValidation:rule="[has(self.title), has(self.releaseGroup), has(self.size), has(self.indexers), has(self.languages)].filter(x, x).size() == 1",message="exactly one of title, releaseGroup, size, indexers or languages is required"
+type FormatCondition struct {
+	// Name describes the condition
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Title is a regular expression the release title matches, ignoring case
+	// +optional
+	Title string `json:"title,omitempty"`
+
+	// ReleaseGroup is a regular expression the release group matches,
+	// ignoring case
+	// +optional
+	ReleaseGroup string `json:"releaseGroup,omitempty"`
+
+	// Size bounds the size of the result
+	// +optional
+	Size *SizeRange `json:"size,omitempty"`
+
+	// Indexers the result comes from
+	// +optional
+	Indexers []string `json:"indexers,omitempty"`
+
+	// Languages the release is in, any of them matches. Releases not
+	// mentioning a language are taken to be in English.
+	// +optional
+	Languages []string `json:"languages,omitempty"`
+
+	// Negate matches results that do not meet the condition
+	// +optional
+	Negate bool `json:"negate,omitempty"`
+}
+
+// SizeRange bounds a size, either bound may be left out
+type SizeRange struct {
+	// Min is the smallest matching size
+	// +optional
+	Min *resource.Quantity `json:"min,omitempty"`
+
+	// Max is the largest matching size
+	// +optional
+	Max *resource.Quantity `json:"max,omitempty"`
+}
+
+// CustomFormatStatus defines the observed state of CustomFormat
+type CustomFormatStatus struct {
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=cf
+// +kubebuilder:printcolumn:name="Score",type="integer",JSONPath=".spec.score"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// CustomFormat is the Schema for the customformats API
+type CustomFormat struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CustomFormatSpec   `json:"spec,omitempty"`
+	Status CustomFormatStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CustomFormatList contains a list of CustomFormat
+type CustomFormatList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CustomFormat `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CustomFormat{}, &CustomFormatList{})
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DownloadClientType names a supported download client
+// +kubebuilder:validation:Enum=qbittorrent;transmission;deluge;rtorrent
+type DownloadClientType string
+
+const (
+	// DownloadClientQBittorrent downloads with qBittorrent through its WebUI API
+	DownloadClientQBittorrent DownloadClientType = "qbittorrent"
+	// DownloadClientTransmission downloads with Transmission through its RPC
+	// API. Transmission has no categories, the category is added as a label.
+	DownloadClientTransmission DownloadClientType = "transmission"
+	// DownloadClientDeluge downloads with Deluge through the JSON-RPC API of
+	// its web UI. The category is set as the label of the Label plugin.
+	DownloadClientDeluge DownloadClientType = "deluge"
+	// DownloadClientRTorrent downloads with rTorrent through its XML-RPC API,
+	// served over HTTP. The category is set as the ruTorrent label.
+	DownloadClientRTorrent DownloadClientType = "rtorrent"
+)
+
+// DownloadClientSpec defines the desired state of DownloadClient
+type DownloadClientSpec struct {
+	// Type of the download client
+	Type DownloadClientType `json:"type"`
+
+	// URL of the client API (e.g. http://qbittorrent:8080). The RPC path of
+	// Transmission, /transmission/rpc, and of rTorrent, /RPC2, is appended
+	// when the URL has none. The Deluge URL is the one of its web UI.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// CredentialsSecretRef references a Secret holding the "username" and
+	// "password" keys, unset for clients without authentication. Deluge
+	// only takes the password.
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+
+	// Category the torrents are added in
+	// +optional
+	Category string `json:"category,omitempty"`
+
+	// SavePath the torrents are downloaded to, the client default when unset
+	// +optional
+	SavePath string `json:"savePath,omitempty"`
+
+	// Tags added to the torrents, as labels for Transmission. Deluge and
+	// rTorrent have no tags.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// Default makes Torrents without a downloadClientRef use this client.
+	// The only DownloadClient of a namespace is its default.
+	// +optional
+	Default bool `json:"default,omitempty"`
+
+	// RemoveData deletes the downloaded files along with the torrent when a
+	// Torrent is deleted, e.g. when a monitored request upgrades it. rTorrent
+	// cannot delete files and does not support it.
+	// +optional
+	RemoveData bool `json:"removeData,omitempty"`
+
+	// StalledTimeout is how long a downloading torrent may make no progress
+	// with no seeder connected before it is marked Stalled, 30m when unset.
+	// 0 never marks torrents stalled.
+	// +optional
+	StalledTimeout *metav1.Duration `json:"stalledTimeout,omitempty"`
+}
+
+// DownloadClientStatus defines the observed state of DownloadClient
+type DownloadClientStatus struct {
+	// Version reported by the client
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Conditions store the status conditions of the DownloadClient
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// LastChecked is when the client was last health checked
+	// +optional
+	LastChecked metav1.Time `json:"lastChecked,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=dlc
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".spec.url"
+// +kubebuilder:printcolumn:name="Default",type="boolean",JSONPath=".spec.default"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].message",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// DownloadClient is the Schema for the downloadclients API. Torrents are
+// handed to the download client they reference, or to the default one of
+// their namespace.
+type DownloadClient struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DownloadClientSpec   `json:"spec,omitempty"`
+	Status DownloadClientStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DownloadClientList contains a list of DownloadClient
+type DownloadClientList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DownloadClient `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DownloadClient{}, &DownloadClientList{})
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -38,6 +39,22 @@ type IndexerSpec struct {
 	FollowRedirect  bool     `json:"followredirect,omitempty"`
 	TestLinkTorrent bool     `json:"testlinktorrent,omitempty"`
 
+	// CABundleSecretRef references a Secret key holding a PEM bundle of
+	// additional certificate authorities trusted for this indexer, on top of
+	// the certificate fingerprints listed in Certificates
+	// +optional
+	CABundleSecretRef *corev1.SecretKeySelector `json:"caBundleSecretRef,omitempty"`
+
+	// Proxy routes every request to this indexer through a proxy, overriding
+	// the operator default proxy
+	// +optional
+	Proxy *ProxyConfig `json:"proxy,omitempty"`
+
+	// RSS configures how the RSS sync fetches the latest releases of the
+	// indexer for monitored TorrentRequests
+	// +optional
+	RSS *RSSSync `json:"rss,omitempty"`
+
 	Caps     Caps            `json:"caps"`
 	Settings []SettingsField `json:"settings,omitempty"`
 	Login    *Login          `json:"login,omitempty"`
@@ -45,6 +62,32 @@ type IndexerSpec struct {
 	Download *DownloadBlock  `json:"download,omitempty"`
 }
 
+// RSSSync configures the RSS sync of an indexer
+type RSSSync struct {
+	// Interval between two fetches of the latest releases, the operator
+	// --rss-sync-interval when unset
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Path of the latest releases page, relative to the active link and
+	// parsed like search results. Without it the sync runs a search without
+	// keywords, which needs search.allowEmptyInputs.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// ProxyConfig describes an outbound HTTP (CONNECT) or SOCKS5 proxy
+type ProxyConfig struct {
+	// URL of the proxy, e.g. "http://vpn:8888" or "socks5://vpn:1080"
+	// +kubebuilder:validation:Pattern=`^(https?|socks5h?)://`
+	URL string `json:"url"`
+
+	// CredentialsSecretRef references a Secret holding the proxy "username"
+	// and "password" keys
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+}
+
 type Caps struct {
 	Categories       map[string]string `json:"categories,omitempty"`
 	CategoryMappings []CategoryMapping `json:"categorymappings,omitempty"`
@@ -209,12 +252,38 @@ type IndexerStatus struct {
 	// Conditions store the status conditions of the Indexer instances
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// ActiveLink is the mirror from Links currently used for searches
+	// +optional
+	ActiveLink string `json:"activeLink,omitempty"`
+
+	// Links reports the health of every mirror listed in Links
+	// +optional
+	Links []LinkStatus `json:"links,omitempty"`
+}
+
+// LinkStatus is the health check outcome for a single indexer mirror
+type LinkStatus struct {
+	// URL of the mirror
+	URL string `json:"url"`
+
+	// Healthy is true when the last health check against this mirror succeeded
+	Healthy bool `json:"healthy"`
+
+	// Message explains why the mirror is unhealthy
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastChecked is when the mirror was last health checked
+	// +optional
+	LastChecked metav1.Time `json:"lastChecked,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].message"
+// +kubebuilder:printcolumn:name="Link",type="string",JSONPath=".status.activeLink",priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Indexer is the Schema for the indexers API
@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// QualityProfileSpec defines the desired state of QualityProfile
+type QualityProfileSpec struct {
+	// Qualities allowed, from the most to the least preferred. Results of
+	// any other quality are rejected.
+	// +kubebuilder:validation:MinItems=1
+	Qualities []Quality `json:"qualities"`
+
+	// Cutoff is the name of the quality past which results are good enough,
+	// e.g. "WEB-DL 1080p". Monitored requests holding it or better stop
+	// upgrading, even below their upgradeUntil. It does not change how
+	// results rank.
+	// +optional
+	Cutoff string `json:"cutoff,omitempty"`
+
+	// RequiredWords must all appear in the title, ignoring case
+	// +optional
+	RequiredWords []string `json:"requiredWords,omitempty"`
+
+	// ForbiddenWords reject titles containing any of them, ignoring case
+	// +optional
+	ForbiddenWords []string `json:"forbiddenWords,omitempty"`
+
+	// MinFormatScore rejects results whose CustomFormat scores sum below it
+	// +optional
+	MinFormatScore int `json:"minFormatScore,omitempty"`
+
+	// PreferredGroups are release groups preferred among results of the same
+	// quality, from the most to the least preferred
+	// +optional
+	PreferredGroups []string `json:"preferredGroups,omitempty"`
+}
+
+// Quality is a resolution and source combination. It is named after both,
+// e.g. "WEB-DL 1080p", or after the one set.
+// +kubebuilder:validation:XValidation:rule="has(self.resolution) || has(self.source)",message="resolution or source is required"
+type Quality struct {
+	// Resolution of the release
+	// +kubebuilder:validation:Enum="2160p";"1080p";"720p";"576p";"480p";"360p"
+	// +optional
+	Resolution string `json:"resolution,omitempty"`
+
+	// Source of the release
+	// +kubebuilder:validation:Enum=CAM;TELESYNC;TELECINE;SCREENER;DVD;HDTV;WEBRip;WEB-DL;BluRay;Remux
+	// +optional
+	Source string `json:"source,omitempty"`
+
+	// MinSize rejects smaller results of this quality
+	// +optional
+	MinSize *resource.Quantity `json:"minSize,omitempty"`
+
+	// MaxSize rejects larger results of this quality
+	// +optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+}
+
+// QualityProfileStatus defines the observed state of QualityProfile
+type QualityProfileStatus struct {
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=qp
+// +kubebuilder:printcolumn:name="Cutoff",type="string",JSONPath=".spec.cutoff"
+// +kubebuilder:printcolumn:name="Min Score",type="integer",JSONPath=".spec.minFormatScore"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// QualityProfile is the Schema for the qualityprofiles API
+type QualityProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   QualityProfileSpec   `json:"spec,omitempty"`
+	Status QualityProfileStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// QualityProfileList contains a list of QualityProfile
+type QualityProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []QualityProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&QualityProfile{}, &QualityProfileList{})
+}
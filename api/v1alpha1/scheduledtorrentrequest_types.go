@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConcurrencyPolicy tells what to do when a scheduled search is due while
+// the previous one is still running
+// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent lets scheduled requests run concurrently
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+	// ForbidConcurrent skips the next run while the previous one is running
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+	// ReplaceConcurrent deletes the running requests and starts a new one
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// ScheduledTorrentRequestSpec defines the desired state of ScheduledTorrentRequest
+type ScheduledTorrentRequestSpec struct {
+	// Schedule in cron syntax (e.g. "0 20 * * FRI"), or a descriptor like
+	// "@daily" or "@every 6h"
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// TimeZone the schedule is evaluated in (e.g. "Europe/Paris"), the time
+	// zone of the controller when unset
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+
+	// StartingDeadlineSeconds is how late a search may start after its
+	// scheduled time. Runs missed by more, e.g. while the controller was
+	// down, are skipped.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	// ConcurrencyPolicy tells what to do when a search is due while the
+	// previous request is still running: Allow (default) runs both, Forbid
+	// skips the new one, and Replace deletes the running request
+	// +kubebuilder:default=Allow
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// Suspend stops scheduling searches, running requests are left alone
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// SuccessfulRequestsHistoryLimit is how many completed requests are
+	// kept, 3 when unset. The Torrents of deleted requests are deleted with
+	// them.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SuccessfulRequestsHistoryLimit *int32 `json:"successfulRequestsHistoryLimit,omitempty"`
+
+	// FailedRequestsHistoryLimit is how many failed requests are kept, 1
+	// when unset
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailedRequestsHistoryLimit *int32 `json:"failedRequestsHistoryLimit,omitempty"`
+
+	// RequestTemplate is the TorrentRequest created at each scheduled time
+	RequestTemplate TorrentRequestTemplate `json:"requestTemplate"`
+}
+
+// TorrentRequestTemplate describes the TorrentRequests a
+// ScheduledTorrentRequest creates
+type TorrentRequestTemplate struct {
+	// Labels added to the TorrentRequests
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations added to the TorrentRequests
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Spec of the TorrentRequests
+	Spec TorrentRequestSpec `json:"spec"`
+}
+
+// ScheduledTorrentRequestStatus defines the observed state of ScheduledTorrentRequest
+type ScheduledTorrentRequestStatus struct {
+	// Active are the TorrentRequests still searching or monitoring
+	// +optional
+	Active []corev1.ObjectReference `json:"active,omitempty"`
+
+	// LastScheduleTime is when a request was last scheduled
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// LastSuccessfulTime is when a request last completed
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// NextScheduleTime is when the next request is due
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// Conditions store the status conditions of the ScheduledTorrentRequest
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=str
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+// +kubebuilder:printcolumn:name="Suspend",type="boolean",JSONPath=".spec.suspend"
+// +kubebuilder:printcolumn:name="Last Schedule",type="date",JSONPath=".status.lastScheduleTime"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].message",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ScheduledTorrentRequest is the Schema for the scheduledtorrentrequests API.
+// It creates a TorrentRequest from its template on a cron schedule, the way a
+// CronJob creates Jobs.
+type ScheduledTorrentRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ScheduledTorrentRequestSpec   `json:"spec,omitempty"`
+	Status ScheduledTorrentRequestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ScheduledTorrentRequestList contains a list of ScheduledTorrentRequest
+type ScheduledTorrentRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ScheduledTorrentRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ScheduledTorrentRequest{}, &ScheduledTorrentRequestList{})
+}
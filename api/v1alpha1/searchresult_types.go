@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SearchResultSpec is a result found for a TorrentRequest
+type SearchResultSpec struct {
+	// Request is the name of the TorrentRequest the result was found for
+	Request string `json:"request"`
+
+	// Position of the result in the ranking of the request, 1 being the best
+	// +kubebuilder:validation:Minimum=1
+	Position int `json:"position"`
+
+	CandidateStatus `json:",inline"`
+
+	// Magnet link or download URL of the result
+	// +optional
+	Magnet string `json:"magnet,omitempty"`
+
+	// Leechers count at time of discovery
+	// +optional
+	Leechers int `json:"leechers,omitempty"`
+
+	// PublishedAt is when the torrent was uploaded
+	// +optional
+	PublishedAt *metav1.Time `json:"publishedAt,omitempty"`
+
+	// Release holds the attributes parsed from the title
+	// +optional
+	Release *ReleaseInfo `json:"release,omitempty"`
+}
+
+// SearchResultStatus defines the observed state of SearchResult
+type SearchResultStatus struct {
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=sr
+// +kubebuilder:printcolumn:name="Request",type="string",JSONPath=".spec.request"
+// +kubebuilder:printcolumn:name="#",type="integer",JSONPath=".spec.position"
+// +kubebuilder:printcolumn:name="Title",type="string",JSONPath=".spec.title"
+// +kubebuilder:printcolumn:name="Indexer",type="string",JSONPath=".spec.indexer"
+// +kubebuilder:printcolumn:name="Quality",type="string",JSONPath=".spec.quality"
+// +kubebuilder:printcolumn:name="Size",type="string",JSONPath=".spec.size"
+// +kubebuilder:printcolumn:name="Seeders",type="integer",JSONPath=".spec.seeders"
+// +kubebuilder:printcolumn:name="Score",type="integer",JSONPath=".spec.formatScore"
+// +kubebuilder:printcolumn:name="Chosen",type="boolean",JSONPath=".spec.chosen"
+// +kubebuilder:printcolumn:name="Rejected",type="boolean",JSONPath=".spec.rejected"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".spec.reason",priority=1
+// +kubebuilder:printcolumn:name="InfoHash",type="string",JSONPath=".spec.infoHash",priority=1
+
+// SearchResult is the Schema for the searchresults API. The TorrentRequest
+// controller keeps one per ranked result of a request, replacing them on
+// each search.
+type SearchResult struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SearchResultSpec   `json:"spec,omitempty"`
+	Status SearchResultStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SearchResultList contains a list of SearchResult
+type SearchResultList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SearchResult `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SearchResult{}, &SearchResultList{})
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SeriesRequestSpec defines the desired state of SeriesRequest
+// +kubebuilder:validation:XValidation:rule="has(self.series) || has(self.tvdbId) || has(self.imdbId)",message="series, tvdbId or imdbId is required"
+// +kubebuilder:validation:XValidation:rule="has(self.seasons) || (has(self.future) && self.future)",message="seasons or future is required"
+type SeriesRequestSpec struct {
+	// Series name, which indexers without ID search need
+	// +optional
+	Series string `json:"series,omitempty"`
+
+	// TVDBID of the series on TheTVDB
+	// +optional
+	TVDBID int `json:"tvdbId,omitempty"`
+
+	// IMDBID of the series on IMDb (e.g. "tt0944947")
+	// +kubebuilder:validation:Pattern=`^tt\d+$`
+	// +optional
+	IMDBID string `json:"imdbId,omitempty"`
+
+	// Seasons requested, whole or some of their episodes
+	// +optional
+	Seasons []SeasonRequest `json:"seasons,omitempty"`
+
+	// Future keeps requesting the episodes released after the last
+	// requested one, of the same season and of the next ones, with monitored
+	// TorrentRequests
+	// +optional
+	Future bool `json:"future,omitempty"`
+
+	// Template holds the settings of the TorrentRequests created for the
+	// seasons and episodes
+	// +optional
+	Template SeriesRequestTemplate `json:"template,omitempty"`
+}
+
+// SeasonRequest selects a season, or episodes of it
+type SeasonRequest struct {
+	// Season number
+	// +kubebuilder:validation:Minimum=1
+	Season int `json:"season"`
+
+	// Episodes requested, the whole season when empty
+	// +optional
+	Episodes []EpisodeRange `json:"episodes,omitempty"`
+
+	// EpisodeCount is the number of episodes of the season. A whole season
+	// is searched as a season pack, and when no pack is found it is
+	// requested episode by episode, which needs the count.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=999
+	// +optional
+	EpisodeCount int `json:"episodeCount,omitempty"`
+}
+
+// EpisodeRange is a range of episodes of a season
+// +kubebuilder:validation:XValidation:rule="!has(self.to) || self.to >= self.from",message="to must not be before from"
+type EpisodeRange struct {
+	// From is the first episode of the range
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=999
+	From int `json:"from"`
+
+	// To is the last episode of the range, From when unset
+	// +kubebuilder:validation:Maximum=999
+	// +optional
+	To int `json:"to,omitempty"`
+}
+
+// SeriesRequestTemplate is copied to the TorrentRequests of a SeriesRequest
+type SeriesRequestTemplate struct {
+	// Indexers restricts the search to these indexers
+	// +optional
+	Indexers []string `json:"indexers,omitempty"`
+
+	// QualityProfileRef ranks the results by quality
+	// +optional
+	QualityProfileRef *corev1.LocalObjectReference `json:"qualityProfileRef,omitempty"`
+
+	// MinSeeders rejects results with fewer seeders
+	// +optional
+	MinSeeders int `json:"minSeeders,omitempty"`
+
+	// MustContain rejects results whose title lacks one of these words
+	// +optional
+	MustContain []string `json:"mustContain,omitempty"`
+
+	// MustNotContain rejects results whose title contains one of these words
+	// +optional
+	MustNotContain []string `json:"mustNotContain,omitempty"`
+
+	// PreferredWords rank results whose title contains them
+	// +optional
+	PreferredWords []PreferredWord `json:"preferredWords,omitempty"`
+
+	// Filter is a CEL expression results must satisfy
+	// +optional
+	Filter string `json:"filter,omitempty"`
+
+	// SortBy is a CEL expression ranking the results
+	// +optional
+	SortBy string `json:"sortBy,omitempty"`
+
+	// Retry searches the requested seasons and episodes again when nothing
+	// acceptable is found
+	// +optional
+	Retry *RetryPolicy `json:"retry,omitempty"`
+
+	// SearchInterval is how often the future episodes are searched for
+	// +optional
+	SearchInterval *metav1.Duration `json:"searchInterval,omitempty"`
+
+	// DownloadClientRef is the DownloadClient the Torrents are handed to
+	// +optional
+	DownloadClientRef *corev1.LocalObjectReference `json:"downloadClientRef,omitempty"`
+}
+
+// SeriesRequestStatus defines the observed state of SeriesRequest
+type SeriesRequestStatus struct {
+	// EpisodesWanted is the number of episodes requested so far. A whole
+	// season without an episodeCount counts as one.
+	// +optional
+	EpisodesWanted int `json:"episodesWanted,omitempty"`
+
+	// EpisodesFound is the number of requested episodes a Torrent was
+	// created for
+	// +optional
+	EpisodesFound int `json:"episodesFound,omitempty"`
+
+	// Progress sums up the found episodes, e.g. "8/10"
+	// +optional
+	Progress string `json:"progress,omitempty"`
+
+	// Requests are the TorrentRequests of the seasons and episodes
+	// +optional
+	Requests []SeriesRequestChild `json:"requests,omitempty"`
+
+	// Conditions store the status conditions of the SeriesRequest
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// SeriesRequestChild is the state of a TorrentRequest of a SeriesRequest
+type SeriesRequestChild struct {
+	// Name of the TorrentRequest
+	Name string `json:"name"`
+
+	// Season it requests
+	Season int `json:"season"`
+
+	// Episode it requests, unset for a season pack
+	// +optional
+	Episode int `json:"episode,omitempty"`
+
+	// State of the TorrentRequest
+	// +optional
+	State string `json:"state,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=ser
+// +kubebuilder:printcolumn:name="Series",type="string",JSONPath=".spec.series"
+// +kubebuilder:printcolumn:name="Found",type="string",JSONPath=".status.progress"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].message",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// SeriesRequest is the Schema for the seriesrequests API. It requests
+// seasons and episodes of a series through TorrentRequests it owns, one per
+// season pack or episode.
+type SeriesRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SeriesRequestSpec   `json:"spec,omitempty"`
+	Status SeriesRequestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SeriesRequestList contains a list of SeriesRequest
+type SeriesRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SeriesRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SeriesRequest{}, &SeriesRequestList{})
+}
@@ -17,9 +17,18 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// CustomFormatsAnnotation lists the CustomFormats the release matched,
+	// comma separated
+	CustomFormatsAnnotation = "torrents.vitoru.fun/custom-formats"
+	// FormatScoreAnnotation is the sum of the matched CustomFormat scores
+	FormatScoreAnnotation = "torrents.vitoru.fun/format-score"
+)
+
 // TorrentSpec defines the desired state of Torrent
 type TorrentSpec struct {
 	// Title of the torrent release
@@ -51,16 +60,171 @@ type TorrentSpec struct {
 	// PublishedAt is when the torrent was uploaded
 	// +optional
 	PublishedAt *metav1.Time `json:"publishedAt,omitempty"`
+
+	// Release holds the attributes parsed from the title
+	// +optional
+	Release *ReleaseInfo `json:"release,omitempty"`
+
+	// DownloadClientRef is the DownloadClient the torrent is handed to, the
+	// default one of the namespace when unset
+	// +optional
+	DownloadClientRef *corev1.LocalObjectReference `json:"downloadClientRef,omitempty"`
+}
+
+// ReleaseInfo holds the attributes a release title encodes
+type ReleaseInfo struct {
+	// Title is the movie or series name, without the release attributes
+	// +optional
+	Title string `json:"title,omitempty"`
+
+	// Year the movie or series was released
+	// +optional
+	Year int `json:"year,omitempty"`
+
+	// Seasons the release covers
+	// +optional
+	Seasons []int `json:"seasons,omitempty"`
+
+	// Episodes the release covers, empty for season packs
+	// +optional
+	Episodes []int `json:"episodes,omitempty"`
+
+	// FullSeason is true for season packs
+	// +optional
+	FullSeason bool `json:"fullSeason,omitempty"`
+
+	// Resolution such as "2160p", "1080p" or "720p"
+	// +optional
+	Resolution string `json:"resolution,omitempty"`
+
+	// Source such as "WEB-DL", "BluRay" or "HDTV"
+	// +optional
+	Source string `json:"source,omitempty"`
+
+	// Codec such as "x264", "x265" or "AV1"
+	// +optional
+	Codec string `json:"codec,omitempty"`
+
+	// Audio codecs such as "DD+", "TrueHD" or "Atmos"
+	// +optional
+	Audio []string `json:"audio,omitempty"`
+
+	// Channels is the audio channel layout, e.g. "5.1"
+	// +optional
+	Channels string `json:"channels,omitempty"`
+
+	// HDR formats such as "DV", "HDR10" or "HDR10+"
+	// +optional
+	HDR []string `json:"hdr,omitempty"`
+
+	// Group that made the release
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// Proper is true for releases fixing another group's release
+	// +optional
+	Proper bool `json:"proper,omitempty"`
+
+	// Repack is true for releases fixing the group's own release
+	// +optional
+	Repack bool `json:"repack,omitempty"`
+
+	// Languages of the release, empty when the title does not mention any
+	// +optional
+	Languages []string `json:"languages,omitempty"`
+
+	// Edition such as "Extended" or "Director's Cut"
+	// +optional
+	Edition string `json:"edition,omitempty"`
 }
 
+// TorrentPhase is how far the download of a Torrent went
+// +kubebuilder:validation:Enum=Queued;Downloading;Seeding;Completed;Error;Stalled
+type TorrentPhase string
+
+const (
+	// TorrentQueued torrents wait to download: queued, paused or checked by
+	// the download client
+	TorrentQueued TorrentPhase = "Queued"
+	// TorrentDownloading torrents are downloading
+	TorrentDownloading TorrentPhase = "Downloading"
+	// TorrentSeeding torrents are complete and uploading
+	TorrentSeeding TorrentPhase = "Seeding"
+	// TorrentCompleted torrents are complete and no longer uploading
+	TorrentCompleted TorrentPhase = "Completed"
+	// TorrentError torrents failed in the download client, or were removed
+	// from it
+	TorrentError TorrentPhase = "Error"
+	// TorrentStalled torrents made no progress for the stalledTimeout of
+	// their DownloadClient with no seeder connected. The TorrentRequest that
+	// created one replaces it with its next candidate.
+	TorrentStalled TorrentPhase = "Stalled"
+)
+
 // TorrentStatus defines the observed state of Torrent
 type TorrentStatus struct {
-	// Insert additional status field - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// DownloadClient the torrent was handed to
+	// +optional
+	DownloadClient string `json:"downloadClient,omitempty"`
+
+	// Hash identifying the torrent in the download client
+	// +optional
+	Hash string `json:"hash,omitempty"`
+
+	// AddedAt is when the torrent was handed to the download client
+	// +optional
+	AddedAt *metav1.Time `json:"addedAt,omitempty"`
+
+	// Phase of the download, polled from the download client
+	// +optional
+	Phase TorrentPhase `json:"phase,omitempty"`
+
+	// Progress of the download, e.g. "42.5%"
+	// +optional
+	Progress string `json:"progress,omitempty"`
+
+	// DownloadRate is the current download speed, e.g. "2.4 MiB/s"
+	// +optional
+	DownloadRate string `json:"downloadRate,omitempty"`
+
+	// ETA is the estimated time left to complete the download
+	// +optional
+	ETA *metav1.Duration `json:"eta,omitempty"`
+
+	// Ratio of the uploaded to the downloaded data, e.g. "1.25"
+	// +optional
+	Ratio string `json:"ratio,omitempty"`
+
+	// SavePath the torrent is downloaded to
+	// +optional
+	SavePath string `json:"savePath,omitempty"`
+
+	// CompletedAt is when the download completed
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// LastActivityTime is when the download last progressed, stalled
+	// detection counts from it
+	// +optional
+	LastActivityTime *metav1.Time `json:"lastActivityTime,omitempty"`
+
+	// Conditions store the status conditions of the Torrent
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Title",type="string",JSONPath=".spec.title"
+// +kubebuilder:printcolumn:name="Client",type="string",JSONPath=".status.downloadClient"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Progress",type="string",JSONPath=".status.progress"
+// +kubebuilder:printcolumn:name="Rate",type="string",JSONPath=".status.downloadRate",priority=1
+// +kubebuilder:printcolumn:name="ETA",type="string",JSONPath=".status.eta",priority=1
+// +kubebuilder:printcolumn:name="Ratio",type="string",JSONPath=".status.ratio",priority=1
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].message",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Torrent is the Schema for the torrents API
 type Torrent struct {
@@ -17,13 +17,61 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// SelectedCandidateAnnotation picks the candidate of a request awaiting
+// selection, like spec.selectedCandidate
+const SelectedCandidateAnnotation = "torrents.vitoru.fun/selected-candidate"
+
+// Modes of a TorrentRequest
+const (
+	// ModeOnce searches until a Torrent is created or the request fails
+	ModeOnce = "once"
+	// ModeMonitor keeps searching until a release appears, and optionally
+	// for better ones
+	ModeMonitor = "monitor"
+)
+
+// Selection modes of a TorrentRequest
+const (
+	// SelectionAutomatic creates the Torrent from the best ranked result
+	SelectionAutomatic = "automatic"
+	// SelectionManual waits for a user to pick one of the ranked results
+	SelectionManual = "manual"
+)
+
 // TorrentRequestSpec defines the desired state of TorrentRequest
+// +kubebuilder:validation:XValidation:rule="has(self.keywords) || has(self.tv) || has(self.movie) || has(self.music) || has(self.book)",message="keywords, tv, movie, music or book is required"
+// +kubebuilder:validation:XValidation:rule="[has(self.tv), has(self.movie), has(self.music), has(self.book)].filter(x, x).size() <= 1",message="tv, movie, music and book are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!has(self.selection) || self.selection != 'manual' || !has(self.searchResults) || self.searchResults > 0",message="manual selection picks among search results, searchResults must not be 0"
+// +kubebuilder:validation:XValidation:rule="!has(self.mode) || self.mode != 'monitor' || ((!has(self.selection) || self.selection != 'manual') && !has(self.retry))",message="monitored requests search until they find a release, selection must not be manual and retry does not apply"
+// +kubebuilder:validation:XValidation:rule="!has(self.upgradeUntil) || (has(self.mode) && self.mode == 'monitor' && has(self.qualityProfileRef))",message="upgradeUntil needs mode monitor and a qualityProfileRef"
 type TorrentRequestSpec struct {
 	// Keywords to search for
-	Keywords string `json:"keywords"`
+	// +optional
+	Keywords string `json:"keywords,omitempty"`
+
+	// TV searches for an episode or season of a series, through the
+	// tv-search mode of indexers supporting it
+	// +optional
+	TV *TVQuery `json:"tv,omitempty"`
+
+	// Movie searches for a movie, through the movie-search mode of indexers
+	// supporting it
+	// +optional
+	Movie *MovieQuery `json:"movie,omitempty"`
+
+	// Music searches for an artist or album, through the music-search mode
+	// of indexers supporting it
+	// +optional
+	Music *MusicQuery `json:"music,omitempty"`
+
+	// Book searches for a book, through the book-search mode of indexers
+	// supporting it
+	// +optional
+	Book *BookQuery `json:"book,omitempty"`
 
 	// Category to search in (e.g. "Movies", "TV")
 	// +optional
@@ -33,14 +81,222 @@ type TorrentRequestSpec struct {
 	// +optional
 	MinSeeders int `json:"minSeeders,omitempty"`
 
+	// MustContain rejects results whose title lacks any of these words,
+	// ignoring case. Words only match whole; values between slashes are
+	// regular expressions, e.g. "/x265|hevc/".
+	// +optional
+	MustContain []string `json:"mustContain,omitempty"`
+
+	// MustNotContain rejects results whose title contains any of these words
+	// or /regular expressions/, e.g. "CAM" or "sample"
+	// +optional
+	MustNotContain []string `json:"mustNotContain,omitempty"`
+
+	// PreferredWords rank results whose title contains them, by the sum of
+	// their weights. Negative weights rank them lower.
+	// +optional
+	PreferredWords []PreferredWord `json:"preferredWords,omitempty"`
+
+	// Filter is a CEL expression results must satisfy, e.g.
+	// "seeders > 5 && sizeBytes < 4 * 1024 * 1024 * 1024 && !title.contains('CAM')".
+	// It sees title, sizeBytes, seeders, leechers, indexer, publishedAt,
+	// quality, formats, formatScore, wordScore and the parsed release
+	// attributes as release (e.g. release.resolution).
+	// +kubebuilder:validation:MaxLength=4096
+	// +optional
+	Filter string `json:"filter,omitempty"`
+
+	// SortBy is a numeric CEL expression ranking results, highest first, e.g.
+	// "seeders * 2 + formatScore". It sees the same variables as Filter; the
+	// default order only breaks its ties.
+	// +kubebuilder:validation:MaxLength=4096
+	// +optional
+	SortBy string `json:"sortBy,omitempty"`
+
 	// Indexers allows specifying specific indexers to use. If empty, uses all healthy public ones.
 	// +optional
 	Indexers []string `json:"indexers,omitempty"`
+
+	// SearchResults is how many of the best ranked results are kept as
+	// SearchResult resources, 20 when unset. 0 keeps none.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	SearchResults *int `json:"searchResults,omitempty"`
+
+	// QualityProfileRef names a QualityProfile in the same namespace ranking
+	// the results. Without one the best seeded result is picked.
+	// +optional
+	QualityProfileRef *corev1.LocalObjectReference `json:"qualityProfileRef,omitempty"`
+
+	// Selection is how the result the Torrent is created from is picked.
+	// "manual" stores the ranked results as SearchResults and waits for
+	// selectedCandidate, or the torrents.vitoru.fun/selected-candidate
+	// annotation, to name one.
+	// +kubebuilder:validation:Enum=automatic;manual
+	// +optional
+	Selection string `json:"selection,omitempty"`
+
+	// SelectedCandidate picks a result of a manual request, by its position
+	// among the SearchResults (1 being the best ranked) or its infohash
+	// +kubebuilder:validation:Pattern=`^([1-9][0-9]*|[0-9a-fA-F]{40})$`
+	// +optional
+	SelectedCandidate string `json:"selectedCandidate,omitempty"`
+
+	// ApprovalTimeout is how long a manual request waits for a selection
+	// before picking the best ranked result itself. Without one it waits
+	// forever.
+	// +optional
+	ApprovalTimeout *metav1.Duration `json:"approvalTimeout,omitempty"`
+
+	// Mode is "once", the default, to search until a Torrent is created or
+	// the request fails, or "monitor" to keep searching every searchInterval
+	// until an acceptable release appears
+	// +kubebuilder:validation:Enum=once;monitor
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// SearchInterval is how often a monitored request searches, 1h when
+	// unset, or 24h when the RSS sync offers it new releases in between
+	// +optional
+	SearchInterval *metav1.Duration `json:"searchInterval,omitempty"`
+
+	// UpgradeUntil keeps a monitored request searching after it created its
+	// Torrent, replacing it whenever a release of a better quality appears,
+	// until one of this quality of the QualityProfile or better is found.
+	// Qualities are named "<source> <resolution>", e.g. "WEB-DL 1080p".
+	// +optional
+	UpgradeUntil string `json:"upgradeUntil,omitempty"`
+
+	// Retry searches again when a search finds nothing acceptable, instead
+	// of failing the request. Without it the request fails after one search.
+	// +optional
+	Retry *RetryPolicy `json:"retry,omitempty"`
+
+	// DownloadClientRef is the DownloadClient the created Torrents are handed
+	// to, the default one of the namespace when unset
+	// +optional
+	DownloadClientRef *corev1.LocalObjectReference `json:"downloadClientRef,omitempty"`
+}
+
+// RetryPolicy is how a request searches again. It gives up once either
+// limit is reached, and retries forever without one.
+type RetryPolicy struct {
+	// MaxAttempts bounds the number of searches, the first one included
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+
+	// Backoff is the delay before the first retry, 5m when unset. It doubles
+	// with each retry, up to a day.
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+
+	// RetryUntil is the time past which the request stops retrying, e.g. a
+	// while after an episode airs
+	// +optional
+	RetryUntil *metav1.Time `json:"retryUntil,omitempty"`
+}
+
+// TVQuery identifies a series, season or episode
+// +kubebuilder:validation:XValidation:rule="!has(self.episode) || has(self.season)",message="episode requires season"
+type TVQuery struct {
+	// Series name
+	Series string `json:"series"`
+
+	// Season number, unset searches the whole series
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Season int `json:"season,omitempty"`
+
+	// Episode number within the season, unset searches the whole season
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Episode int `json:"episode,omitempty"`
+
+	// SeasonPack only accepts releases of the whole season, rather than of
+	// some of its episodes
+	// +optional
+	SeasonPack bool `json:"seasonPack,omitempty"`
+
+	// TVDBID of the series on TheTVDB
+	// +optional
+	TVDBID int `json:"tvdbId,omitempty"`
+
+	// IMDBID of the series on IMDb (e.g. "tt0944947")
+	// +kubebuilder:validation:Pattern=`^tt\d+$`
+	// +optional
+	IMDBID string `json:"imdbId,omitempty"`
+}
+
+// MovieQuery identifies a movie
+type MovieQuery struct {
+	// Title of the movie
+	Title string `json:"title"`
+
+	// Year the movie was released, results mentioning another year are
+	// ignored so remakes are not picked
+	// +kubebuilder:validation:Minimum=1888
+	// +optional
+	Year int `json:"year,omitempty"`
+
+	// IMDBID of the movie on IMDb (e.g. "tt0133093")
+	// +kubebuilder:validation:Pattern=`^tt\d+$`
+	// +optional
+	IMDBID string `json:"imdbId,omitempty"`
+
+	// TMDBID of the movie on TheMovieDB
+	// +optional
+	TMDBID int `json:"tmdbId,omitempty"`
+}
+
+// MusicQuery identifies an artist or album
+// +kubebuilder:validation:XValidation:rule="has(self.artist) || has(self.album)",message="artist or album is required"
+type MusicQuery struct {
+	// Artist name
+	// +optional
+	Artist string `json:"artist,omitempty"`
+
+	// Album title
+	// +optional
+	Album string `json:"album,omitempty"`
+
+	// Label that released the album
+	// +optional
+	Label string `json:"label,omitempty"`
+
+	// Year the album was released, results mentioning another year are
+	// ignored
+	// +optional
+	Year int `json:"year,omitempty"`
+}
+
+// BookQuery identifies a book
+// +kubebuilder:validation:XValidation:rule="has(self.author) || has(self.title)",message="author or title is required"
+type BookQuery struct {
+	// Author name
+	// +optional
+	Author string `json:"author,omitempty"`
+
+	// Title of the book
+	// +optional
+	Title string `json:"title,omitempty"`
+}
+
+// PreferredWord weighs a word or /regular expression/ in result titles
+type PreferredWord struct {
+	// Pattern is a word or a regular expression between slashes
+	// +kubebuilder:validation:MinLength=1
+	Pattern string `json:"pattern"`
+
+	// Weight added to the results containing the pattern
+	Weight int `json:"weight"`
 }
 
 // TorrentRequestStatus defines the observed state of TorrentRequest
 type TorrentRequestStatus struct {
-	// State of the request: "Pending", "Searching", "Completed", "Failed"
+	// State of the request: "Pending", "Searching", "Retrying",
+	// "Monitoring", "AwaitingSelection", "Completed", "Failed"
 	// +optional
 	State string `json:"state,omitempty"`
 
@@ -48,21 +304,116 @@ type TorrentRequestStatus struct {
 	// +optional
 	FoundTorrent string `json:"foundTorrent,omitempty"`
 
+	// Attempts is the number of searches run
+	// +optional
+	Attempts int `json:"attempts,omitempty"`
+
+	// LastSearchTime is when the last search ran
+	// +optional
+	LastSearchTime *metav1.Time `json:"lastSearchTime,omitempty"`
+
+	// NextSearchTime is when a retrying or monitored request searches again
+	// +optional
+	NextSearchTime *metav1.Time `json:"nextSearchTime,omitempty"`
+
+	// SelectionDeadline is when a manual request awaiting selection picks
+	// the best ranked result itself
+	// +optional
+	SelectionDeadline *metav1.Time `json:"selectionDeadline,omitempty"`
+
 	// ResultsFound is the number of results returned by the search
 	// +optional
 	ResultsFound int `json:"resultsFound,omitempty"`
 
+	// BestCandidate is the result the Torrent of a monitored request was
+	// created from, or until then the best ranked result of its last search
+	// +optional
+	BestCandidate *CandidateStatus `json:"bestCandidate,omitempty"`
+
+	// Candidates are the best ranked results, with why each was chosen or
+	// rejected. SearchResult resources list more of them.
+	// +optional
+	Candidates []CandidateStatus `json:"candidates,omitempty"`
+
+	// Eliminations count the results each rule rejected, telling whether the
+	// request failed because of its rules or because nothing was found
+	// +optional
+	Eliminations []Elimination `json:"eliminations,omitempty"`
+
 	// Conditions store the status conditions of the TorrentRequest
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// Elimination is how many results a rule rejected
+type Elimination struct {
+	// Rule that rejected the results, e.g. minSeeders or
+	// mustNotContain "CAM"
+	Rule string `json:"rule"`
+
+	// Count of results the rule rejected
+	Count int `json:"count"`
+}
+
+// CandidateStatus is a search result considered for the request
+type CandidateStatus struct {
+	// Title of the release
+	Title string `json:"title"`
+
+	// Indexer that returned the result
+	// +optional
+	Indexer string `json:"indexer,omitempty"`
+
+	// Quality of the release, e.g. "WEB-DL 1080p"
+	// +optional
+	Quality string `json:"quality,omitempty"`
+
+	// Size of the content
+	// +optional
+	Size string `json:"size,omitempty"`
+
+	// Seeders count at time of discovery
+	// +optional
+	Seeders int `json:"seeders,omitempty"`
+
+	// InfoHash of the torrent, when its magnet link has one
+	// +optional
+	InfoHash string `json:"infoHash,omitempty"`
+
+	// Formats are the CustomFormats the result matched
+	// +optional
+	Formats []string `json:"formats,omitempty"`
+
+	// FormatScore is the sum of the matched CustomFormat scores
+	// +optional
+	FormatScore int `json:"formatScore,omitempty"`
+
+	// WordScore is the sum of the weights of the preferred words the title
+	// contains
+	// +optional
+	WordScore int `json:"wordScore,omitempty"`
+
+	// Chosen is true for the result the Torrent was created from
+	// +optional
+	Chosen bool `json:"chosen,omitempty"`
+
+	// Rejected is true for results the request does not accept
+	// +optional
+	Rejected bool `json:"rejected,omitempty"`
+
+	// Reason explains the rank of the result, or why it was rejected
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
 // +kubebuilder:printcolumn:name="Torrent",type="string",JSONPath=".status.foundTorrent"
 // +kubebuilder:printcolumn:name="Results",type="integer",JSONPath=".status.resultsFound"
+// +kubebuilder:printcolumn:name="Attempts",type="integer",JSONPath=".status.attempts",priority=1
+// +kubebuilder:printcolumn:name="Next Search",type="date",JSONPath=".status.nextSearchTime",priority=1
 // +kubebuilder:resource:shortName=tr
 
 // TorrentRequest is the Schema for the torrentrequests API
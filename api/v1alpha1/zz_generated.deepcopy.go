@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -52,6 +53,41 @@ func (in *BeforeBlock) DeepCopy() *BeforeBlock {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BookQuery) DeepCopyInto(out *BookQuery) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BookQuery.
+func (in *BookQuery) DeepCopy() *BookQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(BookQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CandidateStatus) DeepCopyInto(out *CandidateStatus) {
+	*out = *in
+	if in.Formats != nil {
+		in, out := &in.Formats, &out.Formats
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CandidateStatus.
+func (in *CandidateStatus) DeepCopy() *CandidateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CandidateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Caps) DeepCopyInto(out *Caps) {
 	*out = *in
@@ -111,131 +147,138 @@ func (in *CategoryMapping) DeepCopy() *CategoryMapping {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DownloadBlock) DeepCopyInto(out *DownloadBlock) {
+func (in *ChallengeSolver) DeepCopyInto(out *ChallengeSolver) {
 	*out = *in
-	if in.Before != nil {
-		in, out := &in.Before, &out.Before
-		*out = new(BeforeBlock)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Selectors != nil {
-		in, out := &in.Selectors, &out.Selectors
-		*out = make([]SelectorBlock, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.InfoHash != nil {
-		in, out := &in.InfoHash, &out.InfoHash
-		*out = new(InfoHashBlock)
-		**out = **in
-	}
-	if in.Headers != nil {
-		in, out := &in.Headers, &out.Headers
-		*out = make(map[string][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				inVal := (*in)[key]
-				in, out := &inVal, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DownloadBlock.
-func (in *DownloadBlock) DeepCopy() *DownloadBlock {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChallengeSolver.
+func (in *ChallengeSolver) DeepCopy() *ChallengeSolver {
 	if in == nil {
 		return nil
 	}
-	out := new(DownloadBlock)
+	out := new(ChallengeSolver)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChallengeSolver) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ErrorBlock) DeepCopyInto(out *ErrorBlock) {
+func (in *ChallengeSolverList) DeepCopyInto(out *ChallengeSolverList) {
 	*out = *in
-	in.Message.DeepCopyInto(&out.Message)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ChallengeSolver, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorBlock.
-func (in *ErrorBlock) DeepCopy() *ErrorBlock {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChallengeSolverList.
+func (in *ChallengeSolverList) DeepCopy() *ChallengeSolverList {
 	if in == nil {
 		return nil
 	}
-	out := new(ErrorBlock)
+	out := new(ChallengeSolverList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChallengeSolverList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in FieldsBlock) DeepCopyInto(out *FieldsBlock) {
-	{
-		in := &in
-		*out = make(FieldsBlock, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
+func (in *ChallengeSolverSpec) DeepCopyInto(out *ChallengeSolverSpec) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Indexers != nil {
+		in, out := &in.Indexers, &out.Indexers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IndexerSelector != nil {
+		in, out := &in.IndexerSelector, &out.IndexerSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldsBlock.
-func (in FieldsBlock) DeepCopy() FieldsBlock {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChallengeSolverSpec.
+func (in *ChallengeSolverSpec) DeepCopy() *ChallengeSolverSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(FieldsBlock)
+	out := new(ChallengeSolverSpec)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FilterBlock) DeepCopyInto(out *FilterBlock) {
+func (in *ChallengeSolverStatus) DeepCopyInto(out *ChallengeSolverStatus) {
 	*out = *in
-	if in.Args != nil {
-		in, out := &in.Args, &out.Args
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
+	in.LastChecked.DeepCopyInto(&out.LastChecked)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilterBlock.
-func (in *FilterBlock) DeepCopy() *FilterBlock {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChallengeSolverStatus.
+func (in *ChallengeSolverStatus) DeepCopy() *ChallengeSolverStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(FilterBlock)
+	out := new(ChallengeSolverStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Indexer) DeepCopyInto(out *Indexer) {
+func (in *CustomFormat) DeepCopyInto(out *CustomFormat) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Indexer.
-func (in *Indexer) DeepCopy() *Indexer {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomFormat.
+func (in *CustomFormat) DeepCopy() *CustomFormat {
 	if in == nil {
 		return nil
 	}
-	out := new(Indexer)
+	out := new(CustomFormat)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Indexer) DeepCopyObject() runtime.Object {
+func (in *CustomFormat) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -243,31 +286,31 @@ func (in *Indexer) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexerList) DeepCopyInto(out *IndexerList) {
+func (in *CustomFormatList) DeepCopyInto(out *CustomFormatList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Indexer, len(*in))
+		*out = make([]CustomFormat, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexerList.
-func (in *IndexerList) DeepCopy() *IndexerList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomFormatList.
+func (in *CustomFormatList) DeepCopy() *CustomFormatList {
 	if in == nil {
 		return nil
 	}
-	out := new(IndexerList)
+	out := new(CustomFormatList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IndexerList) DeepCopyObject() runtime.Object {
+func (in *CustomFormatList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -275,139 +318,60 @@ func (in *IndexerList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexerSpec) DeepCopyInto(out *IndexerSpec) {
-	*out = *in
-	if in.Links != nil {
-		in, out := &in.Links, &out.Links
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.LegacyLinks != nil {
-		in, out := &in.LegacyLinks, &out.LegacyLinks
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Certificates != nil {
-		in, out := &in.Certificates, &out.Certificates
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	in.Caps.DeepCopyInto(&out.Caps)
-	if in.Settings != nil {
-		in, out := &in.Settings, &out.Settings
-		*out = make([]SettingsField, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Login != nil {
-		in, out := &in.Login, &out.Login
-		*out = new(Login)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Search != nil {
-		in, out := &in.Search, &out.Search
-		*out = new(Search)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Download != nil {
-		in, out := &in.Download, &out.Download
-		*out = new(DownloadBlock)
-		(*in).DeepCopyInto(*out)
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexerSpec.
-func (in *IndexerSpec) DeepCopy() *IndexerSpec {
-	if in == nil {
-		return nil
-	}
-	out := new(IndexerSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexerStatus) DeepCopyInto(out *IndexerStatus) {
+func (in *CustomFormatSpec) DeepCopyInto(out *CustomFormatSpec) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]FormatCondition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexerStatus.
-func (in *IndexerStatus) DeepCopy() *IndexerStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomFormatSpec.
+func (in *CustomFormatSpec) DeepCopy() *CustomFormatSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IndexerStatus)
+	out := new(CustomFormatSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InfoHashBlock) DeepCopyInto(out *InfoHashBlock) {
+func (in *CustomFormatStatus) DeepCopyInto(out *CustomFormatStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfoHashBlock.
-func (in *InfoHashBlock) DeepCopy() *InfoHashBlock {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomFormatStatus.
+func (in *CustomFormatStatus) DeepCopy() *CustomFormatStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(InfoHashBlock)
+	out := new(CustomFormatStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Login) DeepCopyInto(out *Login) {
+func (in *DownloadBlock) DeepCopyInto(out *DownloadBlock) {
 	*out = *in
-	if in.Cookies != nil {
-		in, out := &in.Cookies, &out.Cookies
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Captcha != nil {
-		in, out := &in.Captcha, &out.Captcha
-		*out = new(CaptchaBlock)
-		**out = **in
-	}
-	if in.Inputs != nil {
-		in, out := &in.Inputs, &out.Inputs
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.SelectorInputs != nil {
-		in, out := &in.SelectorInputs, &out.SelectorInputs
-		*out = make(map[string]SelectorBlock, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.GetSelectorInputs != nil {
-		in, out := &in.GetSelectorInputs, &out.GetSelectorInputs
-		*out = make(map[string]SelectorBlock, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
+	if in.Before != nil {
+		in, out := &in.Before, &out.Before
+		*out = new(BeforeBlock)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Error != nil {
-		in, out := &in.Error, &out.Error
-		*out = make([]ErrorBlock, len(*in))
+	if in.Selectors != nil {
+		in, out := &in.Selectors, &out.Selectors
+		*out = make([]SelectorBlock, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Test != nil {
-		in, out := &in.Test, &out.Test
-		*out = new(PageTestBlock)
+	if in.InfoHash != nil {
+		in, out := &in.InfoHash, &out.InfoHash
+		*out = new(InfoHashBlock)
 		**out = **in
 	}
 	if in.Headers != nil {
@@ -428,310 +392,1557 @@ func (in *Login) DeepCopyInto(out *Login) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Login.
-func (in *Login) DeepCopy() *Login {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DownloadBlock.
+func (in *DownloadBlock) DeepCopy() *DownloadBlock {
 	if in == nil {
 		return nil
 	}
-	out := new(Login)
+	out := new(DownloadBlock)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Modes) DeepCopyInto(out *Modes) {
+func (in *DownloadClient) DeepCopyInto(out *DownloadClient) {
 	*out = *in
-	if in.Search != nil {
-		in, out := &in.Search, &out.Search
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DownloadClient.
+func (in *DownloadClient) DeepCopy() *DownloadClient {
+	if in == nil {
+		return nil
 	}
-	if in.TvSearch != nil {
-		in, out := &in.TvSearch, &out.TvSearch
+	out := new(DownloadClient)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DownloadClient) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DownloadClientList) DeepCopyInto(out *DownloadClientList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DownloadClient, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DownloadClientList.
+func (in *DownloadClientList) DeepCopy() *DownloadClientList {
+	if in == nil {
+		return nil
+	}
+	out := new(DownloadClientList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DownloadClientList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DownloadClientSpec) DeepCopyInto(out *DownloadClientSpec) {
+	*out = *in
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.MovieSearch != nil {
-		in, out := &in.MovieSearch, &out.MovieSearch
+	if in.StalledTimeout != nil {
+		in, out := &in.StalledTimeout, &out.StalledTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DownloadClientSpec.
+func (in *DownloadClientSpec) DeepCopy() *DownloadClientSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DownloadClientSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DownloadClientStatus) DeepCopyInto(out *DownloadClientStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.LastChecked.DeepCopyInto(&out.LastChecked)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DownloadClientStatus.
+func (in *DownloadClientStatus) DeepCopy() *DownloadClientStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DownloadClientStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Elimination) DeepCopyInto(out *Elimination) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Elimination.
+func (in *Elimination) DeepCopy() *Elimination {
+	if in == nil {
+		return nil
+	}
+	out := new(Elimination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EpisodeRange) DeepCopyInto(out *EpisodeRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EpisodeRange.
+func (in *EpisodeRange) DeepCopy() *EpisodeRange {
+	if in == nil {
+		return nil
+	}
+	out := new(EpisodeRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorBlock) DeepCopyInto(out *ErrorBlock) {
+	*out = *in
+	in.Message.DeepCopyInto(&out.Message)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorBlock.
+func (in *ErrorBlock) DeepCopy() *ErrorBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in FieldsBlock) DeepCopyInto(out *FieldsBlock) {
+	{
+		in := &in
+		*out = make(FieldsBlock, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldsBlock.
+func (in FieldsBlock) DeepCopy() FieldsBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldsBlock)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilterBlock) DeepCopyInto(out *FilterBlock) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.MusicSearch != nil {
-		in, out := &in.MusicSearch, &out.MusicSearch
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilterBlock.
+func (in *FilterBlock) DeepCopy() *FilterBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(FilterBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FormatCondition) DeepCopyInto(out *FormatCondition) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(SizeRange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Indexers != nil {
+		in, out := &in.Indexers, &out.Indexers
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.BookSearch != nil {
-		in, out := &in.BookSearch, &out.BookSearch
+	if in.Languages != nil {
+		in, out := &in.Languages, &out.Languages
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Modes.
-func (in *Modes) DeepCopy() *Modes {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FormatCondition.
+func (in *FormatCondition) DeepCopy() *FormatCondition {
 	if in == nil {
 		return nil
 	}
-	out := new(Modes)
+	out := new(FormatCondition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PageTestBlock) DeepCopyInto(out *PageTestBlock) {
+func (in *Indexer) DeepCopyInto(out *Indexer) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PageTestBlock.
-func (in *PageTestBlock) DeepCopy() *PageTestBlock {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Indexer.
+func (in *Indexer) DeepCopy() *Indexer {
 	if in == nil {
 		return nil
 	}
-	out := new(PageTestBlock)
+	out := new(Indexer)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Indexer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResponseBlock) DeepCopyInto(out *ResponseBlock) {
+func (in *IndexerList) DeepCopyInto(out *IndexerList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Indexer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResponseBlock.
-func (in *ResponseBlock) DeepCopy() *ResponseBlock {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexerList.
+func (in *IndexerList) DeepCopy() *IndexerList {
 	if in == nil {
 		return nil
 	}
-	out := new(ResponseBlock)
+	out := new(IndexerList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IndexerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RowFilterBlock) DeepCopyInto(out *RowFilterBlock) {
+func (in *IndexerSpec) DeepCopyInto(out *IndexerSpec) {
 	*out = *in
-	if in.Args != nil {
-		in, out := &in.Args, &out.Args
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LegacyLinks != nil {
+		in, out := &in.LegacyLinks, &out.LegacyLinks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.CABundleSecretRef != nil {
+		in, out := &in.CABundleSecretRef, &out.CABundleSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ProxyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RSS != nil {
+		in, out := &in.RSS, &out.RSS
+		*out = new(RSSSync)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Caps.DeepCopyInto(&out.Caps)
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make([]SettingsField, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Login != nil {
+		in, out := &in.Login, &out.Login
+		*out = new(Login)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Search != nil {
+		in, out := &in.Search, &out.Search
+		*out = new(Search)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Download != nil {
+		in, out := &in.Download, &out.Download
+		*out = new(DownloadBlock)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexerSpec.
+func (in *IndexerSpec) DeepCopy() *IndexerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexerStatus) DeepCopyInto(out *IndexerStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = make([]LinkStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexerStatus.
+func (in *IndexerStatus) DeepCopy() *IndexerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfoHashBlock) DeepCopyInto(out *InfoHashBlock) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfoHashBlock.
+func (in *InfoHashBlock) DeepCopy() *InfoHashBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(InfoHashBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LinkStatus) DeepCopyInto(out *LinkStatus) {
+	*out = *in
+	in.LastChecked.DeepCopyInto(&out.LastChecked)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinkStatus.
+func (in *LinkStatus) DeepCopy() *LinkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LinkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Login) DeepCopyInto(out *Login) {
+	*out = *in
+	if in.Cookies != nil {
+		in, out := &in.Cookies, &out.Cookies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Captcha != nil {
+		in, out := &in.Captcha, &out.Captcha
+		*out = new(CaptchaBlock)
+		**out = **in
+	}
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SelectorInputs != nil {
+		in, out := &in.SelectorInputs, &out.SelectorInputs
+		*out = make(map[string]SelectorBlock, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.GetSelectorInputs != nil {
+		in, out := &in.GetSelectorInputs, &out.GetSelectorInputs
+		*out = make(map[string]SelectorBlock, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Error != nil {
+		in, out := &in.Error, &out.Error
+		*out = make([]ErrorBlock, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Test != nil {
+		in, out := &in.Test, &out.Test
+		*out = new(PageTestBlock)
+		**out = **in
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Login.
+func (in *Login) DeepCopy() *Login {
+	if in == nil {
+		return nil
+	}
+	out := new(Login)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Modes) DeepCopyInto(out *Modes) {
+	*out = *in
+	if in.Search != nil {
+		in, out := &in.Search, &out.Search
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TvSearch != nil {
+		in, out := &in.TvSearch, &out.TvSearch
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MovieSearch != nil {
+		in, out := &in.MovieSearch, &out.MovieSearch
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MusicSearch != nil {
+		in, out := &in.MusicSearch, &out.MusicSearch
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BookSearch != nil {
+		in, out := &in.BookSearch, &out.BookSearch
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Modes.
+func (in *Modes) DeepCopy() *Modes {
+	if in == nil {
+		return nil
+	}
+	out := new(Modes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MovieQuery) DeepCopyInto(out *MovieQuery) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MovieQuery.
+func (in *MovieQuery) DeepCopy() *MovieQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(MovieQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MusicQuery) DeepCopyInto(out *MusicQuery) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MusicQuery.
+func (in *MusicQuery) DeepCopy() *MusicQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(MusicQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PageTestBlock) DeepCopyInto(out *PageTestBlock) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PageTestBlock.
+func (in *PageTestBlock) DeepCopy() *PageTestBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(PageTestBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreferredWord) DeepCopyInto(out *PreferredWord) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreferredWord.
+func (in *PreferredWord) DeepCopy() *PreferredWord {
+	if in == nil {
+		return nil
+	}
+	out := new(PreferredWord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyConfig) DeepCopyInto(out *ProxyConfig) {
+	*out = *in
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyConfig.
+func (in *ProxyConfig) DeepCopy() *ProxyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Quality) DeepCopyInto(out *Quality) {
+	*out = *in
+	if in.MinSize != nil {
+		in, out := &in.MinSize, &out.MinSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Quality.
+func (in *Quality) DeepCopy() *Quality {
+	if in == nil {
+		return nil
+	}
+	out := new(Quality)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QualityProfile) DeepCopyInto(out *QualityProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QualityProfile.
+func (in *QualityProfile) DeepCopy() *QualityProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(QualityProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QualityProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QualityProfileList) DeepCopyInto(out *QualityProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]QualityProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QualityProfileList.
+func (in *QualityProfileList) DeepCopy() *QualityProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(QualityProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QualityProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QualityProfileSpec) DeepCopyInto(out *QualityProfileSpec) {
+	*out = *in
+	if in.Qualities != nil {
+		in, out := &in.Qualities, &out.Qualities
+		*out = make([]Quality, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RequiredWords != nil {
+		in, out := &in.RequiredWords, &out.RequiredWords
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ForbiddenWords != nil {
+		in, out := &in.ForbiddenWords, &out.ForbiddenWords
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreferredGroups != nil {
+		in, out := &in.PreferredGroups, &out.PreferredGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QualityProfileSpec.
+func (in *QualityProfileSpec) DeepCopy() *QualityProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QualityProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QualityProfileStatus) DeepCopyInto(out *QualityProfileStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QualityProfileStatus.
+func (in *QualityProfileStatus) DeepCopy() *QualityProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(QualityProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RSSSync) DeepCopyInto(out *RSSSync) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RSSSync.
+func (in *RSSSync) DeepCopy() *RSSSync {
+	if in == nil {
+		return nil
+	}
+	out := new(RSSSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseInfo) DeepCopyInto(out *ReleaseInfo) {
+	*out = *in
+	if in.Seasons != nil {
+		in, out := &in.Seasons, &out.Seasons
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.Episodes != nil {
+		in, out := &in.Episodes, &out.Episodes
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.Audio != nil {
+		in, out := &in.Audio, &out.Audio
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HDR != nil {
+		in, out := &in.HDR, &out.HDR
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Languages != nil {
+		in, out := &in.Languages, &out.Languages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseInfo.
+func (in *ReleaseInfo) DeepCopy() *ReleaseInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResponseBlock) DeepCopyInto(out *ResponseBlock) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResponseBlock.
+func (in *ResponseBlock) DeepCopy() *ResponseBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(ResponseBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RetryUntil != nil {
+		in, out := &in.RetryUntil, &out.RetryUntil
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RowFilterBlock) DeepCopyInto(out *RowFilterBlock) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RowFilterBlock.
+func (in *RowFilterBlock) DeepCopy() *RowFilterBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(RowFilterBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RowsBlock) DeepCopyInto(out *RowsBlock) {
+	*out = *in
+	in.DateHeaders.DeepCopyInto(&out.DateHeaders)
+	if in.Case != nil {
+		in, out := &in.Case, &out.Case
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = make([]RowFilterBlock, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Count.DeepCopyInto(&out.Count)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RowsBlock.
+func (in *RowsBlock) DeepCopy() *RowsBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(RowsBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledTorrentRequest) DeepCopyInto(out *ScheduledTorrentRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledTorrentRequest.
+func (in *ScheduledTorrentRequest) DeepCopy() *ScheduledTorrentRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledTorrentRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledTorrentRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledTorrentRequestList) DeepCopyInto(out *ScheduledTorrentRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ScheduledTorrentRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledTorrentRequestList.
+func (in *ScheduledTorrentRequestList) DeepCopy() *ScheduledTorrentRequestList {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledTorrentRequestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledTorrentRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledTorrentRequestSpec) DeepCopyInto(out *ScheduledTorrentRequestSpec) {
+	*out = *in
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SuccessfulRequestsHistoryLimit != nil {
+		in, out := &in.SuccessfulRequestsHistoryLimit, &out.SuccessfulRequestsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedRequestsHistoryLimit != nil {
+		in, out := &in.FailedRequestsHistoryLimit, &out.FailedRequestsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	in.RequestTemplate.DeepCopyInto(&out.RequestTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledTorrentRequestSpec.
+func (in *ScheduledTorrentRequestSpec) DeepCopy() *ScheduledTorrentRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledTorrentRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledTorrentRequestStatus) DeepCopyInto(out *ScheduledTorrentRequestStatus) {
+	*out = *in
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]corev1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledTorrentRequestStatus.
+func (in *ScheduledTorrentRequestStatus) DeepCopy() *ScheduledTorrentRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledTorrentRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Search) DeepCopyInto(out *Search) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]SearchPathBlock, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.KeywordsFilters != nil {
+		in, out := &in.KeywordsFilters, &out.KeywordsFilters
+		*out = make([]FilterBlock, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Error != nil {
+		in, out := &in.Error, &out.Error
+		*out = make([]ErrorBlock, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreprocessingFilters != nil {
+		in, out := &in.PreprocessingFilters, &out.PreprocessingFilters
+		*out = make([]FilterBlock, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Rows.DeepCopyInto(&out.Rows)
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make(FieldsBlock, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Search.
+func (in *Search) DeepCopy() *Search {
+	if in == nil {
+		return nil
+	}
+	out := new(Search)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SearchPathBlock) DeepCopyInto(out *SearchPathBlock) {
+	*out = *in
+	if in.Categories != nil {
+		in, out := &in.Categories, &out.Categories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Response != nil {
+		in, out := &in.Response, &out.Response
+		*out = new(ResponseBlock)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SearchPathBlock.
+func (in *SearchPathBlock) DeepCopy() *SearchPathBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(SearchPathBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SearchResult) DeepCopyInto(out *SearchResult) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SearchResult.
+func (in *SearchResult) DeepCopy() *SearchResult {
+	if in == nil {
+		return nil
+	}
+	out := new(SearchResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SearchResult) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SearchResultList) DeepCopyInto(out *SearchResultList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SearchResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SearchResultList.
+func (in *SearchResultList) DeepCopy() *SearchResultList {
+	if in == nil {
+		return nil
+	}
+	out := new(SearchResultList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SearchResultList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SearchResultSpec) DeepCopyInto(out *SearchResultSpec) {
+	*out = *in
+	in.CandidateStatus.DeepCopyInto(&out.CandidateStatus)
+	if in.PublishedAt != nil {
+		in, out := &in.PublishedAt, &out.PublishedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Release != nil {
+		in, out := &in.Release, &out.Release
+		*out = new(ReleaseInfo)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SearchResultSpec.
+func (in *SearchResultSpec) DeepCopy() *SearchResultSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SearchResultSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SearchResultStatus) DeepCopyInto(out *SearchResultStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SearchResultStatus.
+func (in *SearchResultStatus) DeepCopy() *SearchResultStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SearchResultStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeasonRequest) DeepCopyInto(out *SeasonRequest) {
+	*out = *in
+	if in.Episodes != nil {
+		in, out := &in.Episodes, &out.Episodes
+		*out = make([]EpisodeRange, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeasonRequest.
+func (in *SeasonRequest) DeepCopy() *SeasonRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(SeasonRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelectorBlock) DeepCopyInto(out *SelectorBlock) {
+	*out = *in
+	if in.Case != nil {
+		in, out := &in.Case, &out.Case
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = make([]FilterBlock, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelectorBlock.
+func (in *SelectorBlock) DeepCopy() *SelectorBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(SelectorBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelectorField) DeepCopyInto(out *SelectorField) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelectorField.
+func (in *SelectorField) DeepCopy() *SelectorField {
+	if in == nil {
+		return nil
+	}
+	out := new(SelectorField)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeriesRequest) DeepCopyInto(out *SeriesRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeriesRequest.
+func (in *SeriesRequest) DeepCopy() *SeriesRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(SeriesRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SeriesRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeriesRequestChild) DeepCopyInto(out *SeriesRequestChild) {
+	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RowFilterBlock.
-func (in *RowFilterBlock) DeepCopy() *RowFilterBlock {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeriesRequestChild.
+func (in *SeriesRequestChild) DeepCopy() *SeriesRequestChild {
 	if in == nil {
 		return nil
 	}
-	out := new(RowFilterBlock)
+	out := new(SeriesRequestChild)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RowsBlock) DeepCopyInto(out *RowsBlock) {
+func (in *SeriesRequestList) DeepCopyInto(out *SeriesRequestList) {
 	*out = *in
-	in.DateHeaders.DeepCopyInto(&out.DateHeaders)
-	if in.Case != nil {
-		in, out := &in.Case, &out.Case
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Filters != nil {
-		in, out := &in.Filters, &out.Filters
-		*out = make([]RowFilterBlock, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SeriesRequest, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	in.Count.DeepCopyInto(&out.Count)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RowsBlock.
-func (in *RowsBlock) DeepCopy() *RowsBlock {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeriesRequestList.
+func (in *SeriesRequestList) DeepCopy() *SeriesRequestList {
 	if in == nil {
 		return nil
 	}
-	out := new(RowsBlock)
+	out := new(SeriesRequestList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SeriesRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Search) DeepCopyInto(out *Search) {
+func (in *SeriesRequestSpec) DeepCopyInto(out *SeriesRequestSpec) {
 	*out = *in
-	if in.Paths != nil {
-		in, out := &in.Paths, &out.Paths
-		*out = make([]SearchPathBlock, len(*in))
+	if in.Seasons != nil {
+		in, out := &in.Seasons, &out.Seasons
+		*out = make([]SeasonRequest, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Inputs != nil {
-		in, out := &in.Inputs, &out.Inputs
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Headers != nil {
-		in, out := &in.Headers, &out.Headers
-		*out = make(map[string][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				inVal := (*in)[key]
-				in, out := &inVal, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
-	}
-	if in.KeywordsFilters != nil {
-		in, out := &in.KeywordsFilters, &out.KeywordsFilters
-		*out = make([]FilterBlock, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeriesRequestSpec.
+func (in *SeriesRequestSpec) DeepCopy() *SeriesRequestSpec {
+	if in == nil {
+		return nil
 	}
-	if in.Error != nil {
-		in, out := &in.Error, &out.Error
-		*out = make([]ErrorBlock, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out := new(SeriesRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeriesRequestStatus) DeepCopyInto(out *SeriesRequestStatus) {
+	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make([]SeriesRequestChild, len(*in))
+		copy(*out, *in)
 	}
-	if in.PreprocessingFilters != nil {
-		in, out := &in.PreprocessingFilters, &out.PreprocessingFilters
-		*out = make([]FilterBlock, len(*in))
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	in.Rows.DeepCopyInto(&out.Rows)
-	if in.Fields != nil {
-		in, out := &in.Fields, &out.Fields
-		*out = make(FieldsBlock, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Search.
-func (in *Search) DeepCopy() *Search {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeriesRequestStatus.
+func (in *SeriesRequestStatus) DeepCopy() *SeriesRequestStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Search)
+	out := new(SeriesRequestStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SearchPathBlock) DeepCopyInto(out *SearchPathBlock) {
+func (in *SeriesRequestTemplate) DeepCopyInto(out *SeriesRequestTemplate) {
 	*out = *in
-	if in.Categories != nil {
-		in, out := &in.Categories, &out.Categories
+	if in.Indexers != nil {
+		in, out := &in.Indexers, &out.Indexers
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Inputs != nil {
-		in, out := &in.Inputs, &out.Inputs
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.QualityProfileRef != nil {
+		in, out := &in.QualityProfileRef, &out.QualityProfileRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
 	}
-	if in.Response != nil {
-		in, out := &in.Response, &out.Response
-		*out = new(ResponseBlock)
+	if in.MustContain != nil {
+		in, out := &in.MustContain, &out.MustContain
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MustNotContain != nil {
+		in, out := &in.MustNotContain, &out.MustNotContain
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreferredWords != nil {
+		in, out := &in.PreferredWords, &out.PreferredWords
+		*out = make([]PreferredWord, len(*in))
+		copy(*out, *in)
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SearchInterval != nil {
+		in, out := &in.SearchInterval, &out.SearchInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DownloadClientRef != nil {
+		in, out := &in.DownloadClientRef, &out.DownloadClientRef
+		*out = new(corev1.LocalObjectReference)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SearchPathBlock.
-func (in *SearchPathBlock) DeepCopy() *SearchPathBlock {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeriesRequestTemplate.
+func (in *SeriesRequestTemplate) DeepCopy() *SeriesRequestTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(SearchPathBlock)
+	out := new(SeriesRequestTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SelectorBlock) DeepCopyInto(out *SelectorBlock) {
+func (in *SettingsField) DeepCopyInto(out *SettingsField) {
 	*out = *in
-	if in.Case != nil {
-		in, out := &in.Case, &out.Case
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.Filters != nil {
-		in, out := &in.Filters, &out.Filters
-		*out = make([]FilterBlock, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelectorBlock.
-func (in *SelectorBlock) DeepCopy() *SelectorBlock {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SettingsField.
+func (in *SettingsField) DeepCopy() *SettingsField {
 	if in == nil {
 		return nil
 	}
-	out := new(SelectorBlock)
+	out := new(SettingsField)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SelectorField) DeepCopyInto(out *SelectorField) {
+func (in *SizeRange) DeepCopyInto(out *SizeRange) {
 	*out = *in
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelectorField.
-func (in *SelectorField) DeepCopy() *SelectorField {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SizeRange.
+func (in *SizeRange) DeepCopy() *SizeRange {
 	if in == nil {
 		return nil
 	}
-	out := new(SelectorField)
+	out := new(SizeRange)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SettingsField) DeepCopyInto(out *SettingsField) {
+func (in *TVQuery) DeepCopyInto(out *TVQuery) {
 	*out = *in
-	if in.Options != nil {
-		in, out := &in.Options, &out.Options
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Defaults != nil {
-		in, out := &in.Defaults, &out.Defaults
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SettingsField.
-func (in *SettingsField) DeepCopy() *SettingsField {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TVQuery.
+func (in *TVQuery) DeepCopy() *TVQuery {
 	if in == nil {
 		return nil
 	}
-	out := new(SettingsField)
+	out := new(TVQuery)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -742,7 +1953,7 @@ func (in *Torrent) DeepCopyInto(out *Torrent) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Torrent.
@@ -857,11 +2068,76 @@ func (in *TorrentRequestList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TorrentRequestSpec) DeepCopyInto(out *TorrentRequestSpec) {
 	*out = *in
+	if in.TV != nil {
+		in, out := &in.TV, &out.TV
+		*out = new(TVQuery)
+		**out = **in
+	}
+	if in.Movie != nil {
+		in, out := &in.Movie, &out.Movie
+		*out = new(MovieQuery)
+		**out = **in
+	}
+	if in.Music != nil {
+		in, out := &in.Music, &out.Music
+		*out = new(MusicQuery)
+		**out = **in
+	}
+	if in.Book != nil {
+		in, out := &in.Book, &out.Book
+		*out = new(BookQuery)
+		**out = **in
+	}
+	if in.MustContain != nil {
+		in, out := &in.MustContain, &out.MustContain
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MustNotContain != nil {
+		in, out := &in.MustNotContain, &out.MustNotContain
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreferredWords != nil {
+		in, out := &in.PreferredWords, &out.PreferredWords
+		*out = make([]PreferredWord, len(*in))
+		copy(*out, *in)
+	}
 	if in.Indexers != nil {
 		in, out := &in.Indexers, &out.Indexers
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.SearchResults != nil {
+		in, out := &in.SearchResults, &out.SearchResults
+		*out = new(int)
+		**out = **in
+	}
+	if in.QualityProfileRef != nil {
+		in, out := &in.QualityProfileRef, &out.QualityProfileRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ApprovalTimeout != nil {
+		in, out := &in.ApprovalTimeout, &out.ApprovalTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SearchInterval != nil {
+		in, out := &in.SearchInterval, &out.SearchInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DownloadClientRef != nil {
+		in, out := &in.DownloadClientRef, &out.DownloadClientRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TorrentRequestSpec.
@@ -877,6 +2153,35 @@ func (in *TorrentRequestSpec) DeepCopy() *TorrentRequestSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TorrentRequestStatus) DeepCopyInto(out *TorrentRequestStatus) {
 	*out = *in
+	if in.LastSearchTime != nil {
+		in, out := &in.LastSearchTime, &out.LastSearchTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextSearchTime != nil {
+		in, out := &in.NextSearchTime, &out.NextSearchTime
+		*out = (*in).DeepCopy()
+	}
+	if in.SelectionDeadline != nil {
+		in, out := &in.SelectionDeadline, &out.SelectionDeadline
+		*out = (*in).DeepCopy()
+	}
+	if in.BestCandidate != nil {
+		in, out := &in.BestCandidate, &out.BestCandidate
+		*out = new(CandidateStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Candidates != nil {
+		in, out := &in.Candidates, &out.Candidates
+		*out = make([]CandidateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Eliminations != nil {
+		in, out := &in.Eliminations, &out.Eliminations
+		*out = make([]Elimination, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -896,6 +2201,36 @@ func (in *TorrentRequestStatus) DeepCopy() *TorrentRequestStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TorrentRequestTemplate) DeepCopyInto(out *TorrentRequestTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TorrentRequestTemplate.
+func (in *TorrentRequestTemplate) DeepCopy() *TorrentRequestTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(TorrentRequestTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TorrentSpec) DeepCopyInto(out *TorrentSpec) {
 	*out = *in
@@ -903,6 +2238,16 @@ func (in *TorrentSpec) DeepCopyInto(out *TorrentSpec) {
 		in, out := &in.PublishedAt, &out.PublishedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.Release != nil {
+		in, out := &in.Release, &out.Release
+		*out = new(ReleaseInfo)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DownloadClientRef != nil {
+		in, out := &in.DownloadClientRef, &out.DownloadClientRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TorrentSpec.
@@ -918,6 +2263,30 @@ func (in *TorrentSpec) DeepCopy() *TorrentSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TorrentStatus) DeepCopyInto(out *TorrentStatus) {
 	*out = *in
+	if in.AddedAt != nil {
+		in, out := &in.AddedAt, &out.AddedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ETA != nil {
+		in, out := &in.ETA, &out.ETA
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastActivityTime != nil {
+		in, out := &in.LastActivityTime, &out.LastActivityTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TorrentStatus.
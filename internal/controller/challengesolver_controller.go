@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/flaresolverr"
+	"vitoru.fun/torrents/internal/solver"
+)
+
+// challengeSolverCheckInterval is how often solvers are health checked
+const challengeSolverCheckInterval = 5 * time.Minute
+
+// ChallengeSolverReconciler registers ChallengeSolver resources with the
+// solver registry shared with the indexer controllers and health checks them
+type ChallengeSolverReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Solvers is the registry indexers look their solver up in
+	Solvers *solver.Registry
+
+	mu    sync.Mutex
+	built map[string]builtSolver
+}
+
+// builtSolver remembers the spec generation a solver was built from, so
+// browser sessions survive reconciles that do not change the spec
+type builtSolver struct {
+	generation int64
+	solver     solver.Solver
+}
+
+// +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=challengesolvers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=challengesolvers/status,verbs=get;update;patch
+
+func (r *ChallengeSolverReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	var cs torrentsv1alpha1.ChallengeSolver
+	if err := r.Get(ctx, req.NamespacedName, &cs); err != nil {
+		if errors.IsNotFound(err) {
+			r.Solvers.Remove(req.Name)
+			if s := r.forget(req.Name); s != nil {
+				if err := s.DestroyAll(ctx); err != nil {
+					l.Error(err, "Failed to destroy challenge solver sessions", "solver", req.Name)
+				}
+			}
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	s, err := r.solver(ctx, &cs)
+	if err != nil {
+		r.Solvers.Remove(cs.Name)
+		return ctrl.Result{}, r.setReady(ctx, &cs, metav1.ConditionFalse, "InvalidSpec", err.Error())
+	}
+
+	selector := labels.Nothing()
+	if cs.Spec.IndexerSelector != nil {
+		selector, err = metav1.LabelSelectorAsSelector(cs.Spec.IndexerSelector)
+		if err != nil {
+			r.Solvers.Remove(cs.Name)
+			return ctrl.Result{}, r.setReady(ctx, &cs, metav1.ConditionFalse, "InvalidSpec", fmt.Sprintf("invalid indexer selector: %v", err))
+		}
+	}
+
+	pingErr := s.Ping(ctx)
+	r.Solvers.Set(cs.Name, &solver.Entry{
+		Solver:   s,
+		Indexers: cs.Spec.Indexers,
+		Selector: selector,
+		Ready:    pingErr == nil,
+	})
+
+	if pingErr != nil {
+		l.Info("Challenge solver health check failed", "solver", cs.Name, "error", pingErr.Error())
+		err = r.setReady(ctx, &cs, metav1.ConditionFalse, "HealthCheckFailed", pingErr.Error())
+	} else {
+		err = r.setReady(ctx, &cs, metav1.ConditionTrue, "HealthCheckSucceeded", "Challenge solver is reachable")
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: challengeSolverCheckInterval}, nil
+}
+
+// solver returns the solver built from the current spec, replacing the one
+// built from a previous generation
+func (r *ChallengeSolverReconciler) solver(ctx context.Context, cs *torrentsv1alpha1.ChallengeSolver) (solver.Solver, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if b, ok := r.built[cs.Name]; ok && b.generation == cs.Generation {
+		return b.solver, nil
+	}
+
+	s, err := newSolver(cs.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if old, ok := r.built[cs.Name]; ok {
+		// Sessions of the old endpoint are of no use anymore
+		_ = old.solver.DestroyAll(ctx)
+	}
+	if r.built == nil {
+		r.built = make(map[string]builtSolver)
+	}
+	r.built[cs.Name] = builtSolver{generation: cs.Generation, solver: s}
+	return s, nil
+}
+
+func (r *ChallengeSolverReconciler) forget(name string) solver.Solver {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.built[name]
+	if !ok {
+		return nil
+	}
+	delete(r.built, name)
+	return b.solver
+}
+
+// newSolver builds the solver implementation for the spec type
+func newSolver(spec torrentsv1alpha1.ChallengeSolverSpec) (solver.Solver, error) {
+	switch spec.Type {
+	case "", torrentsv1alpha1.ChallengeSolverFlareSolverr:
+		fsClient := flaresolverr.NewClient(spec.Endpoint, nil)
+		if spec.Timeout != nil && spec.Timeout.Duration > 0 {
+			fsClient.MaxTimeout = spec.Timeout.Duration
+		}
+		// Leave FlareSolverr time to answer once it gives up on a challenge
+		fsClient.HTTPClient = &http.Client{Timeout: fsClient.MaxTimeout + 30*time.Second}
+		sessions := flaresolverr.NewSessions(fsClient)
+		sessions.MaxSessions = spec.MaxSessions
+		return sessions, nil
+	default:
+		return nil, fmt.Errorf("unsupported challenge solver type %q", spec.Type)
+	}
+}
+
+func (r *ChallengeSolverReconciler) setReady(ctx context.Context, cs *torrentsv1alpha1.ChallengeSolver, status metav1.ConditionStatus, reason, message string) error {
+	apimeta.SetStatusCondition(&cs.Status.Conditions, metav1.Condition{
+		Type:    "Ready",
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	cs.Status.LastChecked = metav1.Now()
+	if err := r.Status().Update(ctx, cs); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update ChallengeSolver status")
+		return err
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ChallengeSolverReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// Status updates would trigger a health check each
+		For(&torrentsv1alpha1.ChallengeSolver{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/downloadclient"
+)
+
+// downloadClientCheckInterval is how often download clients are health checked
+const downloadClientCheckInterval = 5 * time.Minute
+
+// DownloadClientReconciler registers DownloadClient resources with the
+// client registry shared with the Torrent controller and health checks them
+type DownloadClientReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Clients is the registry Torrents look their download client up in
+	Clients *downloadclient.Registry
+
+	mu    sync.Mutex
+	built map[types.NamespacedName]builtDownloadClient
+}
+
+// builtDownloadClient remembers what a client was built from, so its session
+// survives reconciles that change neither the spec nor the credentials
+type builtDownloadClient struct {
+	version string
+	client  downloadclient.Client
+}
+
+// +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=downloadclients,verbs=get;list;watch
+// +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=downloadclients/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+func (r *DownloadClientReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	var dc torrentsv1alpha1.DownloadClient
+	if err := r.Get(ctx, req.NamespacedName, &dc); err != nil {
+		if errors.IsNotFound(err) {
+			r.Clients.Remove(req.NamespacedName)
+			r.forget(req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	c, err := r.downloadClient(ctx, &dc)
+	if err != nil {
+		r.Clients.Remove(req.NamespacedName)
+		// Credentials may show up later
+		if err := r.setReady(ctx, &dc, metav1.ConditionFalse, "InvalidSpec", err.Error()); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: downloadClientCheckInterval}, nil
+	}
+
+	version, checkErr := c.Version(ctx)
+	r.Clients.Set(req.NamespacedName, &downloadclient.Entry{Client: c, Ready: checkErr == nil})
+
+	if checkErr != nil {
+		l.Info("Download client health check failed", "downloadClient", dc.Name, "error", checkErr.Error())
+		err = r.setReady(ctx, &dc, metav1.ConditionFalse, "HealthCheckFailed", checkErr.Error())
+	} else {
+		dc.Status.Version = version
+		err = r.setReady(ctx, &dc, metav1.ConditionTrue, "HealthCheckSucceeded", fmt.Sprintf("%s %s is reachable", dc.Spec.Type, version))
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: downloadClientCheckInterval}, nil
+}
+
+// downloadClient returns the client built from the current spec and
+// credentials, replacing the one built from previous ones
+func (r *DownloadClientReconciler) downloadClient(ctx context.Context, dc *torrentsv1alpha1.DownloadClient) (downloadclient.Client, error) {
+	// Better refused upfront than when the first Torrent is deleted
+	if dc.Spec.RemoveData && dc.Spec.Type == torrentsv1alpha1.DownloadClientRTorrent {
+		return nil, fmt.Errorf("removeData is not supported by rtorrent: %w", downloadclient.ErrRemoveDataUnsupported)
+	}
+	cfg := downloadclient.Config{
+		URL:        dc.Spec.URL,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	version := fmt.Sprint(dc.Generation)
+	if ref := dc.Spec.CredentialsSecretRef; ref != nil {
+		var secret corev1.Secret
+		if err := r.Get(ctx, types.NamespacedName{Namespace: dc.Namespace, Name: ref.Name}, &secret); err != nil {
+			return nil, fmt.Errorf("failed to read credentials: %w", err)
+		}
+		// Deluge only takes the password of its web UI
+		username, password, err := secretCredentials(&secret, dc.Spec.Type != torrentsv1alpha1.DownloadClientDeluge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid credentials: %w", err)
+		}
+		cfg.Username, cfg.Password = username, password
+		version += "/" + secret.ResourceVersion
+	}
+
+	key := client.ObjectKeyFromObject(dc)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if b, ok := r.built[key]; ok && b.version == version {
+		return b.client, nil
+	}
+	c, err := downloadclient.New(dc.Spec.Type, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if r.built == nil {
+		r.built = make(map[types.NamespacedName]builtDownloadClient)
+	}
+	r.built[key] = builtDownloadClient{version: version, client: c}
+	return c, nil
+}
+
+func (r *DownloadClientReconciler) forget(key types.NamespacedName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.built, key)
+}
+
+func (r *DownloadClientReconciler) setReady(ctx context.Context, dc *torrentsv1alpha1.DownloadClient, status metav1.ConditionStatus, reason, message string) error {
+	apimeta.SetStatusCondition(&dc.Status.Conditions, metav1.Condition{
+		Type:    "Ready",
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	dc.Status.LastChecked = metav1.Now()
+	if err := r.Status().Update(ctx, dc); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update DownloadClient status")
+		return err
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DownloadClientReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// Status updates would trigger a health check each
+		For(&torrentsv1alpha1.DownloadClient{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
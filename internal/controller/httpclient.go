@@ -0,0 +1,287 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/httpclient"
+	"vitoru.fun/torrents/internal/solver"
+)
+
+// userAgent is sent with direct requests, as some trackers require one
+const userAgent = "Prowlarr/1.0 (Text-Mode-Operator)"
+
+// indexerClients builds and caches the HTTP client used for each indexer, so
+// indexers with custom TLS trust or proxies keep pooling connections across
+// reconciles, and fetches indexer pages through a challenge solver when needed.
+type indexerClients struct {
+	reader       client.Reader
+	base         *http.Client
+	defaultProxy string
+	solvers      *solver.Registry
+
+	mu      sync.Mutex
+	clients map[types.NamespacedName]cachedClient
+}
+
+// newIndexerClients returns a client cache deriving clients from base.
+// defaultProxy is the operator wide proxy used when the indexer does not
+// configure its own and solvers may be nil when no solver is deployed.
+func newIndexerClients(reader client.Reader, base *http.Client, defaultProxy string, solvers *solver.Registry) *indexerClients {
+	return &indexerClients{
+		reader:       reader,
+		base:         base,
+		defaultProxy: defaultProxy,
+		solvers:      solvers,
+	}
+}
+
+// pageRequest is a single page fetched from an indexer
+type pageRequest struct {
+	// Method is GET (default) or POST
+	Method string
+	URL    string
+	// Form is sent url encoded as the body of POST requests
+	Form url.Values
+}
+
+// fetch retrieves a page from the indexer, through a challenge solver when
+// the indexer uses one, and fails on non 2xx responses.
+func (c *indexerClients) fetch(ctx context.Context, indexer *torrentsv1alpha1.Indexer, page pageRequest) ([]byte, error) {
+	httpClient, err := c.get(ctx, indexer)
+	if err != nil {
+		return nil, err
+	}
+
+	method := strings.ToUpper(page.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	s, err := c.solverFor(indexer)
+	if err != nil {
+		return nil, err
+	}
+	if s != nil {
+		proxy, err := indexerProxy(ctx, c.reader, indexer, c.defaultProxy)
+		if err != nil {
+			return nil, err
+		}
+		return s.Do(ctx, indexerKey(indexer), httpClient, solver.Request{
+			Method:   method,
+			URL:      page.URL,
+			PostData: page.Form.Encode(),
+			Proxy:    proxy,
+		})
+	}
+
+	var body io.Reader
+	if method == http.MethodPost {
+		body = strings.NewReader(page.Form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, page.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("HTTP Status: %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// solverFor returns the challenge solver requests to the indexer go through,
+// or nil for direct requests. A ChallengeSolver selecting the indexer takes
+// precedence; otherwise definitions signal they need the default solver with
+// an info_flaresolverr setting.
+func (c *indexerClients) solverFor(indexer *torrentsv1alpha1.Indexer) (solver.Solver, error) {
+	if c.solvers == nil {
+		return nil, nil
+	}
+	if name, entry, ok := c.solvers.Lookup(indexer.Namespace, indexer.Name, indexer.Labels); ok {
+		if !entry.Ready {
+			return nil, fmt.Errorf("%w: %s", solver.ErrNotReady, name)
+		}
+		return entry.Solver, nil
+	}
+	if c.solvers.Default == nil {
+		return nil, nil
+	}
+	for _, setting := range indexer.Spec.Settings {
+		if setting.Type == "info_flaresolverr" {
+			return c.solvers.Default, nil
+		}
+	}
+	return nil, nil
+}
+
+// indexerKey identifies the indexer in per-indexer caches
+func indexerKey(indexer *torrentsv1alpha1.Indexer) string {
+	return types.NamespacedName{Namespace: indexer.Namespace, Name: indexer.Name}.String()
+}
+
+type cachedClient struct {
+	key    string
+	client *http.Client
+}
+
+// get returns the client for the indexer, falling back to the base client
+// when the indexer needs nothing special.
+func (c *indexerClients) get(ctx context.Context, indexer *torrentsv1alpha1.Indexer) (*http.Client, error) {
+	opts, err := indexerHTTPOptions(ctx, c.reader, indexer, c.defaultProxy)
+	if err != nil {
+		return nil, err
+	}
+	if opts.IsZero() {
+		return c.base, nil
+	}
+
+	name := types.NamespacedName{Namespace: indexer.Namespace, Name: indexer.Name}
+	key := optionsKey(opts)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.clients[name]
+	if ok && cached.key == key {
+		return cached.client, nil
+	}
+
+	hc, err := httpclient.New(c.base, opts)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		cached.client.CloseIdleConnections()
+	}
+	if c.clients == nil {
+		c.clients = make(map[types.NamespacedName]cachedClient)
+	}
+	c.clients[name] = cachedClient{key: key, client: hc}
+	return hc, nil
+}
+
+// indexerHTTPOptions collects the transport settings of the indexer,
+// resolving the Secrets it references.
+func indexerHTTPOptions(ctx context.Context, reader client.Reader, indexer *torrentsv1alpha1.Indexer, defaultProxy string) (httpclient.Options, error) {
+	opts := httpclient.Options{
+		Certificates: indexer.Spec.Certificates,
+	}
+
+	proxy, err := indexerProxy(ctx, reader, indexer, defaultProxy)
+	if err != nil {
+		return opts, err
+	}
+	opts.Proxy = proxy
+
+	if ref := indexer.Spec.CABundleSecretRef; ref != nil {
+		bundle, err := secretValue(ctx, reader, indexer.Namespace, ref)
+		if err != nil {
+			return opts, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		opts.CABundle = bundle
+	}
+
+	return opts, nil
+}
+
+// indexerProxy resolves the proxy requests to the indexer go through, if any
+func indexerProxy(ctx context.Context, reader client.Reader, indexer *torrentsv1alpha1.Indexer, defaultProxy string) (*url.URL, error) {
+	raw := defaultProxy
+	if indexer.Spec.Proxy != nil {
+		raw = indexer.Spec.Proxy.URL
+	}
+	if raw == "" {
+		return nil, nil
+	}
+
+	proxy, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	if p := indexer.Spec.Proxy; p != nil && p.CredentialsSecretRef != nil {
+		var secret corev1.Secret
+		key := types.NamespacedName{Namespace: indexer.Namespace, Name: p.CredentialsSecretRef.Name}
+		if err := reader.Get(ctx, key, &secret); err != nil {
+			return nil, fmt.Errorf("failed to read proxy credentials: %w", err)
+		}
+		username, password, err := secretCredentials(&secret, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy credentials: %w", err)
+		}
+		proxy.User = url.UserPassword(username, password)
+	}
+
+	return proxy, nil
+}
+
+// secretCredentials returns the "username" and "password" keys of a
+// credentials Secret, failing when one is missing rather than sending empty
+// credentials. withUsername is false for clients only taking a password.
+func secretCredentials(secret *corev1.Secret, withUsername bool) (string, string, error) {
+	keys := []string{"password"}
+	if withUsername {
+		keys = []string{"username", "password"}
+	}
+	for _, k := range keys {
+		if _, ok := secret.Data[k]; !ok {
+			return "", "", fmt.Errorf("key %q not found in secret %s/%s", k, secret.Namespace, secret.Name)
+		}
+	}
+	return string(secret.Data["username"]), string(secret.Data["password"]), nil
+}
+
+// secretValue reads a single key from a Secret in the given namespace
+func secretValue(ctx context.Context, reader client.Reader, namespace string, ref *corev1.SecretKeySelector) ([]byte, error) {
+	var secret corev1.Secret
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, &secret); err != nil {
+		return nil, err
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("key %q not found in secret %s/%s", ref.Key, namespace, ref.Name)
+	}
+	return value, nil
+}
+
+// optionsKey fingerprints the options so cached clients are rebuilt when the
+// indexer spec or the referenced Secrets change.
+func optionsKey(opts httpclient.Options) string {
+	certs := append([]string(nil), opts.Certificates...)
+	sort.Strings(certs)
+
+	h := sha256.New()
+	for _, c := range certs {
+		h.Write([]byte(c))
+		h.Write([]byte{0})
+	}
+	h.Write(opts.CABundle)
+	if opts.Proxy != nil {
+		h.Write([]byte(opts.Proxy.String()))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("secretCredentials", func() {
+	secret := func(data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default"}, Data: data}
+	}
+
+	It("reads the username and password", func() {
+		username, password, err := secretCredentials(secret(map[string][]byte{"username": []byte("admin"), "password": []byte("secret")}), true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(username).To(Equal("admin"))
+		Expect(password).To(Equal("secret"))
+	})
+
+	It("fails on missing keys", func() {
+		_, _, err := secretCredentials(secret(map[string][]byte{"user": []byte("admin"), "password": []byte("secret")}), true)
+		Expect(err).To(MatchError(`key "username" not found in secret default/creds`))
+		_, _, err = secretCredentials(secret(map[string][]byte{"username": []byte("admin")}), true)
+		Expect(err).To(MatchError(`key "password" not found in secret default/creds`))
+	})
+
+	It("only needs the password of password only clients", func() {
+		_, password, err := secretCredentials(secret(map[string][]byte{"password": []byte("deluge")}), false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(password).To(Equal("deluge"))
+	})
+})
@@ -17,9 +17,7 @@ limitations under the License.
 package controller
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -30,23 +28,33 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/solver"
 )
 
 // IndexerReconciler reconciles a Indexer object
 type IndexerReconciler struct {
 	client.Client
-	Scheme          *runtime.Scheme
-	HTTPClient      *http.Client
-	FlareSolverrURL string
+	Scheme     *runtime.Scheme
+	HTTPClient *http.Client
+	// Solvers holds the challenge solvers indexers may go through, nil when
+	// none is deployed
+	Solvers *solver.Registry
+	// ProxyURL is the default proxy for indexers without their own
+	ProxyURL string
+
+	clients *indexerClients
 }
 
 // +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=indexers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=indexers/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=indexers/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -57,6 +65,10 @@ func (r *IndexerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	var indexer torrentsv1alpha1.Indexer
 	if err := r.Get(ctx, req.NamespacedName, &indexer); err != nil {
 		if errors.IsNotFound(err) {
+			// Release the browser sessions solvers kept for the deleted indexer
+			if err := r.Solvers.Destroy(ctx, req.NamespacedName.String()); err != nil {
+				l.Error(err, "Failed to destroy challenge solver session", "indexer", req.NamespacedName)
+			}
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
@@ -69,29 +81,50 @@ func (r *IndexerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	l.Info("Reconciling Indexer", "name", indexer.Name)
 
-	needsCheck := true
 	// Check if we need to run health check (e.g., every 15 minutes)
-	readyCondition := apimeta.FindStatusCondition(indexer.Status.Conditions, "Ready")
-	if readyCondition != nil {
-		if time.Since(readyCondition.LastTransitionTime.Time) < 15*time.Minute {
-			needsCheck = false
-		}
-	}
+	needsCheck := time.Since(lastChecked(&indexer.Status)) >= 15*time.Minute
 
 	if needsCheck {
 		l.Info("Running health check", "indexer", indexer.Name)
-		healthy, errMsg := r.checkHealth(ctx, &indexer)
+		status := metav1.ConditionFalse
+		reason := "HealthCheckFailed"
+		message := "No links defined"
+
+		// Transport settings that cannot be resolved, like a proxy Secret
+		// missing its credentials, would fail every link the same way
+		var links []torrentsv1alpha1.LinkStatus
+		if _, err := r.clients.get(ctx, &indexer); err != nil {
+			reason = "InvalidSpec"
+			message = err.Error()
+		} else {
+			links = r.checkHealth(ctx, &indexer)
+		}
 
-		status := metav1.ConditionTrue
-		reason := "HealthCheckSucceeded"
-		message := "Indexer is healthy"
+		indexer.Status.ActiveLink = ""
+		var failures []string
+		for _, link := range links {
+			if link.Healthy {
+				if indexer.Status.ActiveLink == "" {
+					indexer.Status.ActiveLink = link.URL
+				}
+				continue
+			}
+			failures = append(failures, fmt.Sprintf("%s: %s", link.URL, link.Message))
+		}
 
-		if !healthy {
-			status = metav1.ConditionFalse
-			reason = "HealthCheckFailed"
-			message = errMsg
+		if indexer.Status.ActiveLink != "" {
+			status = metav1.ConditionTrue
+			reason = "HealthCheckSucceeded"
+			message = "Indexer is healthy"
+			if len(failures) > 0 {
+				message = fmt.Sprintf("Indexer is healthy via %s (%d of %d mirrors failing)", indexer.Status.ActiveLink, len(failures), len(links))
+			}
+		} else if len(failures) > 0 {
+			message = strings.Join(failures, "; ")
 		}
 
+		indexer.Status.Links = links
+
 		newCondition := metav1.Condition{
 			Type:               "Ready",
 			Status:             status,
@@ -112,15 +145,37 @@ func (r *IndexerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	return ctrl.Result{RequeueAfter: 15 * time.Minute}, nil
 }
 
-func (r *IndexerReconciler) checkHealth(ctx context.Context, indexer *torrentsv1alpha1.Indexer) (bool, string) {
-	if len(indexer.Spec.Links) == 0 {
-		return false, "No links defined"
+// lastChecked is when the mirrors of the indexer were last health checked,
+// zero when they never were
+func lastChecked(status *torrentsv1alpha1.IndexerStatus) time.Time {
+	var last time.Time
+	for _, link := range status.Links {
+		if link.LastChecked.After(last) {
+			last = link.LastChecked.Time
+		}
 	}
+	return last
+}
 
+// checkHealth health checks every mirror listed in the indexer links
+func (r *IndexerReconciler) checkHealth(ctx context.Context, indexer *torrentsv1alpha1.Indexer) []torrentsv1alpha1.LinkStatus {
+	links := make([]torrentsv1alpha1.LinkStatus, 0, len(indexer.Spec.Links))
+	for _, link := range indexer.Spec.Links {
+		healthy, errMsg := r.checkLink(ctx, indexer, link)
+		links = append(links, torrentsv1alpha1.LinkStatus{
+			URL:         link,
+			Healthy:     healthy,
+			Message:     errMsg,
+			LastChecked: metav1.Now(),
+		})
+	}
+	return links
+}
+
+func (r *IndexerReconciler) checkLink(ctx context.Context, indexer *torrentsv1alpha1.Indexer, link string) (bool, string) {
 	// Use specific search path if available, or just the base URL
-	baseURL := indexer.Spec.Links[0]
 	// Remove trailing slash to avoid double slashes
-	baseURL = strings.TrimRight(baseURL, "/")
+	baseURL := strings.TrimRight(link, "/")
 
 	targetURL := baseURL
 
@@ -138,11 +193,7 @@ func (r *IndexerReconciler) checkHealth(ctx context.Context, indexer *torrentsv1
 			// If complex templating remains, fallback to base URL
 			targetURL = baseURL
 		} else {
-			if !strings.HasPrefix(path, "/") {
-				targetURL = baseURL + "/" + path
-			} else {
-				targetURL = baseURL + path
-			}
+			targetURL = joinURL(baseURL, path)
 		}
 	}
 
@@ -150,92 +201,10 @@ func (r *IndexerReconciler) checkHealth(ctx context.Context, indexer *torrentsv1
 		targetURL = baseURL
 	}
 
-	// Check if we should use FlareSolverr
-	useFlareSolverr := false
-	if r.FlareSolverrURL != "" {
-		for _, setting := range indexer.Spec.Settings {
-			if setting.Type == "info_flaresolverr" {
-				useFlareSolverr = true
-				break
-			}
-		}
-	}
-
-	// fmt.Printf("DEBUG: Indexer=%s URL=%s FS_URL='%s' UseFS=%v\n", indexer.Name, targetURL, r.FlareSolverrURL, useFlareSolverr)
-
-	if useFlareSolverr {
-		return r.checkHealthViaFlareSolverr(ctx, targetURL)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "GET", targetURL, nil)
-	if err != nil {
-		return false, fmt.Sprintf("Failed to create request: %v", err)
-	}
-
-	// Set User-Agent as some trackers require it
-	req.Header.Set("User-Agent", "Prowlarr/1.0 (Text-Mode-Operator)")
-
-	resp, err := r.HTTPClient.Do(req)
-	if err != nil {
-		return false, fmt.Sprintf("Connection failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		return true, ""
-	}
-
-	return false, fmt.Sprintf("HTTP Status: %d", resp.StatusCode)
-}
-
-type flareSolverrRequest struct {
-	Cmd        string `json:"cmd"`
-	URL        string `json:"url"`
-	MaxTimeout int    `json:"maxTimeout"`
-}
-
-type flareSolverrResponse struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
-	// Solution struct {
-	// 	Response string `json:"response"` // HTML content
-	// } `json:"solution"`
-}
-
-func (r *IndexerReconciler) checkHealthViaFlareSolverr(ctx context.Context, url string) (bool, string) {
-	reqBody := flareSolverrRequest{
-		Cmd:        "request.get",
-		URL:        url,
-		MaxTimeout: 60000,
-	}
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return false, fmt.Sprintf("Failed to marshal FS request: %v", err)
-	}
-
-	fsURL := fmt.Sprintf("%s/v1", strings.TrimRight(r.FlareSolverrURL, "/"))
-	req, err := http.NewRequestWithContext(ctx, "POST", fsURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return false, fmt.Sprintf("Failed to create FS request: %v", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := r.HTTPClient.Do(req)
-	if err != nil {
-		return false, fmt.Sprintf("FlareSolverr connection failed: %v", err)
+	if _, err := r.clients.fetch(ctx, indexer, pageRequest{URL: targetURL}); err != nil {
+		return false, err.Error()
 	}
-	defer resp.Body.Close()
-
-	var fsResp flareSolverrResponse
-	if err := json.NewDecoder(resp.Body).Decode(&fsResp); err != nil {
-		return false, fmt.Sprintf("Failed to decode FS response: %v", err)
-	}
-
-	if fsResp.Status == "ok" {
-		return true, ""
-	}
-
-	return false, fmt.Sprintf("FlareSolverr Status: %s, Msg: %s", fsResp.Status, fsResp.Message)
+	return true, ""
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -248,7 +217,9 @@ func (r *IndexerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			// Default Go client follows redirects.
 		}
 	}
+	r.clients = newIndexerClients(r.Client, r.HTTPClient, r.ProxyURL, r.Solvers)
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&torrentsv1alpha1.Indexer{}).
+		// Status updates would trigger a health check each
+		For(&torrentsv1alpha1.Indexer{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
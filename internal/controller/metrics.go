@@ -45,9 +45,25 @@ var (
 			Help: "Total number of failed torrent requests",
 		},
 	)
+
+	torrentsAddedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "torrents_added_total",
+			Help: "Total number of torrents handed to download clients",
+		},
+		[]string{"download_client", "status"},
+	)
+
+	torrentsStalledTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "torrents_stalled_total",
+			Help: "Total number of torrents that stalled in download clients",
+		},
+		[]string{"download_client"},
+	)
 )
 
 func init() {
 	// Register custom metrics with the global prometheus registry
-	metrics.Registry.MustRegister(torrentSearchesTotal, torrentRequestDuration, torrentRequestFailureDuration, torrentsCreatedTotal, torrentRequestsFailedTotal)
+	metrics.Registry.MustRegister(torrentSearchesTotal, torrentRequestDuration, torrentRequestFailureDuration, torrentsCreatedTotal, torrentRequestsFailedTotal, torrentsAddedTotal, torrentsStalledTotal)
 }
@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"strings"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+)
+
+// mirrorOrder returns the indexer links in the order searches should try them:
+// the active link first, then the other links reported healthy, and finally
+// links that are failing or were never checked as a last resort.
+func mirrorOrder(indexer *torrentsv1alpha1.Indexer) []string {
+	health := make(map[string]bool, len(indexer.Status.Links))
+	for _, link := range indexer.Status.Links {
+		health[link.URL] = link.Healthy
+	}
+
+	var active, healthy, rest []string
+	for _, link := range indexer.Spec.Links {
+		switch {
+		case link == indexer.Status.ActiveLink:
+			active = append(active, link)
+		case health[link]:
+			healthy = append(healthy, link)
+		default:
+			rest = append(rest, link)
+		}
+	}
+
+	ordered := append(active, healthy...)
+	return append(ordered, rest...)
+}
+
+// joinURL appends path to the mirror base URL without doubling slashes
+func joinURL(baseURL, path string) string {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if path == "" {
+		return baseURL
+	}
+	if !strings.HasPrefix(path, "/") {
+		return baseURL + "/" + path
+	}
+	return baseURL + path
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/parser"
+)
+
+// releaseOffers holds the releases offered to each request until its next
+// reconcile
+type releaseOffers struct {
+	mu     sync.Mutex
+	offers map[types.NamespacedName][]parser.ParseResult
+}
+
+func (o *releaseOffers) add(request types.NamespacedName, results []parser.ParseResult) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.offers == nil {
+		o.offers = map[types.NamespacedName][]parser.ParseResult{}
+	}
+	o.offers[request] = append(o.offers[request], results...)
+}
+
+// take removes and returns the releases offered to the request
+func (o *releaseOffers) take(request types.NamespacedName) []parser.ParseResult {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	results := o.offers[request]
+	delete(o.offers, request)
+	return results
+}
+
+// Offer hands new releases found by the RSS sync to a monitored request and
+// queues it, so they are evaluated without searching the indexers
+func (r *TorrentRequestReconciler) Offer(ctx context.Context, request types.NamespacedName, results []parser.ParseResult) {
+	r.offers.add(request, results)
+	tr := &torrentsv1alpha1.TorrentRequest{ObjectMeta: metav1.ObjectMeta{Name: request.Name, Namespace: request.Namespace}}
+	select {
+	case r.rssEvents <- event.GenericEvent{Object: tr}:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,328 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+)
+
+const (
+	// scheduledAtAnnotation records on a scheduled request the time it was
+	// scheduled for
+	scheduledAtAnnotation = "torrents.vitoru.fun/scheduled-at"
+	// maxMissedSchedules is how many missed runs are walked through before
+	// skipping straight to the latest one
+	maxMissedSchedules = 100
+	// defaultSuccessfulRequestsHistoryLimit and defaultFailedRequestsHistoryLimit
+	// are the history limits of a CronJob
+	defaultSuccessfulRequestsHistoryLimit = 3
+	defaultFailedRequestsHistoryLimit     = 1
+)
+
+// ScheduledTorrentRequestReconciler creates TorrentRequests from the template
+// of a ScheduledTorrentRequest on its cron schedule, following the semantics
+// of a CronJob for missed runs, concurrency and history
+type ScheduledTorrentRequestReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=scheduledtorrentrequests,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=scheduledtorrentrequests/status,verbs=get;update;patch
+
+func (r *ScheduledTorrentRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+	now := time.Now()
+
+	var str torrentsv1alpha1.ScheduledTorrentRequest
+	if err := r.Get(ctx, req.NamespacedName, &str); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	var list torrentsv1alpha1.TorrentRequestList
+	if err := r.List(ctx, &list, client.InNamespace(str.Namespace), client.MatchingLabels{"created-by": str.Name}); err != nil {
+		l.Error(err, "Failed to list torrent requests")
+		return ctrl.Result{}, err
+	}
+	var active, successful, failed []*torrentsv1alpha1.TorrentRequest
+	for i := range list.Items {
+		tr := &list.Items[i]
+		if !metav1.IsControlledBy(tr, &str) {
+			continue
+		}
+		switch tr.Status.State {
+		case "Completed":
+			successful = append(successful, tr)
+		case "Failed":
+			failed = append(failed, tr)
+		default:
+			active = append(active, tr)
+		}
+	}
+
+	str.Status.Active = nil
+	for _, tr := range active {
+		str.Status.Active = append(str.Status.Active, requestReference(tr))
+	}
+	for _, tr := range successful {
+		if t := scheduledTime(tr); t != nil && (str.Status.LastSuccessfulTime == nil || t.After(str.Status.LastSuccessfulTime.Time)) {
+			str.Status.LastSuccessfulTime = &metav1.Time{Time: *t}
+		}
+	}
+
+	r.pruneHistory(ctx, successful, historyLimit(str.Spec.SuccessfulRequestsHistoryLimit, defaultSuccessfulRequestsHistoryLimit))
+	r.pruneHistory(ctx, failed, historyLimit(str.Spec.FailedRequestsHistoryLimit, defaultFailedRequestsHistoryLimit))
+
+	sched, err := parseSchedule(str.Spec.Schedule, str.Spec.TimeZone)
+	if err != nil {
+		// Only a spec change can fix the schedule
+		l.Error(err, "Invalid schedule", "schedule", str.Spec.Schedule)
+		str.Status.NextScheduleTime = nil
+		meta.SetStatusCondition(&str.Status.Conditions, metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidSchedule",
+			Message: err.Error(),
+		})
+		return ctrl.Result{}, r.Status().Update(ctx, &str)
+	}
+
+	if str.Spec.Suspend {
+		str.Status.NextScheduleTime = nil
+		meta.SetStatusCondition(&str.Status.Conditions, metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionFalse,
+			Reason:  "Suspended",
+			Message: "Scheduling is suspended",
+		})
+		return ctrl.Result{}, r.Status().Update(ctx, &str)
+	}
+
+	missed, next, tooMany := nextSchedule(&str, sched, now)
+	if next.IsZero() {
+		// Schedules like "0 0 30 2 *" parse but never fire, only a spec
+		// change can fix them
+		str.Status.NextScheduleTime = nil
+		meta.SetStatusCondition(&str.Status.Conditions, metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidSchedule",
+			Message: fmt.Sprintf("Schedule %q never fires", str.Spec.Schedule),
+		})
+		return ctrl.Result{}, r.Status().Update(ctx, &str)
+	}
+	if tooMany {
+		l.Info("Too many missed runs, only starting the latest", "schedule", str.Spec.Schedule)
+	}
+	str.Status.NextScheduleTime = &metav1.Time{Time: next}
+	// Requeued a little past the scheduled time so it counts as due
+	result := ctrl.Result{RequeueAfter: next.Sub(now) + 100*time.Millisecond}
+
+	condition := metav1.Condition{
+		Type:    "Ready",
+		Status:  metav1.ConditionTrue,
+		Reason:  "Scheduled",
+		Message: fmt.Sprintf("Next search at %s", next.Format(time.RFC3339)),
+	}
+	switch {
+	case missed.IsZero():
+	case str.Spec.StartingDeadlineSeconds != nil && now.Sub(missed) > time.Duration(*str.Spec.StartingDeadlineSeconds)*time.Second:
+		l.Info("Missed the starting deadline, skipping the run", "scheduledTime", missed)
+		condition.Reason = "MissedSchedule"
+		condition.Message = fmt.Sprintf("Missed the search of %s, next at %s", missed.Format(time.RFC3339), next.Format(time.RFC3339))
+	case str.Spec.ConcurrencyPolicy == torrentsv1alpha1.ForbidConcurrent && len(active) > 0:
+		// The run stays missed, so it starts once the active request ends
+		// if still within the starting deadline
+		l.Info("Previous request still running, skipping the run", "scheduledTime", missed)
+		condition.Reason = "ConcurrencyForbidden"
+		condition.Message = fmt.Sprintf("Skipped the search of %s while %s is running", missed.Format(time.RFC3339), active[0].Name)
+		meta.SetStatusCondition(&str.Status.Conditions, condition)
+		return result, r.Status().Update(ctx, &str)
+	default:
+		if str.Spec.ConcurrencyPolicy == torrentsv1alpha1.ReplaceConcurrent {
+			for _, tr := range active {
+				if err := r.Delete(ctx, tr, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+					l.Error(err, "Failed to delete running torrent request", "name", tr.Name)
+					return ctrl.Result{}, err
+				}
+				l.Info("Replaced running torrent request", "name", tr.Name)
+			}
+			str.Status.Active = nil
+		}
+
+		tr, err := r.newScheduledRequest(&str, missed)
+		if err != nil {
+			l.Error(err, "Failed to build torrent request")
+			return ctrl.Result{}, err
+		}
+		if err := r.Create(ctx, tr); err != nil && !errors.IsAlreadyExists(err) {
+			l.Error(err, "Failed to create torrent request", "name", tr.Name)
+			return ctrl.Result{}, err
+		}
+		l.Info("Created scheduled torrent request", "name", tr.Name, "scheduledTime", missed)
+		str.Status.Active = append(str.Status.Active, requestReference(tr))
+		str.Status.LastScheduleTime = &metav1.Time{Time: missed}
+	}
+
+	meta.SetStatusCondition(&str.Status.Conditions, condition)
+	if err := r.Status().Update(ctx, &str); err != nil {
+		return ctrl.Result{}, err
+	}
+	return result, nil
+}
+
+// parseSchedule parses a cron schedule evaluated in the time zone, the local
+// one when nil
+func parseSchedule(schedule string, timeZone *string) (cron.Schedule, error) {
+	if strings.Contains(schedule, "TZ=") {
+		return nil, fmt.Errorf("schedule must not set a time zone, use timeZone instead")
+	}
+	if timeZone != nil {
+		if _, err := time.LoadLocation(*timeZone); err != nil {
+			return nil, fmt.Errorf("unknown time zone %q: %w", *timeZone, err)
+		}
+		schedule = "CRON_TZ=" + *timeZone + " " + schedule
+	}
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return nil, fmt.Errorf("unparseable schedule %q: %w", schedule, err)
+	}
+	return sched, nil
+}
+
+// nextSchedule returns the latest scheduled time missed since the last run,
+// zero when none, and the next scheduled time after now. Runs are counted
+// from the creation of the ScheduledTorrentRequest, and not before its
+// starting deadline. tooMany reports more than maxMissedSchedules missed
+// runs, which were skipped over.
+func nextSchedule(str *torrentsv1alpha1.ScheduledTorrentRequest, sched cron.Schedule, now time.Time) (missed, next time.Time, tooMany bool) {
+	earliest := str.CreationTimestamp.Time
+	if str.Status.LastScheduleTime != nil {
+		earliest = str.Status.LastScheduleTime.Time
+	}
+	if d := str.Spec.StartingDeadlineSeconds; d != nil {
+		if deadline := now.Add(-time.Duration(*d) * time.Second); deadline.After(earliest) {
+			earliest = deadline
+		}
+	}
+
+	count := 0
+	for t := sched.Next(earliest); !t.IsZero() && !t.After(now); t = sched.Next(t) {
+		missed = t
+		count++
+		if count == maxMissedSchedules {
+			// Skip to the runs of the last interval instead of walking
+			// through months of a frequent schedule
+			tooMany = true
+			interval := sched.Next(t).Sub(t)
+			if skip := now.Add(-interval); skip.After(t) && !sched.Next(skip).After(now) {
+				t = skip
+			}
+		}
+	}
+	return missed, sched.Next(now), tooMany
+}
+
+// newScheduledRequest builds the TorrentRequest of the run scheduled at t
+func (r *ScheduledTorrentRequestReconciler) newScheduledRequest(str *torrentsv1alpha1.ScheduledTorrentRequest, t time.Time) (*torrentsv1alpha1.TorrentRequest, error) {
+	labels := map[string]string{}
+	for k, v := range str.Spec.RequestTemplate.Labels {
+		labels[k] = v
+	}
+	labels["created-by"] = str.Name
+	annotations := map[string]string{}
+	for k, v := range str.Spec.RequestTemplate.Annotations {
+		annotations[k] = v
+	}
+	annotations[scheduledAtAnnotation] = t.UTC().Format(time.RFC3339)
+
+	tr := &torrentsv1alpha1.TorrentRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        scheduledRequestName(str.Name, t),
+			Namespace:   str.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: *str.Spec.RequestTemplate.Spec.DeepCopy(),
+	}
+	if err := ctrl.SetControllerReference(str, tr, r.Scheme); err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+// scheduledRequestName names the request of the run scheduled at t, so a
+// run is never created twice
+func scheduledRequestName(name string, t time.Time) string {
+	suffix := "-" + strconv.FormatInt(t.Unix(), 10)
+	if len(name)+len(suffix) > validation.DNS1123SubdomainMaxLength {
+		name = strings.TrimRight(name[:validation.DNS1123SubdomainMaxLength-len(suffix)], "-.")
+	}
+	return name + suffix
+}
+
+// scheduledTime returns the time a scheduled request was scheduled for
+func scheduledTime(tr *torrentsv1alpha1.TorrentRequest) *time.Time {
+	t, err := time.Parse(time.RFC3339, tr.Annotations[scheduledAtAnnotation])
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// pruneHistory deletes the oldest finished requests beyond the limit
+func (r *ScheduledTorrentRequestReconciler) pruneHistory(ctx context.Context, finished []*torrentsv1alpha1.TorrentRequest, limit int) {
+	if len(finished) <= limit {
+		return
+	}
+	slices.SortFunc(finished, func(a, b *torrentsv1alpha1.TorrentRequest) int {
+		return a.CreationTimestamp.Compare(b.CreationTimestamp.Time)
+	})
+	for _, tr := range finished[:len(finished)-limit] {
+		if err := r.Delete(ctx, tr, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			log.FromContext(ctx).Error(err, "Failed to delete old torrent request", "name", tr.Name)
+		}
+	}
+}
+
+// historyLimit returns the limit, or def when unset
+func historyLimit(limit *int32, def int) int {
+	if limit == nil {
+		return def
+	}
+	return int(*limit)
+}
+
+// requestReference references a scheduled request in the status
+func requestReference(tr *torrentsv1alpha1.TorrentRequest) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: torrentsv1alpha1.GroupVersion.String(),
+		Kind:       "TorrentRequest",
+		Name:       tr.Name,
+		Namespace:  tr.Namespace,
+		UID:        tr.UID,
+	}
+}
+
+func (r *ScheduledTorrentRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&torrentsv1alpha1.ScheduledTorrentRequest{}).
+		Owns(&torrentsv1alpha1.TorrentRequest{}).
+		Complete(r)
+}
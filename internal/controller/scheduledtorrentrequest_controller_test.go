@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+)
+
+var _ = Describe("ScheduledTorrentRequest Controller", func() {
+	const (
+		timeout  = time.Second * 10
+		interval = time.Millisecond * 250
+	)
+
+	Context("When a search is due", func() {
+		It("Should create a TorrentRequest from the template", func() {
+			ctx := context.Background()
+
+			str := &torrentsv1alpha1.ScheduledTorrentRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-scheduled",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.ScheduledTorrentRequestSpec{
+					Schedule: "@every 2s",
+					RequestTemplate: torrentsv1alpha1.TorrentRequestTemplate{
+						Labels: map[string]string{"app": "weekly"},
+						Spec: torrentsv1alpha1.TorrentRequestSpec{
+							Keywords: "weekly show",
+							Indexers: []string{"test-missing-indexer"},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, str)).To(Succeed())
+
+			var list torrentsv1alpha1.TorrentRequestList
+			Eventually(func() int {
+				if err := k8sClient.List(ctx, &list, client.InNamespace("default"), client.MatchingLabels{"created-by": "test-scheduled"}); err != nil {
+					return 0
+				}
+				return len(list.Items)
+			}, timeout, interval).Should(BeNumerically(">", 0))
+			tr := list.Items[0]
+			Expect(tr.Spec.Keywords).To(Equal("weekly show"))
+			Expect(tr.Labels).To(HaveKeyWithValue("app", "weekly"))
+			Expect(tr.Annotations).To(HaveKey(scheduledAtAnnotation))
+			Expect(metav1.IsControlledBy(&tr, str)).To(BeTrue())
+
+			key := types.NamespacedName{Name: "test-scheduled", Namespace: "default"}
+			created := &torrentsv1alpha1.ScheduledTorrentRequest{}
+			Eventually(func() bool {
+				if err := k8sClient.Get(ctx, key, created); err != nil {
+					return false
+				}
+				return created.Status.LastScheduleTime != nil && created.Status.NextScheduleTime != nil
+			}, timeout, interval).Should(BeTrue())
+			cond := meta.FindStatusCondition(created.Status.Conditions, "Ready")
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal("Scheduled"))
+
+			created.Spec.Suspend = true
+			Expect(k8sClient.Update(ctx, created)).To(Succeed())
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, key, created); err != nil {
+					return ""
+				}
+				return meta.FindStatusCondition(created.Status.Conditions, "Ready").Reason
+			}, timeout, interval).Should(Equal("Suspended"))
+		})
+	})
+})
+
+var _ = Describe("nextSchedule", func() {
+	created := time.Date(2025, 1, 1, 12, 30, 0, 0, time.UTC)
+
+	newScheduled := func(last *time.Time, deadline *int64) *torrentsv1alpha1.ScheduledTorrentRequest {
+		str := &torrentsv1alpha1.ScheduledTorrentRequest{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+			Spec:       torrentsv1alpha1.ScheduledTorrentRequestSpec{StartingDeadlineSeconds: deadline},
+		}
+		if last != nil {
+			str.Status.LastScheduleTime = &metav1.Time{Time: *last}
+		}
+		return str
+	}
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+	atPtr := func(day, hour, minute int) *time.Time {
+		t := at(day, hour, minute)
+		return &t
+	}
+	seconds := func(s int64) *int64 { return &s }
+	zone := func(name string) *string { return &name }
+
+	DescribeTable("finds the latest missed run and the next one",
+		func(schedule string, last *time.Time, deadline *int64, now, missed, next time.Time, tooMany bool) {
+			sched, err := parseSchedule(schedule, zone("UTC"))
+			Expect(err).NotTo(HaveOccurred())
+			gotMissed, gotNext, gotTooMany := nextSchedule(newScheduled(last, deadline), sched, now)
+			Expect(gotMissed).To(BeTemporally("==", missed))
+			Expect(gotNext).To(BeTemporally("==", next))
+			Expect(gotTooMany).To(Equal(tooMany))
+		},
+		Entry("nothing due before the first run", "0 20 * * *", nil, nil,
+			at(1, 19, 0), time.Time{}, at(1, 20, 0), false),
+		Entry("first run due", "0 20 * * *", nil, nil,
+			at(1, 20, 0), at(1, 20, 0), at(2, 20, 0), false),
+		Entry("run already started", "0 20 * * *", atPtr(1, 20, 0), nil,
+			at(1, 21, 0), time.Time{}, at(2, 20, 0), false),
+		Entry("only the latest of several missed runs", "0 20 * * *", atPtr(1, 20, 0), nil,
+			at(4, 21, 0), at(4, 20, 0), at(5, 20, 0), false),
+		Entry("missed runs before the starting deadline are ignored", "0 20 * * *", atPtr(1, 20, 0), seconds(600),
+			at(4, 21, 0), time.Time{}, at(5, 20, 0), false),
+		Entry("missed run within the starting deadline", "0 20 * * *", atPtr(1, 20, 0), seconds(600),
+			at(4, 20, 5), at(4, 20, 0), at(5, 20, 0), false),
+		Entry("too many missed runs", "* * * * *", nil, nil,
+			at(3, 12, 30), at(3, 12, 30), at(3, 12, 31), true),
+	)
+
+	It("evaluates the schedule in its time zone", func() {
+		sched, err := parseSchedule("0 20 * * *", zone("Europe/Paris"))
+		Expect(err).NotTo(HaveOccurred())
+		_, next, _ := nextSchedule(newScheduled(nil, nil), sched, at(1, 12, 30))
+		Expect(next).To(BeTemporally("==", at(1, 19, 0)))
+	})
+
+	It("finds no next run for schedules that never fire", func() {
+		sched, err := parseSchedule("0 0 30 2 *", zone("UTC"))
+		Expect(err).NotTo(HaveOccurred())
+		missed, next, _ := nextSchedule(newScheduled(nil, nil), sched, at(1, 12, 30))
+		Expect(missed.IsZero()).To(BeTrue())
+		Expect(next.IsZero()).To(BeTrue())
+	})
+
+	It("rejects invalid schedules", func() {
+		_, err := parseSchedule("0 25 * * *", nil)
+		Expect(err).To(HaveOccurred())
+		_, err = parseSchedule("CRON_TZ=UTC 0 20 * * *", nil)
+		Expect(err).To(HaveOccurred())
+		_, err = parseSchedule("0 20 * * *", zone("Mars/Olympus"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,334 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+)
+
+// SeriesRequestReconciler fans a SeriesRequest out into TorrentRequests, one
+// per season pack or episode, and rolls their state up into its status
+type SeriesRequestReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// seriesChild is a TorrentRequest a SeriesRequest wants
+type seriesChild struct {
+	name    string
+	season  int
+	episode int // 0 for a season pack
+	// episodes is how many requested episodes the request counts for
+	episodes int
+	// future requests are monitored until the episode is released
+	future bool
+}
+
+// +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=seriesrequests,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=seriesrequests/status,verbs=get;update;patch
+
+func (r *SeriesRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	var sr torrentsv1alpha1.SeriesRequest
+	if err := r.Get(ctx, req.NamespacedName, &sr); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	var list torrentsv1alpha1.TorrentRequestList
+	if err := r.List(ctx, &list, client.InNamespace(sr.Namespace), client.MatchingLabels{"created-by": sr.Name}); err != nil {
+		l.Error(err, "Failed to list torrent requests")
+		return ctrl.Result{}, err
+	}
+	existing := map[string]*torrentsv1alpha1.TorrentRequest{}
+	var states []torrentsv1alpha1.SeriesRequestChild
+	for i := range list.Items {
+		tr := &list.Items[i]
+		if !metav1.IsControlledBy(tr, &sr) || tr.Spec.TV == nil {
+			continue
+		}
+		existing[tr.Name] = tr
+		states = append(states, torrentsv1alpha1.SeriesRequestChild{
+			Name:    tr.Name,
+			Season:  tr.Spec.TV.Season,
+			Episode: tr.Spec.TV.Episode,
+			State:   tr.Status.State,
+		})
+	}
+
+	children := seriesChildren(&sr, states)
+	wanted := map[string]bool{}
+	for _, c := range children {
+		wanted[c.name] = true
+		if _, ok := existing[c.name]; ok {
+			continue
+		}
+		tr := &torrentsv1alpha1.TorrentRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      c.name,
+				Namespace: sr.Namespace,
+				Labels: map[string]string{
+					"created-by": sr.Name,
+				},
+			},
+			Spec: childRequestSpec(&sr, c),
+		}
+		if err := ctrl.SetControllerReference(&sr, tr, r.Scheme); err != nil {
+			l.Error(err, "Failed to set controller reference")
+			return ctrl.Result{}, err
+		}
+		if err := r.Create(ctx, tr); err != nil && !errors.IsAlreadyExists(err) {
+			l.Error(err, "Failed to create torrent request", "name", c.name)
+			return ctrl.Result{}, err
+		}
+		l.Info("Created torrent request", "name", c.name, "season", c.season, "episode", c.episode)
+		existing[c.name] = tr
+	}
+
+	// Requests no longer wanted, like the episode after a season finale,
+	// are dropped unless they found something
+	for name, tr := range existing {
+		if wanted[name] || tr.Status.State == "Completed" {
+			continue
+		}
+		if err := r.Delete(ctx, tr); client.IgnoreNotFound(err) != nil {
+			l.Error(err, "Failed to delete torrent request", "name", name)
+		}
+	}
+
+	r.rollUp(&sr, children, existing)
+	if err := r.Status().Update(ctx, &sr); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// rollUp sums the state of the requests up into the SeriesRequest status
+func (r *SeriesRequestReconciler) rollUp(sr *torrentsv1alpha1.SeriesRequest, children []seriesChild, existing map[string]*torrentsv1alpha1.TorrentRequest) {
+	sr.Status.Requests = nil
+	wanted, found, failed, pending := 0, 0, 0, 0
+	for _, c := range children {
+		state := existing[c.name].Status.State
+		if state == "" {
+			state = "Pending"
+		}
+		sr.Status.Requests = append(sr.Status.Requests, torrentsv1alpha1.SeriesRequestChild{
+			Name:    c.name,
+			Season:  c.season,
+			Episode: c.episode,
+			State:   state,
+		})
+
+		wanted += c.episodes
+		switch {
+		case state == "Completed":
+			found += c.episodes
+		case c.episodes == 0 || c.future:
+			// Season packs replaced by their episodes and episodes yet to
+			// be released do not hold the series back
+		case state == "Failed":
+			failed++
+		default:
+			pending++
+		}
+	}
+	sr.Status.EpisodesWanted = wanted
+	sr.Status.EpisodesFound = found
+	sr.Status.Progress = fmt.Sprintf("%d/%d", found, wanted)
+
+	condition := metav1.Condition{
+		Type:    "Ready",
+		Status:  metav1.ConditionFalse,
+		Reason:  "Searching",
+		Message: fmt.Sprintf("%d/%d episodes found", found, wanted),
+	}
+	switch {
+	case pending > 0:
+	case failed > 0:
+		condition.Reason = "EpisodesFailed"
+		condition.Message = fmt.Sprintf("%d/%d episodes found, %d requests failed", found, wanted, failed)
+	case sr.Spec.Future:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Monitoring"
+		condition.Message = fmt.Sprintf("%d/%d episodes found, monitoring future episodes", found, wanted)
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Found"
+		condition.Message = fmt.Sprintf("All %d episodes found", found)
+	}
+	meta.SetStatusCondition(&sr.Status.Conditions, condition)
+}
+
+// seriesChildren lists the requests a SeriesRequest wants given the state of
+// its existing ones. Whole seasons are requested as a season pack, then
+// episode by episode once the pack request failed. Future episodes are
+// requested one at a time after the last requested or found one, along with
+// the first episode of the next season.
+func seriesChildren(sr *torrentsv1alpha1.SeriesRequest, existing []torrentsv1alpha1.SeriesRequestChild) []seriesChild {
+	states := map[string]string{}
+	for _, c := range existing {
+		states[c.Name] = c.State
+	}
+
+	var children []seriesChild
+	// The last requested season, and the episode after the last requested
+	// one, 0 when the length of the season is unknown
+	lastSeason, nextEpisode := 0, 1
+	for _, season := range sr.Spec.Seasons {
+		episodes := seasonEpisodes(season)
+		if season.Season >= lastSeason {
+			lastSeason, nextEpisode = season.Season, 0
+			if len(episodes) > 0 {
+				nextEpisode = episodes[len(episodes)-1] + 1
+			}
+		}
+
+		if len(season.Episodes) == 0 || wholeSeason(episodes, season.EpisodeCount) {
+			pack := seriesChild{
+				name:     seriesChildName(sr.Name, season.Season, 0),
+				season:   season.Season,
+				episodes: max(len(episodes), 1),
+			}
+			if states[pack.name] != "Failed" || len(episodes) == 0 {
+				children = append(children, pack)
+				continue
+			}
+			// No pack was found, the episodes count instead
+			pack.episodes = 0
+			children = append(children, pack)
+		}
+		for _, e := range episodes {
+			children = append(children, seriesChild{
+				name:     seriesChildName(sr.Name, season.Season, e),
+				season:   season.Season,
+				episode:  e,
+				episodes: 1,
+			})
+		}
+	}
+	if !sr.Spec.Future {
+		return children
+	}
+
+	// Future episodes found so far are kept and move the next ones forward
+	var found []torrentsv1alpha1.SeriesRequestChild
+	for _, c := range existing {
+		if c.State == "Completed" && c.Episode > 0 && !slices.ContainsFunc(children, func(w seriesChild) bool { return w.name == c.Name }) {
+			found = append(found, c)
+		}
+	}
+	slices.SortFunc(found, func(a, b torrentsv1alpha1.SeriesRequestChild) int {
+		if a.Season != b.Season {
+			return a.Season - b.Season
+		}
+		return a.Episode - b.Episode
+	})
+	for _, c := range found {
+		children = append(children, seriesChild{name: c.Name, season: c.Season, episode: c.Episode, episodes: 1, future: true})
+		if c.Season > lastSeason || (c.Season == lastSeason && nextEpisode > 0 && c.Episode >= nextEpisode) {
+			lastSeason, nextEpisode = c.Season, c.Episode+1
+		}
+	}
+
+	next := [][2]int{{lastSeason + 1, 1}}
+	if lastSeason > 0 && nextEpisode > 0 {
+		next = [][2]int{{lastSeason, nextEpisode}, {lastSeason + 1, 1}}
+	}
+	for _, n := range next {
+		name := seriesChildName(sr.Name, n[0], n[1])
+		if !slices.ContainsFunc(children, func(w seriesChild) bool { return w.name == name }) {
+			children = append(children, seriesChild{name: name, season: n[0], episode: n[1], episodes: 1, future: true})
+		}
+	}
+	return children
+}
+
+// seasonEpisodes lists the episodes requested of a season in order, nil
+// for a whole season of unknown length
+func seasonEpisodes(season torrentsv1alpha1.SeasonRequest) []int {
+	if len(season.Episodes) == 0 {
+		episodes := make([]int, season.EpisodeCount)
+		for i := range episodes {
+			episodes[i] = i + 1
+		}
+		return episodes
+	}
+	var episodes []int
+	for _, r := range season.Episodes {
+		for e := r.From; e <= max(r.From, r.To); e++ {
+			if !slices.Contains(episodes, e) {
+				episodes = append(episodes, e)
+			}
+		}
+	}
+	slices.Sort(episodes)
+	return episodes
+}
+
+// wholeSeason reports whether the episodes are all those of a season of
+// count episodes
+func wholeSeason(episodes []int, count int) bool {
+	return count > 0 && len(episodes) == count && episodes[0] == 1 && episodes[len(episodes)-1] == count
+}
+
+// childRequestSpec builds the TorrentRequest of a season pack or episode
+func childRequestSpec(sr *torrentsv1alpha1.SeriesRequest, c seriesChild) torrentsv1alpha1.TorrentRequestSpec {
+	t := sr.Spec.Template
+	spec := torrentsv1alpha1.TorrentRequestSpec{
+		TV: &torrentsv1alpha1.TVQuery{
+			Series:     sr.Spec.Series,
+			Season:     c.season,
+			Episode:    c.episode,
+			SeasonPack: c.episode == 0,
+			TVDBID:     sr.Spec.TVDBID,
+			IMDBID:     sr.Spec.IMDBID,
+		},
+		Indexers:          t.Indexers,
+		QualityProfileRef: t.QualityProfileRef,
+		MinSeeders:        t.MinSeeders,
+		MustContain:       t.MustContain,
+		MustNotContain:    t.MustNotContain,
+		PreferredWords:    t.PreferredWords,
+		Filter:            t.Filter,
+		SortBy:            t.SortBy,
+		DownloadClientRef: t.DownloadClientRef,
+	}
+	if c.future {
+		spec.Mode = torrentsv1alpha1.ModeMonitor
+		spec.SearchInterval = t.SearchInterval
+	} else {
+		spec.Retry = t.Retry
+	}
+	return spec
+}
+
+// seriesChildName names the request of a season pack, or of an episode
+func seriesChildName(series string, season, episode int) string {
+	suffix := fmt.Sprintf("-s%02d", season)
+	if episode > 0 {
+		suffix += fmt.Sprintf("e%02d", episode)
+	}
+	if len(series)+len(suffix) > validation.DNS1123SubdomainMaxLength {
+		series = strings.TrimRight(series[:validation.DNS1123SubdomainMaxLength-len(suffix)], "-.")
+	}
+	return series + suffix
+}
+
+func (r *SeriesRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&torrentsv1alpha1.SeriesRequest{}).
+		Owns(&torrentsv1alpha1.TorrentRequest{}).
+		Complete(r)
+}
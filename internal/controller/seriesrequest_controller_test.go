@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+)
+
+var _ = Describe("SeriesRequest Controller", func() {
+	const (
+		timeout  = time.Second * 10
+		interval = time.Millisecond * 250
+	)
+
+	Context("When no season pack is found", func() {
+		It("Should request the episodes one by one and roll up their state", func() {
+			ctx := context.Background()
+
+			sr := &torrentsv1alpha1.SeriesRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-series",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.SeriesRequestSpec{
+					Series:  "The Show",
+					Seasons: []torrentsv1alpha1.SeasonRequest{{Season: 1, EpisodeCount: 2}},
+					Template: torrentsv1alpha1.SeriesRequestTemplate{
+						Indexers: []string{"test-missing-indexer"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sr)).To(Succeed())
+
+			pack := &torrentsv1alpha1.TorrentRequest{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: "test-series-s01", Namespace: "default"}, pack)
+			}, timeout, interval).Should(Succeed())
+			Expect(pack.Spec.TV.SeasonPack).To(BeTrue())
+			Expect(pack.Spec.TV.Series).To(Equal("The Show"))
+			Expect(pack.Spec.Indexers).To(Equal([]string{"test-missing-indexer"}))
+			Expect(pack.OwnerReferences).To(HaveLen(1))
+			Expect(pack.OwnerReferences[0].Name).To(Equal("test-series"))
+
+			// Without a healthy indexer every request fails, the pack first
+			key := types.NamespacedName{Name: "test-series", Namespace: "default"}
+			created := &torrentsv1alpha1.SeriesRequest{}
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, key, created); err != nil {
+					return ""
+				}
+				cond := meta.FindStatusCondition(created.Status.Conditions, "Ready")
+				if cond == nil {
+					return ""
+				}
+				return cond.Reason
+			}, timeout, interval).Should(Equal("EpisodesFailed"))
+			Expect(created.Status.Progress).To(Equal("0/2"))
+			Expect(created.Status.Requests).To(HaveLen(3))
+
+			episode := &torrentsv1alpha1.TorrentRequest{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "test-series-s01e02", Namespace: "default"}, episode)).To(Succeed())
+			Expect(episode.Spec.TV.Season).To(Equal(1))
+			Expect(episode.Spec.TV.Episode).To(Equal(2))
+			Expect(episode.Spec.TV.SeasonPack).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("seriesChildren", func() {
+	type want struct {
+		name     string
+		episodes int
+		future   bool
+	}
+
+	children := func(spec torrentsv1alpha1.SeriesRequestSpec, existing ...torrentsv1alpha1.SeriesRequestChild) []want {
+		sr := &torrentsv1alpha1.SeriesRequest{ObjectMeta: metav1.ObjectMeta{Name: "show"}, Spec: spec}
+		var got []want
+		for _, c := range seriesChildren(sr, existing) {
+			got = append(got, want{c.name, c.episodes, c.future})
+		}
+		return got
+	}
+	season := func(n, count int, ranges ...torrentsv1alpha1.EpisodeRange) torrentsv1alpha1.SeasonRequest {
+		return torrentsv1alpha1.SeasonRequest{Season: n, EpisodeCount: count, Episodes: ranges}
+	}
+	child := func(name string, s, e int, state string) torrentsv1alpha1.SeriesRequestChild {
+		return torrentsv1alpha1.SeriesRequestChild{Name: name, Season: s, Episode: e, State: state}
+	}
+
+	It("requests whole seasons as packs", func() {
+		Expect(children(torrentsv1alpha1.SeriesRequestSpec{Seasons: []torrentsv1alpha1.SeasonRequest{season(1, 0), season(2, 10)}})).To(Equal([]want{
+			{"show-s01", 1, false},
+			{"show-s02", 10, false},
+		}))
+	})
+
+	It("requests every episode once the pack failed", func() {
+		Expect(children(torrentsv1alpha1.SeriesRequestSpec{Seasons: []torrentsv1alpha1.SeasonRequest{season(1, 2)}},
+			child("show-s01", 1, 0, "Failed"))).To(Equal([]want{
+			{"show-s01", 0, false},
+			{"show-s01e01", 1, false},
+			{"show-s01e02", 1, false},
+		}))
+	})
+
+	It("requests episode ranges one by one", func() {
+		Expect(children(torrentsv1alpha1.SeriesRequestSpec{Seasons: []torrentsv1alpha1.SeasonRequest{
+			season(1, 10, torrentsv1alpha1.EpisodeRange{From: 3, To: 4}, torrentsv1alpha1.EpisodeRange{From: 7}),
+		}})).To(Equal([]want{
+			{"show-s01e03", 1, false},
+			{"show-s01e04", 1, false},
+			{"show-s01e07", 1, false},
+		}))
+	})
+
+	It("treats ranges covering the season as the whole season", func() {
+		Expect(children(torrentsv1alpha1.SeriesRequestSpec{Seasons: []torrentsv1alpha1.SeasonRequest{
+			season(1, 3, torrentsv1alpha1.EpisodeRange{From: 1, To: 3}),
+		}})).To(Equal([]want{{"show-s01", 3, false}}))
+	})
+
+	It("requests the episodes after the last requested one", func() {
+		Expect(children(torrentsv1alpha1.SeriesRequestSpec{
+			Seasons: []torrentsv1alpha1.SeasonRequest{season(2, 0, torrentsv1alpha1.EpisodeRange{From: 5})},
+			Future:  true,
+		})).To(Equal([]want{
+			{"show-s02e05", 1, false},
+			{"show-s02e06", 1, true},
+			{"show-s03e01", 1, true},
+		}))
+	})
+
+	It("requests the next season after a pack of unknown length", func() {
+		Expect(children(torrentsv1alpha1.SeriesRequestSpec{
+			Seasons: []torrentsv1alpha1.SeasonRequest{season(1, 0)},
+			Future:  true,
+		})).To(Equal([]want{
+			{"show-s01", 1, false},
+			{"show-s02e01", 1, true},
+		}))
+	})
+
+	It("moves forward as future episodes are found", func() {
+		Expect(children(torrentsv1alpha1.SeriesRequestSpec{Future: true})).To(Equal([]want{{"show-s01e01", 1, true}}))
+
+		Expect(children(torrentsv1alpha1.SeriesRequestSpec{Future: true},
+			child("show-s01e01", 1, 1, "Completed"),
+			child("show-s01e02", 1, 2, "Completed"),
+			child("show-s01e03", 1, 3, "Monitoring"),
+			child("show-s02e01", 2, 1, "Completed"),
+		)).To(Equal([]want{
+			{"show-s01e01", 1, true},
+			{"show-s01e02", 1, true},
+			{"show-s02e01", 1, true},
+			{"show-s02e02", 1, true},
+			{"show-s03e01", 1, true},
+		}))
+	})
+})
@@ -19,6 +19,7 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/downloadclient"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -28,6 +29,8 @@ var (
 	testEnv   *envtest.Environment
 	ctx       context.Context
 	cancel    context.CancelFunc
+	// torrentRequestReconciler receives the releases offered by specs
+	torrentRequestReconciler *TorrentRequestReconciler
 )
 
 func TestControllers(t *testing.T) {
@@ -70,11 +73,40 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).ToNot(HaveOccurred())
 
-	err = (&TorrentRequestReconciler{
-		Client:          mgr.GetClient(),
-		Scheme:          mgr.GetScheme(),
-		HTTPClient:      &http.Client{},
-		FlareSolverrURL: "", // No FS in tests
+	torrentRequestReconciler = &TorrentRequestReconciler{
+		Client:     mgr.GetClient(),
+		Scheme:     mgr.GetScheme(),
+		HTTPClient: &http.Client{},
+		Solvers:    nil, // No challenge solver in tests
+	}
+	err = torrentRequestReconciler.SetupWithManager(mgr)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&SeriesRequestReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&ScheduledTorrentRequestReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr)
+	Expect(err).ToNot(HaveOccurred())
+
+	downloadClients := downloadclient.NewRegistry()
+	err = (&DownloadClientReconciler{
+		Client:  mgr.GetClient(),
+		Scheme:  mgr.GetScheme(),
+		Clients: downloadClients,
+	}).SetupWithManager(mgr)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&TorrentReconciler{
+		Client:     mgr.GetClient(),
+		Scheme:     mgr.GetScheme(),
+		Clients:    downloadClients,
+		HTTPClient: &http.Client{},
 	}).SetupWithManager(mgr)
 	Expect(err).ToNot(HaveOccurred())
 
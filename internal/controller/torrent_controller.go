@@ -0,0 +1,462 @@
+package controller
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/downloadclient"
+	"vitoru.fun/torrents/internal/parser"
+)
+
+const (
+	// downloadClientFinalizer removes the torrent from its download client
+	// before the Torrent is deleted
+	downloadClientFinalizer = "torrents.vitoru.fun/download-client"
+	// downloadClientRetryInterval is how long to wait for an unavailable
+	// download client
+	downloadClientRetryInterval = time.Minute
+	// maxTorrentFileSize bounds the .torrent files fetched
+	maxTorrentFileSize = 10 << 20
+
+	// addedPollDelay leaves the download client time to start a torrent
+	// before it is first polled
+	addedPollDelay = 5 * time.Second
+	// Torrents are polled more often the more active they are
+	activePollInterval = 30 * time.Second
+	queuedPollInterval = 2 * time.Minute
+	idlePollInterval   = 10 * time.Minute
+	// minPollInterval bounds how early a download about to complete is
+	// polled again
+	minPollInterval = 5 * time.Second
+	// defaultStalledTimeout is how long a download may make no progress
+	// with no seeder before it is marked stalled
+	defaultStalledTimeout = 30 * time.Minute
+)
+
+// TorrentReconciler hands Torrents to their DownloadClient, tracks their
+// download, and removes them from it when they are deleted
+type TorrentReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Clients is the registry the download clients are looked up in
+	Clients *downloadclient.Registry
+	// HTTPClient fetches the .torrent files of releases without a magnet
+	HTTPClient *http.Client
+}
+
+// +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=torrents,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=torrents/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=torrents/finalizers,verbs=update
+
+func (r *TorrentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	var t torrentsv1alpha1.Torrent
+	if err := r.Get(ctx, req.NamespacedName, &t); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !t.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, &t)
+	}
+	if t.Status.Hash != "" {
+		return r.track(ctx, &t)
+	}
+
+	dc, err := r.downloadClientFor(ctx, &t)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if dc == nil {
+		// Creating the DownloadClient enqueues the Torrent again
+		message := "No DownloadClient in the namespace"
+		if ref := t.Spec.DownloadClientRef; ref != nil {
+			message = fmt.Sprintf("DownloadClient %s not found", ref.Name)
+		}
+		return ctrl.Result{}, r.setReady(ctx, &t, metav1.ConditionFalse, "NoDownloadClient", message)
+	}
+	entry, ok := r.Clients.Get(client.ObjectKeyFromObject(dc))
+	if !ok || !entry.Ready {
+		err := r.setReady(ctx, &t, metav1.ConditionFalse, "DownloadClientNotReady", fmt.Sprintf("DownloadClient %s is not ready", dc.Name))
+		return ctrl.Result{RequeueAfter: downloadClientRetryInterval}, err
+	}
+
+	// The finalizer is in place before the client knows the torrent, so it
+	// is never left behind
+	if controllerutil.AddFinalizer(&t, downloadClientFinalizer) {
+		if err := r.Update(ctx, &t); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	torrent, err := r.clientTorrent(ctx, &t, dc)
+	if err == nil {
+		torrent.InfoHash, err = torrentHash(&t, torrent.File)
+	}
+	if err == nil {
+		t.Status.Hash, err = entry.Client.Add(ctx, torrent)
+	}
+	if err != nil {
+		l.Error(err, "Failed to add torrent to download client", "downloadClient", dc.Name)
+		torrentsAddedTotal.WithLabelValues(dc.Name, "failed").Inc()
+		t.Status.Hash = ""
+		err := r.setReady(ctx, &t, metav1.ConditionFalse, "AddFailed", err.Error())
+		return ctrl.Result{RequeueAfter: downloadClientRetryInterval}, err
+	}
+	torrentsAddedTotal.WithLabelValues(dc.Name, "success").Inc()
+	l.Info("Added torrent to download client", "downloadClient", dc.Name, "hash", t.Status.Hash)
+
+	t.Status.DownloadClient = dc.Name
+	t.Status.AddedAt = &metav1.Time{Time: time.Now()}
+	t.Status.Phase = torrentsv1alpha1.TorrentQueued
+	err = r.setReady(ctx, &t, metav1.ConditionTrue, "Added", fmt.Sprintf("Added to %s", dc.Name))
+	return ctrl.Result{RequeueAfter: addedPollDelay}, err
+}
+
+// track polls the download client for the progress of the torrent, again
+// after an interval depending on how active it is
+func (r *TorrentReconciler) track(ctx context.Context, t *torrentsv1alpha1.Torrent) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	var dc torrentsv1alpha1.DownloadClient
+	if err := r.Get(ctx, types.NamespacedName{Namespace: t.Namespace, Name: t.Status.DownloadClient}, &dc); err != nil {
+		// Without its DownloadClient there is nothing left to poll
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	entry, ok := r.Clients.Get(client.ObjectKeyFromObject(&dc))
+	if !ok || !entry.Ready {
+		return ctrl.Result{RequeueAfter: downloadClientRetryInterval}, nil
+	}
+
+	previous := t.Status.DeepCopy()
+	status, err := entry.Client.Get(ctx, t.Status.Hash)
+	switch {
+	case stderrors.Is(err, downloadclient.ErrNotFound):
+		t.Status.Phase = torrentsv1alpha1.TorrentError
+		t.Status.DownloadRate = ""
+		t.Status.ETA = nil
+		apimeta.SetStatusCondition(&t.Status.Conditions, metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionFalse,
+			Reason:  "NotInDownloadClient",
+			Message: fmt.Sprintf("Torrent was removed from %s", dc.Name),
+		})
+	case err != nil:
+		l.Error(err, "Failed to get torrent from download client", "downloadClient", dc.Name)
+		return ctrl.Result{RequeueAfter: downloadClientRetryInterval}, nil
+	default:
+		trackProgress(&t.Status, status, stalledTimeout(&dc), time.Now())
+		apimeta.SetStatusCondition(&t.Status.Conditions, progressCondition(t, status, dc.Name))
+	}
+
+	if t.Status.Phase == torrentsv1alpha1.TorrentStalled && previous.Phase != torrentsv1alpha1.TorrentStalled {
+		l.Info("Torrent stalled", "downloadClient", dc.Name, "progress", t.Status.Progress)
+		torrentsStalledTotal.WithLabelValues(dc.Name).Inc()
+	}
+	if !equality.Semantic.DeepEqual(previous, &t.Status) {
+		if err := r.Status().Update(ctx, t); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to update Torrent status")
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: pollInterval(&t.Status)}, nil
+}
+
+// trackProgress records the state the download client reports in the status
+// of a Torrent. A download is stalled once it made no progress for timeout
+// while no seeder was connected.
+func trackProgress(ts *torrentsv1alpha1.TorrentStatus, s *downloadclient.Status, timeout time.Duration, now time.Time) {
+	phase := torrentsv1alpha1.TorrentDownloading
+	switch s.State {
+	case downloadclient.StateError:
+		phase = torrentsv1alpha1.TorrentError
+	case downloadclient.StateSeeding:
+		phase = torrentsv1alpha1.TorrentSeeding
+	case downloadclient.StatePaused:
+		phase = torrentsv1alpha1.TorrentQueued
+		if s.Progress >= 1 {
+			phase = torrentsv1alpha1.TorrentCompleted
+		}
+	case downloadclient.StateQueued, downloadclient.StateChecking:
+		phase = torrentsv1alpha1.TorrentQueued
+	}
+
+	progress := fmt.Sprintf("%.1f%%", s.Progress*100)
+	// The stalled timeout starts over with progress, and as the download starts
+	started := phase == torrentsv1alpha1.TorrentDownloading &&
+		ts.Phase != torrentsv1alpha1.TorrentDownloading && ts.Phase != torrentsv1alpha1.TorrentStalled
+	if progress != ts.Progress || started || ts.LastActivityTime == nil {
+		ts.LastActivityTime = &metav1.Time{Time: now}
+	}
+	if phase == torrentsv1alpha1.TorrentDownloading && s.Seeders == 0 && timeout > 0 && now.Sub(ts.LastActivityTime.Time) >= timeout {
+		phase = torrentsv1alpha1.TorrentStalled
+	}
+
+	ts.Phase = phase
+	ts.Progress = progress
+	ts.DownloadRate = formatRate(s.DownloadRate)
+	ts.Ratio = fmt.Sprintf("%.2f", s.Ratio)
+	ts.SavePath = s.SavePath
+	ts.ETA = nil
+	if phase == torrentsv1alpha1.TorrentDownloading && s.ETA > 0 {
+		ts.ETA = &metav1.Duration{Duration: s.ETA.Round(time.Second)}
+	}
+	switch {
+	case !s.CompletedAt.IsZero():
+		ts.CompletedAt = &metav1.Time{Time: s.CompletedAt}
+	case s.Progress >= 1 && ts.CompletedAt == nil:
+		ts.CompletedAt = &metav1.Time{Time: now}
+	}
+}
+
+// progressCondition is the Ready condition of a tracked Torrent, false when
+// its download failed or stalled
+func progressCondition(t *torrentsv1alpha1.Torrent, s *downloadclient.Status, downloadClient string) metav1.Condition {
+	switch t.Status.Phase {
+	case torrentsv1alpha1.TorrentError:
+		message := s.Error
+		if message == "" {
+			message = fmt.Sprintf("%s reports an error", downloadClient)
+		}
+		return metav1.Condition{Type: "Ready", Status: metav1.ConditionFalse, Reason: "DownloadFailed", Message: message}
+	case torrentsv1alpha1.TorrentStalled:
+		return metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionFalse,
+			Reason:  "Stalled",
+			Message: fmt.Sprintf("No progress since %s and no seeder", t.Status.LastActivityTime.UTC().Format(time.RFC3339)),
+		}
+	default:
+		return metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Added", Message: fmt.Sprintf("Added to %s", downloadClient)}
+	}
+}
+
+// pollInterval is how long until a Torrent is polled again: often while it
+// downloads, and as it is about to complete
+func pollInterval(ts *torrentsv1alpha1.TorrentStatus) time.Duration {
+	switch ts.Phase {
+	case torrentsv1alpha1.TorrentDownloading:
+		if ts.ETA != nil && ts.ETA.Duration < activePollInterval {
+			return max(ts.ETA.Duration, minPollInterval)
+		}
+		return activePollInterval
+	case torrentsv1alpha1.TorrentSeeding, torrentsv1alpha1.TorrentCompleted:
+		return idlePollInterval
+	default:
+		return queuedPollInterval
+	}
+}
+
+// stalledTimeout is how long downloads of the client may make no progress
+// before they are marked stalled, 0 when they never are
+func stalledTimeout(dc *torrentsv1alpha1.DownloadClient) time.Duration {
+	if dc.Spec.StalledTimeout != nil {
+		return dc.Spec.StalledTimeout.Duration
+	}
+	return defaultStalledTimeout
+}
+
+// formatRate formats a speed in bytes per second, e.g. "2.4 MiB/s"
+func formatRate(rate int64) string {
+	const unit = 1024
+	if rate < unit {
+		return fmt.Sprintf("%d B/s", rate)
+	}
+	div, exp := int64(unit), 0
+	for n := rate / unit; n >= unit && exp < 3; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB/s", float64(rate)/float64(div), "KMGT"[exp])
+}
+
+// finalize removes the torrent from its download client and lets the Torrent
+// go. A download client that no longer exists has nothing to remove.
+func (r *TorrentReconciler) finalize(ctx context.Context, t *torrentsv1alpha1.Torrent) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(t, downloadClientFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if t.Status.Hash != "" && t.Status.DownloadClient != "" {
+		var dc torrentsv1alpha1.DownloadClient
+		err := r.Get(ctx, types.NamespacedName{Namespace: t.Namespace, Name: t.Status.DownloadClient}, &dc)
+		switch {
+		case errors.IsNotFound(err):
+		case err != nil:
+			return ctrl.Result{}, err
+		default:
+			entry, ok := r.Clients.Get(client.ObjectKeyFromObject(&dc))
+			if !ok {
+				return ctrl.Result{RequeueAfter: downloadClientRetryInterval}, nil
+			}
+			if err := entry.Client.Remove(ctx, t.Status.Hash, dc.Spec.RemoveData); err != nil {
+				log.FromContext(ctx).Error(err, "Failed to remove torrent from download client", "downloadClient", dc.Name)
+				return ctrl.Result{RequeueAfter: downloadClientRetryInterval}, nil
+			}
+			log.FromContext(ctx).Info("Removed torrent from download client", "downloadClient", dc.Name, "hash", t.Status.Hash)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(t, downloadClientFinalizer)
+	return ctrl.Result{}, r.Update(ctx, t)
+}
+
+// downloadClientFor returns the DownloadClient the Torrent references, or the
+// default one of its namespace, nil when there is none
+func (r *TorrentReconciler) downloadClientFor(ctx context.Context, t *torrentsv1alpha1.Torrent) (*torrentsv1alpha1.DownloadClient, error) {
+	if ref := t.Spec.DownloadClientRef; ref != nil {
+		var dc torrentsv1alpha1.DownloadClient
+		if err := r.Get(ctx, types.NamespacedName{Namespace: t.Namespace, Name: ref.Name}, &dc); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return &dc, nil
+	}
+
+	var list torrentsv1alpha1.DownloadClientList
+	if err := r.List(ctx, &list, client.InNamespace(t.Namespace)); err != nil {
+		return nil, err
+	}
+	return defaultDownloadClient(list.Items), nil
+}
+
+// defaultDownloadClient picks the default among the download clients of a
+// namespace, by name when several are, the only one when none is
+func defaultDownloadClient(clients []torrentsv1alpha1.DownloadClient) *torrentsv1alpha1.DownloadClient {
+	var def *torrentsv1alpha1.DownloadClient
+	for i := range clients {
+		dc := &clients[i]
+		if dc.Spec.Default && (def == nil || dc.Name < def.Name) {
+			def = dc
+		}
+	}
+	if def == nil && len(clients) == 1 {
+		def = &clients[0]
+	}
+	return def
+}
+
+// clientTorrent builds what is handed to the download client, fetching the
+// .torrent file of releases linking to one rather than to a magnet
+func (r *TorrentReconciler) clientTorrent(ctx context.Context, t *torrentsv1alpha1.Torrent, dc *torrentsv1alpha1.DownloadClient) (downloadclient.Torrent, error) {
+	torrent := downloadclient.Torrent{
+		Name:     t.Spec.Title,
+		Magnet:   t.Spec.Magnet,
+		Category: dc.Spec.Category,
+		SavePath: dc.Spec.SavePath,
+		Tags:     dc.Spec.Tags,
+	}
+	if strings.HasPrefix(t.Spec.Magnet, "magnet:") {
+		return torrent, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.Spec.Magnet, nil)
+	if err != nil {
+		return torrent, fmt.Errorf("invalid torrent link: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := r.HTTPClient.Do(req)
+	if err != nil {
+		return torrent, fmt.Errorf("failed to fetch .torrent file: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return torrent, fmt.Errorf("failed to fetch .torrent file: status %d", resp.StatusCode)
+	}
+	torrent.File, err = io.ReadAll(io.LimitReader(resp.Body, maxTorrentFileSize))
+	if err != nil {
+		return torrent, fmt.Errorf("failed to fetch .torrent file: %w", err)
+	}
+	torrent.Magnet = ""
+	return torrent, nil
+}
+
+// torrentHash returns the info hash of the Torrent, from its .torrent file
+// when it was fetched
+func torrentHash(t *torrentsv1alpha1.Torrent, file []byte) (string, error) {
+	if len(file) > 0 {
+		return downloadclient.FileInfoHash(file)
+	}
+	if hash := parser.InfoHash(t.Spec.Magnet); hash != "" {
+		return hash, nil
+	}
+	if t.Spec.InfoHash != "" {
+		return strings.ToLower(t.Spec.InfoHash), nil
+	}
+	return "", fmt.Errorf("no info hash in magnet link")
+}
+
+func (r *TorrentReconciler) setReady(ctx context.Context, t *torrentsv1alpha1.Torrent, status metav1.ConditionStatus, reason, message string) error {
+	apimeta.SetStatusCondition(&t.Status.Conditions, metav1.Condition{
+		Type:    "Ready",
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	if err := r.Status().Update(ctx, t); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update Torrent status")
+		return err
+	}
+	return nil
+}
+
+// pendingTorrents enqueues the Torrents of the namespace not handed to a
+// download client yet, when one is created or changes
+func (r *TorrentReconciler) pendingTorrents(ctx context.Context, obj client.Object) []reconcile.Request {
+	var list torrentsv1alpha1.TorrentList
+	if err := r.List(ctx, &list, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list torrents")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, t := range list.Items {
+		if t.Status.Hash == "" && (t.Spec.DownloadClientRef == nil || t.Spec.DownloadClientRef.Name == obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&t)})
+		}
+	}
+	return requests
+}
+
+// torrentChanged passes the Torrent updates to act on: spec changes and
+// deletion, not the status updates of each poll, which is scheduled
+var torrentChanged = predicate.Or[client.Object](
+	predicate.GenerationChangedPredicate{},
+	predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !e.ObjectNew.GetDeletionTimestamp().Equal(e.ObjectOld.GetDeletionTimestamp()) ||
+				!slices.Equal(e.ObjectNew.GetFinalizers(), e.ObjectOld.GetFinalizers())
+		},
+	},
+)
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TorrentReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&torrentsv1alpha1.Torrent{}, builder.WithPredicates(torrentChanged)).
+		Watches(&torrentsv1alpha1.DownloadClient{}, handler.EnqueueRequestsFromMapFunc(r.pendingTorrents)).
+		Complete(r)
+}
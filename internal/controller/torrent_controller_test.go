@@ -0,0 +1,298 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/downloadclient"
+)
+
+var _ = Describe("Torrent Controller", func() {
+	const (
+		timeout  = time.Second * 10
+		interval = time.Millisecond * 250
+	)
+
+	Context("When a DownloadClient is available", func() {
+		It("Should hand the torrent to qBittorrent and remove it on deletion", func() {
+			ctx := context.Background()
+
+			// qBittorrent without authentication, as for a whitelisted subnet
+			var mu sync.Mutex
+			var added, deleted []string
+			var polls int
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v2/app/version", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.WriteString(w, "v4.6.7")
+			})
+			mux.HandleFunc("POST /api/v2/torrents/add", func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				added = append(added, r.FormValue("urls")+" "+r.FormValue("category"))
+				_, _ = io.WriteString(w, "Ok.")
+			})
+			mux.HandleFunc("POST /api/v2/torrents/info", func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				polls++
+				mu.Unlock()
+				_, _ = io.WriteString(w, `[{"hash":"`+r.FormValue("hashes")+`","state":"downloading","progress":0.5,"dlspeed":1572864,"eta":600,"ratio":0.1,"save_path":"/downloads/movies","num_seeds":3}]`)
+			})
+			mux.HandleFunc("POST /api/v2/torrents/delete", func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				deleted = append(deleted, r.FormValue("hashes"))
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			dc := &torrentsv1alpha1.DownloadClient{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-qbittorrent", Namespace: "default"},
+				Spec: torrentsv1alpha1.DownloadClientSpec{
+					Type:     torrentsv1alpha1.DownloadClientQBittorrent,
+					URL:      server.URL,
+					Category: "movies",
+					Default:  true,
+				},
+			}
+			Expect(k8sClient.Create(ctx, dc)).To(Succeed())
+			defer func() { _ = k8sClient.Delete(ctx, dc) }()
+
+			magnet := "magnet:?xt=urn:btih:0123456789ABCDEF0123456789ABCDEF01234567"
+			t := &torrentsv1alpha1.Torrent{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-download", Namespace: "default"},
+				Spec:       torrentsv1alpha1.TorrentSpec{Title: "Some.Movie.2020.1080p", Magnet: magnet},
+			}
+			Expect(k8sClient.Create(ctx, t)).To(Succeed())
+
+			key := types.NamespacedName{Name: "test-download", Namespace: "default"}
+			created := &torrentsv1alpha1.Torrent{}
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, key, created); err != nil {
+					return ""
+				}
+				return created.Status.Hash
+			}, timeout, interval).Should(Equal("0123456789abcdef0123456789abcdef01234567"))
+			Expect(created.Status.DownloadClient).To(Equal("test-qbittorrent"))
+			Expect(created.Finalizers).To(ContainElement(downloadClientFinalizer))
+			Expect(meta.IsStatusConditionTrue(created.Status.Conditions, "Ready")).To(BeTrue())
+			mu.Lock()
+			Expect(added).To(ContainElement(magnet + " movies"))
+			mu.Unlock()
+
+			// The download is tracked once added
+			Eventually(func() torrentsv1alpha1.TorrentPhase {
+				if err := k8sClient.Get(ctx, key, created); err != nil {
+					return ""
+				}
+				return created.Status.Phase
+			}, timeout, interval).Should(Equal(torrentsv1alpha1.TorrentDownloading))
+			Expect(created.Status.Progress).To(Equal("50.0%"))
+			Expect(created.Status.DownloadRate).To(Equal("1.5 MiB/s"))
+			Expect(created.Status.ETA.Duration).To(Equal(10 * time.Minute))
+			Expect(created.Status.SavePath).To(Equal("/downloads/movies"))
+
+			// Status updates do not trigger a poll each, the next one is scheduled
+			polled := func() int {
+				mu.Lock()
+				defer mu.Unlock()
+				return polls
+			}
+			before := polled()
+			Consistently(polled, 3*time.Second, interval).Should(BeNumerically("<=", before+1))
+
+			Expect(k8sClient.Delete(ctx, created)).To(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, key, created))
+			}, timeout, interval).Should(BeTrue())
+			mu.Lock()
+			Expect(deleted).To(ContainElement("0123456789abcdef0123456789abcdef01234567"))
+			mu.Unlock()
+		})
+	})
+})
+
+var _ = Describe("defaultDownloadClient", func() {
+	dc := func(name string, def bool) torrentsv1alpha1.DownloadClient {
+		return torrentsv1alpha1.DownloadClient{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       torrentsv1alpha1.DownloadClientSpec{Default: def},
+		}
+	}
+	name := func(clients ...torrentsv1alpha1.DownloadClient) string {
+		if d := defaultDownloadClient(clients); d != nil {
+			return d.Name
+		}
+		return ""
+	}
+
+	It("picks the default client", func() {
+		Expect(name(dc("a", false), dc("b", true))).To(Equal("b"))
+		Expect(name(dc("c", true), dc("b", true))).To(Equal("b"))
+	})
+
+	It("falls back to the only client", func() {
+		Expect(name(dc("a", false))).To(Equal("a"))
+		Expect(name(dc("a", false), dc("b", false))).To(BeEmpty())
+		Expect(name()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("trackProgress", func() {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	track := func(ts *torrentsv1alpha1.TorrentStatus, s downloadclient.Status, at time.Time) *torrentsv1alpha1.TorrentStatus {
+		trackProgress(ts, &s, 30*time.Minute, at)
+		return ts
+	}
+
+	DescribeTable("maps the client state to a phase",
+		func(state downloadclient.State, progress float64, phase torrentsv1alpha1.TorrentPhase) {
+			ts := track(&torrentsv1alpha1.TorrentStatus{}, downloadclient.Status{State: state, Progress: progress, Seeders: 1}, now)
+			Expect(ts.Phase).To(Equal(phase))
+		},
+		Entry("queued", downloadclient.StateQueued, 0.0, torrentsv1alpha1.TorrentQueued),
+		Entry("checking", downloadclient.StateChecking, 0.3, torrentsv1alpha1.TorrentQueued),
+		Entry("downloading", downloadclient.StateDownloading, 0.3, torrentsv1alpha1.TorrentDownloading),
+		Entry("paused", downloadclient.StatePaused, 0.3, torrentsv1alpha1.TorrentQueued),
+		Entry("paused once complete", downloadclient.StatePaused, 1.0, torrentsv1alpha1.TorrentCompleted),
+		Entry("seeding", downloadclient.StateSeeding, 1.0, torrentsv1alpha1.TorrentSeeding),
+		Entry("error", downloadclient.StateError, 0.3, torrentsv1alpha1.TorrentError),
+	)
+
+	It("records the progress", func() {
+		ts := track(&torrentsv1alpha1.TorrentStatus{}, downloadclient.Status{
+			State: downloadclient.StateDownloading, Progress: 0.425, DownloadRate: 2516582,
+			ETA: 90*time.Second + time.Millisecond, Ratio: 0.125, SavePath: "/downloads", Seeders: 2,
+		}, now)
+		Expect(ts.Progress).To(Equal("42.5%"))
+		Expect(ts.DownloadRate).To(Equal("2.4 MiB/s"))
+		Expect(ts.ETA.Duration).To(Equal(90 * time.Second))
+		Expect(ts.Ratio).To(Equal("0.12"))
+		Expect(ts.SavePath).To(Equal("/downloads"))
+		Expect(ts.CompletedAt).To(BeNil())
+
+		completedAt := now.Add(-time.Hour)
+		ts = track(ts, downloadclient.Status{State: downloadclient.StateSeeding, Progress: 1, CompletedAt: completedAt}, now)
+		Expect(ts.ETA).To(BeNil())
+		Expect(ts.CompletedAt.Time).To(Equal(completedAt))
+	})
+
+	It("marks downloads without progress nor seeders stalled", func() {
+		stuck := downloadclient.Status{State: downloadclient.StateDownloading, Progress: 0.1}
+		ts := track(&torrentsv1alpha1.TorrentStatus{}, stuck, now)
+		Expect(ts.Phase).To(Equal(torrentsv1alpha1.TorrentDownloading))
+
+		ts = track(ts, stuck, now.Add(29*time.Minute))
+		Expect(ts.Phase).To(Equal(torrentsv1alpha1.TorrentDownloading))
+		ts = track(ts, stuck, now.Add(30*time.Minute))
+		Expect(ts.Phase).To(Equal(torrentsv1alpha1.TorrentStalled))
+
+		// A seeder showing up is enough to wait
+		withSeeder := stuck
+		withSeeder.Seeders = 1
+		Expect(track(ts.DeepCopy(), withSeeder, now.Add(31*time.Minute)).Phase).To(Equal(torrentsv1alpha1.TorrentDownloading))
+
+		// Progress resets the timeout
+		stuck.Progress = 0.2
+		ts = track(ts, stuck, now.Add(31*time.Minute))
+		Expect(ts.Phase).To(Equal(torrentsv1alpha1.TorrentDownloading))
+		Expect(ts.LastActivityTime.Time).To(Equal(now.Add(31 * time.Minute)))
+	})
+
+	It("keeps the last activity until progress is made", func() {
+		seeding := downloadclient.Status{State: downloadclient.StateSeeding, Progress: 1, UploadRate: 1024}
+		ts := track(&torrentsv1alpha1.TorrentStatus{}, seeding, now)
+		ts = track(ts, seeding, now.Add(time.Hour))
+		Expect(ts.LastActivityTime.Time).To(Equal(now))
+
+		downloading := downloadclient.Status{State: downloadclient.StateDownloading, Progress: 0.1, DownloadRate: 10}
+		ts = track(&torrentsv1alpha1.TorrentStatus{}, downloadclient.Status{State: downloadclient.StateQueued, Progress: 0.1}, now)
+		ts = track(ts, downloadclient.Status{State: downloadclient.StateQueued, Progress: 0.1}, now.Add(time.Hour))
+		Expect(ts.LastActivityTime.Time).To(Equal(now))
+		// Starting to download restarts the timeout
+		ts = track(ts, downloading, now.Add(2*time.Hour))
+		Expect(ts.LastActivityTime.Time).To(Equal(now.Add(2 * time.Hour)))
+		ts = track(ts, downloading, now.Add(3*time.Hour))
+		Expect(ts.LastActivityTime.Time).To(Equal(now.Add(2 * time.Hour)))
+	})
+
+	It("never marks downloads stalled without a timeout", func() {
+		ts := &torrentsv1alpha1.TorrentStatus{}
+		stuck := &downloadclient.Status{State: downloadclient.StateDownloading}
+		trackProgress(ts, stuck, 0, now)
+		trackProgress(ts, stuck, 0, now.Add(24*time.Hour))
+		Expect(ts.Phase).To(Equal(torrentsv1alpha1.TorrentDownloading))
+	})
+})
+
+var _ = Describe("torrentChanged", func() {
+	torrent := func(generation int64, status torrentsv1alpha1.TorrentPhase, finalizers ...string) *torrentsv1alpha1.Torrent {
+		return &torrentsv1alpha1.Torrent{
+			ObjectMeta: metav1.ObjectMeta{Generation: generation, Finalizers: finalizers},
+			Status:     torrentsv1alpha1.TorrentStatus{Phase: status},
+		}
+	}
+
+	It("ignores status updates", func() {
+		Expect(torrentChanged.Update(event.UpdateEvent{
+			ObjectOld: torrent(1, torrentsv1alpha1.TorrentQueued),
+			ObjectNew: torrent(1, torrentsv1alpha1.TorrentDownloading),
+		})).To(BeFalse())
+	})
+
+	It("passes spec changes, finalizers and deletion", func() {
+		Expect(torrentChanged.Update(event.UpdateEvent{
+			ObjectOld: torrent(1, ""), ObjectNew: torrent(2, ""),
+		})).To(BeTrue())
+		Expect(torrentChanged.Update(event.UpdateEvent{
+			ObjectOld: torrent(1, ""), ObjectNew: torrent(1, "", downloadClientFinalizer),
+		})).To(BeTrue())
+
+		deleted := torrent(1, "", downloadClientFinalizer)
+		deleted.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		Expect(torrentChanged.Update(event.UpdateEvent{
+			ObjectOld: torrent(1, "", downloadClientFinalizer), ObjectNew: deleted,
+		})).To(BeTrue())
+	})
+})
+
+var _ = Describe("pollInterval", func() {
+	eta := func(d time.Duration) *metav1.Duration { return &metav1.Duration{Duration: d} }
+
+	DescribeTable("polls active torrents more often",
+		func(ts torrentsv1alpha1.TorrentStatus, interval time.Duration) {
+			Expect(pollInterval(&ts)).To(Equal(interval))
+		},
+		Entry("downloading", torrentsv1alpha1.TorrentStatus{Phase: torrentsv1alpha1.TorrentDownloading, ETA: eta(time.Hour)}, activePollInterval),
+		Entry("about to complete", torrentsv1alpha1.TorrentStatus{Phase: torrentsv1alpha1.TorrentDownloading, ETA: eta(12 * time.Second)}, 12*time.Second),
+		Entry("completing now", torrentsv1alpha1.TorrentStatus{Phase: torrentsv1alpha1.TorrentDownloading, ETA: eta(time.Second)}, minPollInterval),
+		Entry("queued", torrentsv1alpha1.TorrentStatus{Phase: torrentsv1alpha1.TorrentQueued}, queuedPollInterval),
+		Entry("stalled", torrentsv1alpha1.TorrentStatus{Phase: torrentsv1alpha1.TorrentStalled}, queuedPollInterval),
+		Entry("seeding", torrentsv1alpha1.TorrentStatus{Phase: torrentsv1alpha1.TorrentSeeding}, idlePollInterval),
+		Entry("completed", torrentsv1alpha1.TorrentStatus{Phase: torrentsv1alpha1.TorrentCompleted}, idlePollInterval),
+	)
+})
+
+var _ = Describe("formatRate", func() {
+	DescribeTable("formats binary units",
+		func(rate int64, formatted string) {
+			Expect(formatRate(rate)).To(Equal(formatted))
+		},
+		Entry("bytes", int64(0), "0 B/s"),
+		Entry("kibibytes", int64(1536), "1.5 KiB/s"),
+		Entry("mebibytes", int64(10<<20), "10.0 MiB/s"),
+		Entry("gibibytes", int64(3<<30), "3.0 GiB/s"),
+	)
+})
@@ -3,53 +3,109 @@ package controller
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"regexp"
-	"sort"
+	"slices"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
 	"vitoru.fun/torrents/internal/parser"
+	"vitoru.fun/torrents/internal/releaseparse"
+	"vitoru.fun/torrents/internal/search"
+	"vitoru.fun/torrents/internal/selection"
+	"vitoru.fun/torrents/internal/solver"
 )
 
 // TorrentRequestReconciler reconciles a TorrentRequest object
 type TorrentRequestReconciler struct {
 	client.Client
-	Scheme          *runtime.Scheme
-	HTTPClient      *http.Client
-	FlareSolverrURL string
+	Scheme     *runtime.Scheme
+	HTTPClient *http.Client
+	// Solvers holds the challenge solvers indexers may go through, nil when
+	// none is deployed
+	Solvers *solver.Registry
+	// ProxyURL is the default proxy for indexers without their own
+	ProxyURL string
+
+	// RSSSync is true when the RSS sync offers new releases to monitored
+	// requests, which then search less often by default
+	RSSSync bool
+
+	clients *indexerClients
+	// expressions caches the compiled filter and sortBy expressions
+	expressions selection.ExpressionCache
+	// offers holds the releases the RSS sync found for monitored requests
+	// until they are reconciled
+	offers    releaseOffers
+	rssEvents chan event.GenericEvent
 }
 
 // +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=torrentrequests,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=torrentrequests/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=torrents,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=searchresults,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=qualityprofiles,verbs=get;list;watch
+// +kubebuilder:rbac:groups=torrents.vitoru.fun,resources=customformats,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 
 func (r *TorrentRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	l := log.FromContext(ctx)
 
 	var tr torrentsv1alpha1.TorrentRequest
 	if err := r.Get(ctx, req.NamespacedName, &tr); err != nil {
+		r.offers.take(req.NamespacedName)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	if err := r.replaceStalled(ctx, &tr); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// If already completed or failed, stop
 	if tr.Status.State == "Completed" || tr.Status.State == "Failed" {
+		r.offers.take(req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 
+	if tr.Status.State == "AwaitingSelection" {
+		return r.awaitSelection(ctx, &tr)
+	}
+
+	var offered []parser.ParseResult
+	if tr.Status.State == "Monitoring" {
+		offered = r.offers.take(req.NamespacedName)
+	}
+	if (tr.Status.State == "Retrying" || tr.Status.State == "Monitoring") && len(offered) == 0 {
+		if tr.Status.NextSearchTime != nil {
+			if wait := time.Until(tr.Status.NextSearchTime.Time); wait > 0 {
+				return ctrl.Result{RequeueAfter: wait}, nil
+			}
+		}
+		tr.Status.State = "Searching"
+		tr.Status.NextSearchTime = nil
+	}
+
 	// Update state to Searching if Pending/Empty
 	if tr.Status.State == "" || tr.Status.State == "Pending" {
 		tr.Status.State = "Searching"
@@ -66,7 +122,44 @@ func (r *TorrentRequestReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{Requeue: true}, nil
 	}
 
-	l.Info("Starting search for torrent", "keywords", tr.Spec.Keywords)
+	profile, err := r.qualityProfile(ctx, &tr)
+	if err != nil {
+		l.Error(err, "Quality profile is not usable")
+		if err := r.Status().Update(ctx, &tr); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+	if tr.Spec.UpgradeUntil != "" && (profile == nil || profile.IndexOf(tr.Spec.UpgradeUntil) < 0) {
+		meta.SetStatusCondition(&tr.Status.Conditions, metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidUpgradeUntil",
+			Message: fmt.Sprintf("upgradeUntil %q is not a quality of the quality profile", tr.Spec.UpgradeUntil),
+		})
+		l.Info("Invalid upgradeUntil", "upgradeUntil", tr.Spec.UpgradeUntil)
+		if err := r.Status().Update(ctx, &tr); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	opts, err := r.selectionOptions(&tr)
+	if err != nil {
+		// Fixing the spec triggers a new reconcile, there is no point retrying
+		l.Error(err, "Invalid selection rules")
+		if err := r.Status().Update(ctx, &tr); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if len(offered) > 0 {
+		return r.evaluateOffered(ctx, &tr, profile, opts, offered)
+	}
+
+	query := search.RequestQuery(&tr)
+	l.Info("Starting search for torrent", "keywords", query.SearchTerm(), "mode", query.Mode)
 
 	// List Indexers
 	var indexerList torrentsv1alpha1.IndexerList
@@ -75,7 +168,11 @@ func (r *TorrentRequestReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, err
 	}
 
+	tr.Status.Attempts++
+	tr.Status.LastSearchTime = &metav1.Time{Time: time.Now()}
+
 	var allResults []parser.ParseResult
+	searched, failed := 0, 0
 
 	// Iterate and search
 	for _, indexer := range indexerList.Items {
@@ -98,10 +195,12 @@ func (r *TorrentRequestReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}
 
 		l.Info("Querying indexer", "name", indexer.Name)
-		results, err := r.searchIndexer(ctx, &indexer, tr.Spec.Keywords)
+		searched++
+		results, err := r.Search(ctx, &indexer, query)
 		if err != nil {
 			l.Error(err, "Search failed for indexer", "name", indexer.Name)
 			torrentSearchesTotal.WithLabelValues(indexer.Name, "failed").Inc()
+			failed++
 			continue
 		}
 
@@ -113,114 +212,547 @@ func (r *TorrentRequestReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 		l.Info("Found results", "indexer", indexer.Name, "count", len(results))
 
-		// Resolve Magnet links that are actually Detail pages
-		for i := range results {
-			// Quick fix to ensure indexer name is populated if parser didn't do it
-			if results[i].Indexer == "" {
-				results[i].Indexer = indexer.Name
-			}
+		allResults = append(allResults, results...)
+	}
 
-			// If Magnet is a URL (not magnet:), and we have a Download block, attempt to fetch details
-			if strings.HasPrefix(results[i].Magnet, "http") && !strings.HasPrefix(results[i].Magnet, "magnet:") && indexer.Spec.Download != nil {
-				l.Info("Fetching details page for magnet", "url", results[i].Magnet)
-
-				var detBody []byte
-				var detErr error
-
-				// Reuse FlareSolverr logic if enabled for this indexer
-				useFS := false
-				if r.FlareSolverrURL != "" {
-					for _, setting := range indexer.Spec.Settings {
-						if setting.Type == "info_flaresolverr" {
-							useFS = true
-							break
-						}
-					}
-				}
+	tr.Status.ResultsFound = len(allResults)
+	tr.Status.Candidates = nil
+	tr.Status.Eliminations = nil
 
-				if useFS {
-					detBody, detErr = r.doRequestFS(ctx, results[i].Magnet)
-				} else {
-					req, _ := http.NewRequestWithContext(ctx, "GET", results[i].Magnet, nil)
-					req.Header.Set("User-Agent", "Prowlarr/1.0 (Text-Mode-Operator)")
-					resp, er := r.HTTPClient.Do(req)
-					if er != nil {
-						detErr = er
-					} else {
-						defer resp.Body.Close()
-						detBody, detErr = io.ReadAll(resp.Body)
-					}
-				}
+	if len(allResults) == 0 {
+		reason, message := "NoResults", "No results found across all indexers"
+		switch {
+		case searched == 0:
+			reason, message = "NoIndexers", "No healthy indexer to search"
+		case failed == searched:
+			reason, message = "AllIndexersFailed", fmt.Sprintf("All %d indexers failed to search", searched)
+		}
+		l.Info(message)
+		r.syncSearchResults(ctx, &tr, nil, false)
+		return r.searchFailed(ctx, &tr, reason, message)
+	}
 
-				if detErr != nil {
-					l.Error(detErr, "Failed to fetch details page", "url", results[i].Magnet)
-					continue
-				}
+	formats, err := r.customFormats(ctx, tr.Namespace)
+	if err != nil {
+		l.Error(err, "Failed to list custom formats")
+		return ctrl.Result{}, err
+	}
 
-				// Parse details
-				magnetLink, parseErr := parser.ParseDetailsPage(string(detBody), indexer.Spec.Download)
-				if parseErr == nil && magnetLink != "" {
-					l.Info("Successfully resolved magnet from details", "magnet", magnetLink)
-					results[i].Magnet = magnetLink
-				} else {
-					l.Error(parseErr, "Failed to parse magnet from details page")
-				}
-			}
+	opts.Profile = profile
+	opts.Formats = formats
+	candidates := selection.Select(allResults, opts)
+	manual := tr.Spec.Selection == torrentsv1alpha1.SelectionManual
+	tr.Status.Candidates = candidateStatuses(candidates, !manual)
+	tr.Status.Eliminations = eliminations(candidates)
+	r.syncSearchResults(ctx, &tr, candidates, !manual)
+
+	if manual {
+		// A user may pick a rejected result, so the request waits even when
+		// none is acceptable
+		tr.Status.State = "AwaitingSelection"
+		if tr.Spec.ApprovalTimeout != nil {
+			tr.Status.SelectionDeadline = &metav1.Time{Time: time.Now().Add(tr.Spec.ApprovalTimeout.Duration)}
 		}
-		allResults = append(allResults, results...)
+		meta.SetStatusCondition(&tr.Status.Conditions, metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionFalse,
+			Reason:  "AwaitingSelection",
+			Message: fmt.Sprintf("%d results found, set spec.selectedCandidate to pick one", len(candidates)),
+		})
+		l.Info("Awaiting candidate selection", "results", len(candidates))
+
+		if err := r.Status().Update(ctx, &tr); err != nil {
+			return ctrl.Result{}, err
+		}
+		// A candidate picked during the search is selected right away
+		return ctrl.Result{Requeue: selectedCandidate(&tr) != "", RequeueAfter: selectionWait(&tr)}, nil
 	}
 
-	tr.Status.ResultsFound = len(allResults)
+	if tr.Spec.Mode == torrentsv1alpha1.ModeMonitor {
+		return r.monitor(ctx, &tr, profile, candidates)
+	}
 
-	if len(allResults) == 0 {
-		tr.Status.State = "Failed"
+	if candidates[0].Rejected() {
+		l.Info("No acceptable result", "results", len(candidates))
+		return r.noAcceptableResult(ctx, &tr)
+	}
+	best := &candidates[0]
+	return r.createTorrent(ctx, &tr, torrentSpec(best), formatAnnotations(best.Formats, best.FormatScore))
+}
+
+// noAcceptableResult fails a request whose results were all rejected
+func (r *TorrentRequestReconciler) noAcceptableResult(ctx context.Context, tr *torrentsv1alpha1.TorrentRequest) (ctrl.Result, error) {
+	tr.Status.SelectionDeadline = nil
+	return r.searchFailed(ctx, tr, "NoAcceptableResult", noAcceptableResultMessage(tr.Status.ResultsFound, tr.Status.Eliminations))
+}
+
+// searchFailed schedules the next search of a request that found nothing
+// acceptable, or fails it once its retry policy is exhausted
+func (r *TorrentRequestReconciler) searchFailed(ctx context.Context, tr *torrentsv1alpha1.TorrentRequest, reason, message string) (ctrl.Result, error) {
+	if tr.Spec.Mode == torrentsv1alpha1.ModeMonitor {
+		// A monitored request never fails, and one that already has a
+		// Torrent stays ready while no upgrade shows up
+		if tr.Status.FoundTorrent == "" {
+			meta.SetStatusCondition(&tr.Status.Conditions, metav1.Condition{
+				Type:    "Ready",
+				Status:  metav1.ConditionFalse,
+				Reason:  reason,
+				Message: message,
+			})
+		}
+		log.FromContext(ctx).Info("Search found nothing, monitoring", "reason", reason)
+		return r.scheduleSearch(ctx, tr)
+	}
+
+	if next, ok := nextSearchTime(tr, time.Now()); ok {
+		tr.Status.State = "Retrying"
+		tr.Status.NextSearchTime = &metav1.Time{Time: next}
 		meta.SetStatusCondition(&tr.Status.Conditions, metav1.Condition{
 			Type:    "Ready",
 			Status:  metav1.ConditionFalse,
-			Reason:  "NotFound",
-			Message: "No results found across all indexers",
+			Reason:  reason,
+			Message: fmt.Sprintf("%s, searching again at %s", message, next.UTC().Format(time.RFC3339)),
 		})
-		l.Info("No results found across all indexers")
+		log.FromContext(ctx).Info("Search failed, retrying", "reason", reason, "attempts", tr.Status.Attempts, "next", next)
+
+		if err := r.Status().Update(ctx, tr); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: time.Until(next)}, nil
+	}
 
-		// Record Duration and Failure Count
-		duration := time.Since(tr.CreationTimestamp.Time).Seconds()
-		torrentRequestFailureDuration.Observe(duration)
-		torrentRequestsFailedTotal.Inc()
+	tr.Status.State = "Failed"
+	tr.Status.NextSearchTime = nil
+	meta.SetStatusCondition(&tr.Status.Conditions, metav1.Condition{
+		Type:    "Ready",
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
 
-		if err := r.Status().Update(ctx, &tr); err != nil {
+	// Record Duration and Failure Count
+	duration := time.Since(tr.CreationTimestamp.Time).Seconds()
+	torrentRequestFailureDuration.Observe(duration)
+	torrentRequestsFailedTotal.Inc()
+
+	if err := r.Status().Update(ctx, tr); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// monitor creates the Torrent of a monitored request once an acceptable
+// result shows up, then replaces it with better qualities until the request
+// reaches its upgradeUntil quality
+func (r *TorrentRequestReconciler) monitor(ctx context.Context, tr *torrentsv1alpha1.TorrentRequest, profile *selection.Profile, candidates []selection.Candidate) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	var current torrentsv1alpha1.Torrent
+	if tr.Status.FoundTorrent != "" {
+		err := r.Get(ctx, types.NamespacedName{Name: tr.Status.FoundTorrent, Namespace: tr.Namespace}, &current)
+		if errors.IsNotFound(err) {
+			l.Info("Torrent was deleted, grabbing again", "torrent", tr.Status.FoundTorrent)
+			tr.Status.FoundTorrent = ""
+		} else if err != nil {
 			return ctrl.Result{}, err
 		}
-		return ctrl.Result{}, nil
 	}
 
-	// Sort and Pick Best
-	sort.Slice(allResults, func(i, j int) bool {
-		return allResults[i].Seeders > allResults[j].Seeders
+	if tr.Status.FoundTorrent == "" {
+		best := &candidates[0]
+		status := candidateStatus(best, true)
+		tr.Status.BestCandidate = &status
+		if best.Rejected() {
+			l.Info("No acceptable result", "results", len(candidates))
+			return r.noAcceptableResult(ctx, tr)
+		}
+		return r.grab(ctx, tr, profile, best, nil)
+	}
+
+	var resolution, source string
+	if current.Spec.Release != nil {
+		resolution, source = current.Spec.Release.Resolution, current.Spec.Release.Source
+	}
+	upgrade := upgradeCandidate(profile, candidates, resolution, source)
+	if upgrade == nil {
+		l.Info("No upgrade found", "torrent", current.Name)
+		return r.scheduleSearch(ctx, tr)
+	}
+	l.Info("Upgrading torrent", "torrent", current.Name, "title", upgrade.Title)
+	return r.grab(ctx, tr, profile, upgrade, &current)
+}
+
+// evaluateOffered ranks the releases the RSS sync offered a monitored
+// request, keeping the candidates of its last search in its status
+func (r *TorrentRequestReconciler) evaluateOffered(ctx context.Context, tr *torrentsv1alpha1.TorrentRequest, profile *selection.Profile, opts selection.Options, results []parser.ParseResult) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	formats, err := r.customFormats(ctx, tr.Namespace)
+	if err != nil {
+		l.Error(err, "Failed to list custom formats")
+		return ctrl.Result{}, err
+	}
+	opts.Profile = profile
+	opts.Formats = formats
+	candidates := selection.Select(results, opts)
+
+	l.Info("Evaluating releases from the RSS sync", "results", len(results))
+	if candidates[0].Rejected() {
+		l.Info("No acceptable release from the RSS sync", "reason", candidates[0].Reason())
+		return r.scheduleSearch(ctx, tr)
+	}
+	return r.monitor(ctx, tr, profile, candidates)
+}
+
+// grab creates the Torrent of a monitored request from c, deleting the
+// Torrent it replaces, and completes the request once c is good enough
+func (r *TorrentRequestReconciler) grab(ctx context.Context, tr *torrentsv1alpha1.TorrentRequest, profile *selection.Profile, c *selection.Candidate, replaced *torrentsv1alpha1.Torrent) (ctrl.Result, error) {
+	name, err := r.newTorrent(ctx, tr, torrentSpec(c), formatAnnotations(c.Formats, c.FormatScore))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if replaced != nil {
+		if err := r.Delete(ctx, replaced); client.IgnoreNotFound(err) != nil {
+			log.FromContext(ctx).Error(err, "Failed to delete replaced Torrent", "torrent", replaced.Name)
+		}
+	}
+	status := candidateStatus(c, true)
+	tr.Status.BestCandidate = &status
+
+	if upgradeDone(tr, profile, c.Release.Resolution, c.Release.Source) {
+		return r.complete(ctx, tr, name, c.Indexer)
+	}
+	tr.Status.FoundTorrent = name
+	meta.SetStatusCondition(&tr.Status.Conditions, metav1.Condition{
+		Type:    "Ready",
+		Status:  metav1.ConditionTrue,
+		Reason:  "Found",
+		Message: fmt.Sprintf("Found torrent: %s, upgrading until %s", name, tr.Spec.UpgradeUntil),
 	})
+	return r.scheduleSearch(ctx, tr)
+}
+
+// scheduleSearch leaves a monitored request waiting for its next search.
+// Releases offered by the RSS sync leave a pending search where it was.
+func (r *TorrentRequestReconciler) scheduleSearch(ctx context.Context, tr *torrentsv1alpha1.TorrentRequest) (ctrl.Result, error) {
+	if tr.Status.NextSearchTime == nil || !tr.Status.NextSearchTime.After(time.Now()) {
+		tr.Status.NextSearchTime = &metav1.Time{Time: time.Now().Add(r.searchInterval(tr))}
+	}
+	tr.Status.State = "Monitoring"
+	if err := r.Status().Update(ctx, tr); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: time.Until(tr.Status.NextSearchTime.Time)}, nil
+}
 
-	var bestTorrent *parser.ParseResult
-	for i := range allResults {
-		if allResults[i].Seeders >= tr.Spec.MinSeeders {
-			bestTorrent = &allResults[i]
-			break
+const (
+	// defaultSearchInterval is the delay between searches of a monitored
+	// request
+	defaultSearchInterval = time.Hour
+	// defaultRSSSearchInterval replaces it when the RSS sync offers new
+	// releases, searches then only catch what the feeds missed
+	defaultRSSSearchInterval = 24 * time.Hour
+)
+
+// searchInterval is the delay between searches of a monitored request
+func (r *TorrentRequestReconciler) searchInterval(tr *torrentsv1alpha1.TorrentRequest) time.Duration {
+	if tr.Spec.SearchInterval != nil && tr.Spec.SearchInterval.Duration > 0 {
+		return tr.Spec.SearchInterval.Duration
+	}
+	if r.RSSSync {
+		return defaultRSSSearchInterval
+	}
+	return defaultSearchInterval
+}
+
+// upgradeDone reports whether a monitored request holding a release of this
+// resolution and source stops looking for better ones, having reached its
+// upgradeUntil quality or the cutoff of its profile
+func upgradeDone(tr *torrentsv1alpha1.TorrentRequest, profile *selection.Profile, resolution, source string) bool {
+	if tr.Spec.UpgradeUntil == "" || profile == nil {
+		return true
+	}
+	return profile.QualityIndex(resolution, source) <= profile.IndexOf(tr.Spec.UpgradeUntil) ||
+		profile.ReachedCutoff(resolution, source)
+}
+
+// upgradeCandidate is the accepted candidate of the best quality above the
+// one of this resolution and source, nil when there is none
+func upgradeCandidate(profile *selection.Profile, candidates []selection.Candidate, resolution, source string) *selection.Candidate {
+	if profile == nil {
+		return nil
+	}
+	var upgrade *selection.Candidate
+	best := profile.QualityIndex(resolution, source)
+	for i := range candidates {
+		c := &candidates[i]
+		if c.Rejected() {
+			continue
+		}
+		if index := profile.QualityIndex(c.Release.Resolution, c.Release.Source); index < best {
+			upgrade, best = c, index
 		}
 	}
+	return upgrade
+}
 
-	if bestTorrent == nil {
-		if tr.Spec.MinSeeders > 0 {
-			l.Info("No results met minSeeders requirement")
-			tr.Status.State = "Failed"
-			if err := r.Status().Update(ctx, &tr); err != nil {
-				l.Error(err, "Failed to update status")
-				return ctrl.Result{}, err
-			}
-			return ctrl.Result{}, nil
+const (
+	// defaultRetryBackoff is the delay before the first retry of a request
+	defaultRetryBackoff = 5 * time.Minute
+	// maxRetryBackoff caps the doubling delay between retries
+	maxRetryBackoff = 24 * time.Hour
+)
+
+// nextSearchTime is when a request whose search failed at now searches
+// again, false once its retry policy is exhausted
+func nextSearchTime(tr *torrentsv1alpha1.TorrentRequest, now time.Time) (time.Time, bool) {
+	policy := tr.Spec.Retry
+	if policy == nil {
+		return time.Time{}, false
+	}
+	if policy.MaxAttempts > 0 && tr.Status.Attempts >= policy.MaxAttempts {
+		return time.Time{}, false
+	}
+
+	delay := defaultRetryBackoff
+	if policy.Backoff != nil && policy.Backoff.Duration > 0 {
+		delay = policy.Backoff.Duration
+	}
+	for i := 1; i < tr.Status.Attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	next := now.Add(min(delay, maxRetryBackoff))
+
+	if policy.RetryUntil != nil && next.After(policy.RetryUntil.Time) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// awaitSelection creates the Torrent of a manual request from the candidate
+// a user picked, or from the best ranked one once the approval timeout has
+// passed
+func (r *TorrentRequestReconciler) awaitSelection(ctx context.Context, tr *torrentsv1alpha1.TorrentRequest) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	selected := selectedCandidate(tr)
+	timedOut := false
+	if selected == "" {
+		wait := selectionWait(tr)
+		if tr.Status.SelectionDeadline == nil || wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, nil
 		}
-		bestTorrent = &allResults[0]
+		l.Info("Approval timed out, selecting the best ranked candidate")
+		selected = "1"
+		timedOut = true
+	}
+
+	sr, err := r.searchResult(ctx, tr, selected)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if timedOut && (sr == nil || sr.Spec.Rejected) {
+		l.Info("No acceptable result to select", "results", tr.Status.ResultsFound)
+		return r.noAcceptableResult(ctx, tr)
+	}
+	if sr == nil {
+		// Picking another candidate triggers a new reconcile
+		meta.SetStatusCondition(&tr.Status.Conditions, metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidSelection",
+			Message: fmt.Sprintf("Candidate %q is not among the search results", selected),
+		})
+		if err := r.Status().Update(ctx, tr); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: selectionWait(tr)}, nil
+	}
+
+	l.Info("Candidate selected", "position", sr.Spec.Position, "title", sr.Spec.Title)
+	sr.Spec.Chosen = true
+	if err := r.Update(ctx, sr); err != nil {
+		l.Error(err, "Failed to mark search result chosen", "name", sr.Name)
+	}
+	if i := sr.Spec.Position - 1; i < len(tr.Status.Candidates) {
+		tr.Status.Candidates[i].Chosen = true
+	}
+
+	return r.createTorrent(ctx, tr, searchResultTorrentSpec(sr), formatAnnotations(sr.Spec.Formats, sr.Spec.FormatScore))
+}
+
+// searchResultTorrentSpec describes the Torrent of a SearchResult
+func searchResultTorrentSpec(sr *torrentsv1alpha1.SearchResult) torrentsv1alpha1.TorrentSpec {
+	return torrentsv1alpha1.TorrentSpec{
+		Title:    sr.Spec.Title,
+		Magnet:   sr.Spec.Magnet,
+		InfoHash: sr.Spec.InfoHash,
+		Size:     sr.Spec.Size,
+		Seeders:  sr.Spec.Seeders,
+		Leechers: sr.Spec.Leechers,
+		Indexer:  sr.Spec.Indexer,
+		Release:  sr.Spec.Release,
+	}
+}
+
+// replaceStalled replaces the Torrent of a completed or monitored request
+// that stalled with the best ranked SearchResult not tried yet
+func (r *TorrentRequestReconciler) replaceStalled(ctx context.Context, tr *torrentsv1alpha1.TorrentRequest) error {
+	if tr.Status.FoundTorrent == "" || (tr.Status.State != "Completed" && tr.Status.State != "Monitoring") {
+		return nil
+	}
+	var stalled torrentsv1alpha1.Torrent
+	if err := r.Get(ctx, types.NamespacedName{Name: tr.Status.FoundTorrent, Namespace: tr.Namespace}, &stalled); err != nil {
+		return client.IgnoreNotFound(err)
 	}
+	if stalled.Status.Phase != torrentsv1alpha1.TorrentStalled || !metav1.IsControlledBy(&stalled, tr) {
+		return nil
+	}
+	l := log.FromContext(ctx)
+
+	var list torrentsv1alpha1.SearchResultList
+	if err := r.List(ctx, &list, client.InNamespace(tr.Namespace), client.MatchingLabels{"created-by": tr.Name}); err != nil {
+		return err
+	}
+	sr := nextCandidate(tr, list.Items, &stalled)
+	if sr == nil {
+		if c := meta.FindStatusCondition(tr.Status.Conditions, "Ready"); c != nil && c.Reason == "TorrentStalled" {
+			return nil
+		}
+		l.Info("Torrent stalled, no candidate left", "torrent", stalled.Name)
+		meta.SetStatusCondition(&tr.Status.Conditions, metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionFalse,
+			Reason:  "TorrentStalled",
+			Message: fmt.Sprintf("Torrent %s stalled and no other candidate is left", stalled.Name),
+		})
+		return r.Status().Update(ctx, tr)
+	}
+
+	l.Info("Torrent stalled, trying the next candidate", "torrent", stalled.Name, "position", sr.Spec.Position, "title", sr.Spec.Title)
+	name, err := r.newTorrent(ctx, tr, searchResultTorrentSpec(sr), formatAnnotations(sr.Spec.Formats, sr.Spec.FormatScore))
+	if err != nil {
+		return err
+	}
+	sr.Spec.Chosen = true
+	if err := r.Update(ctx, sr); err != nil {
+		l.Error(err, "Failed to mark search result chosen", "name", sr.Name)
+	}
+	if i := sr.Spec.Position - 1; i < len(tr.Status.Candidates) {
+		tr.Status.Candidates[i].Chosen = true
+	}
+	if err := r.Delete(ctx, &stalled); client.IgnoreNotFound(err) != nil {
+		l.Error(err, "Failed to delete stalled Torrent", "torrent", stalled.Name)
+	}
+
+	tr.Status.FoundTorrent = name
+	if tr.Status.BestCandidate != nil {
+		status := sr.Spec.CandidateStatus
+		tr.Status.BestCandidate = &status
+	}
+	meta.SetStatusCondition(&tr.Status.Conditions, metav1.Condition{
+		Type:    "Ready",
+		Status:  metav1.ConditionTrue,
+		Reason:  "Found",
+		Message: fmt.Sprintf("Torrent %s stalled, replaced with %s", stalled.Name, name),
+	})
+	return r.Status().Update(ctx, tr)
+}
+
+// nextCandidate is the best ranked acceptable SearchResult of the request
+// that was not tried yet, nil when none is left. Tried results are chosen,
+// or the release of the stalled Torrent.
+func nextCandidate(tr *torrentsv1alpha1.TorrentRequest, results []torrentsv1alpha1.SearchResult, stalled *torrentsv1alpha1.Torrent) *torrentsv1alpha1.SearchResult {
+	var next *torrentsv1alpha1.SearchResult
+	for i := range results {
+		sr := &results[i]
+		if !metav1.IsControlledBy(sr, tr) || sr.Spec.Chosen || sr.Spec.Rejected || sr.Spec.Magnet == "" {
+			continue
+		}
+		if sr.Spec.Title == stalled.Spec.Title || (sr.Spec.InfoHash != "" && strings.EqualFold(sr.Spec.InfoHash, stalled.Spec.InfoHash)) {
+			continue
+		}
+		if next == nil || sr.Spec.Position < next.Spec.Position {
+			next = sr
+		}
+	}
+	return next
+}
+
+// torrentStalled passes the Torrents that stalled, for their request to
+// replace them
+var torrentStalled = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return torrentPhase(e.Object) == torrentsv1alpha1.TorrentStalled
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return torrentPhase(e.ObjectNew) == torrentsv1alpha1.TorrentStalled && torrentPhase(e.ObjectOld) != torrentsv1alpha1.TorrentStalled
+	},
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+func torrentPhase(obj client.Object) torrentsv1alpha1.TorrentPhase {
+	if t, ok := obj.(*torrentsv1alpha1.Torrent); ok {
+		return t.Status.Phase
+	}
+	return ""
+}
+
+// selectedCandidate is the candidate a user picked, by spec or annotation
+func selectedCandidate(tr *torrentsv1alpha1.TorrentRequest) string {
+	if tr.Spec.SelectedCandidate != "" {
+		return tr.Spec.SelectedCandidate
+	}
+	return tr.Annotations[torrentsv1alpha1.SelectedCandidateAnnotation]
+}
+
+// searchResult finds the SearchResult of the request at a position or with
+// an infohash, nil when there is none
+func (r *TorrentRequestReconciler) searchResult(ctx context.Context, tr *torrentsv1alpha1.TorrentRequest, selected string) (*torrentsv1alpha1.SearchResult, error) {
+	var list torrentsv1alpha1.SearchResultList
+	if err := r.List(ctx, &list, client.InNamespace(tr.Namespace), client.MatchingLabels{"created-by": tr.Name}); err != nil {
+		return nil, err
+	}
+	position, _ := strconv.Atoi(selected)
+	for i := range list.Items {
+		sr := &list.Items[i]
+		if !metav1.IsControlledBy(sr, tr) {
+			continue
+		}
+		if sr.Spec.Position == position || (sr.Spec.InfoHash != "" && strings.EqualFold(sr.Spec.InfoHash, selected)) {
+			return sr, nil
+		}
+	}
+	return nil, nil
+}
+
+// selectionWait is how long until a request awaiting selection picks a
+// candidate itself, 0 when it waits for a user only
+func selectionWait(tr *torrentsv1alpha1.TorrentRequest) time.Duration {
+	if tr.Status.SelectionDeadline == nil {
+		return 0
+	}
+	// Never 0, which would stop the requeue
+	return max(time.Until(tr.Status.SelectionDeadline.Time), time.Second)
+}
+
+// createTorrent creates the Torrent the request found and completes it
+func (r *TorrentRequestReconciler) createTorrent(ctx context.Context, tr *torrentsv1alpha1.TorrentRequest, spec torrentsv1alpha1.TorrentSpec, annotations map[string]string) (ctrl.Result, error) {
+	name, err := r.newTorrent(ctx, tr, spec, annotations)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	return r.complete(ctx, tr, name, spec.Indexer)
+}
+
+// newTorrent creates a Torrent owned by the request and returns its name
+func (r *TorrentRequestReconciler) newTorrent(ctx context.Context, tr *torrentsv1alpha1.TorrentRequest, spec torrentsv1alpha1.TorrentSpec, annotations map[string]string) (string, error) {
+	l := log.FromContext(ctx)
 
 	// Create Torrent CR
-	safeName := strings.ToLower(strings.ReplaceAll(bestTorrent.Title, " ", "-"))
+	safeName := strings.ToLower(strings.ReplaceAll(spec.Title, " ", "-"))
 	safeName = strings.ReplaceAll(safeName, ".", "-")
 	reg, _ := regexp.Compile("[^a-z0-9-]+")
 	safeName = reg.ReplaceAllString(safeName, "")
@@ -228,6 +760,7 @@ func (r *TorrentRequestReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		safeName = safeName[:50]
 	}
 	safeName = fmt.Sprintf("%s-%d", safeName, time.Now().Unix())
+	spec.DownloadClientRef = tr.Spec.DownloadClientRef
 
 	torrentCR := &torrentsv1alpha1.Torrent{
 		ObjectMeta: metav1.ObjectMeta{
@@ -236,184 +769,520 @@ func (r *TorrentRequestReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			Labels: map[string]string{
 				"created-by": tr.Name,
 			},
+			Annotations: annotations,
 		},
-		Spec: torrentsv1alpha1.TorrentSpec{
-			Title:    bestTorrent.Title,
-			Magnet:   bestTorrent.Magnet,
-			Size:     bestTorrent.Size,
-			Seeders:  bestTorrent.Seeders,
-			Leechers: bestTorrent.Leechers,
-			Indexer:  bestTorrent.Indexer,
-		},
+		Spec: spec,
 	}
 
 	// Set OwnerReference
-	if err := ctrl.SetControllerReference(&tr, torrentCR, r.Scheme); err != nil {
+	if err := ctrl.SetControllerReference(tr, torrentCR, r.Scheme); err != nil {
 		l.Error(err, "Failed to set controller reference")
-		return ctrl.Result{}, err
+		return "", err
 	}
 
 	if err := r.Create(ctx, torrentCR); err != nil {
 		l.Error(err, "Failed to create Torrent CR")
-		return ctrl.Result{}, err
+		return "", err
 	}
+	torrentsCreatedTotal.WithLabelValues(spec.Indexer).Inc()
 
+	return safeName, nil
+}
+
+// complete marks the request as done with the given Torrent
+func (r *TorrentRequestReconciler) complete(ctx context.Context, tr *torrentsv1alpha1.TorrentRequest, torrentName, indexer string) (ctrl.Result, error) {
 	// Update Request Status
 	tr.Status.State = "Completed"
-	tr.Status.FoundTorrent = safeName
+	tr.Status.FoundTorrent = torrentName
+	tr.Status.SelectionDeadline = nil
+	tr.Status.NextSearchTime = nil
 	meta.SetStatusCondition(&tr.Status.Conditions, metav1.Condition{
 		Type:    "Ready",
 		Status:  metav1.ConditionTrue,
 		Reason:  "Found",
-		Message: fmt.Sprintf("Found torrent: %s", safeName),
+		Message: fmt.Sprintf("Found torrent: %s", torrentName),
 	})
 
 	// Record Duration
 	duration := time.Since(tr.CreationTimestamp.Time).Seconds()
-	torrentRequestDuration.WithLabelValues(bestTorrent.Indexer).Observe(duration)
-	torrentsCreatedTotal.WithLabelValues(bestTorrent.Indexer).Inc()
+	torrentRequestDuration.WithLabelValues(indexer).Observe(duration)
 
-	if err := r.Status().Update(ctx, &tr); err != nil {
+	if err := r.Status().Update(ctx, tr); err != nil {
 		return ctrl.Result{}, err
 	}
 
 	return ctrl.Result{}, nil
 }
 
-func (r *TorrentRequestReconciler) searchIndexer(ctx context.Context, indexer *torrentsv1alpha1.Indexer, keywords string) ([]parser.ParseResult, error) {
-	l := log.FromContext(ctx)
-	// Construct URL (Reuse logic from checkHealth ideally, but we need parameter injection)
-	if len(indexer.Spec.Links) == 0 {
-		return nil, fmt.Errorf("no links")
+// torrentSpec describes the Torrent of a candidate
+func torrentSpec(c *selection.Candidate) torrentsv1alpha1.TorrentSpec {
+	return torrentsv1alpha1.TorrentSpec{
+		Title:    c.Title,
+		Magnet:   c.Magnet,
+		InfoHash: parser.InfoHash(c.Magnet),
+		Size:     c.Size,
+		Seeders:  c.Seeders,
+		Leechers: c.Leechers,
+		Indexer:  c.Indexer,
+		Release:  releaseInfo(c.Release),
 	}
-	baseURL := strings.TrimRight(indexer.Spec.Links[0], "/")
-	targetURL := baseURL
+}
 
-	if indexer.Spec.Search != nil && len(indexer.Spec.Search.Paths) > 0 {
-		pathTmpl := indexer.Spec.Search.Paths[0].Path
+// maxCandidates bounds the candidates reported in the request status
+const maxCandidates = 10
 
-		// Prepare template data
-		data := struct {
-			Keywords string
-			Config   map[string]string
-		}{
-			Keywords: url.QueryEscape(keywords),
-			Config: map[string]string{
-				"username": "guest",
-				"password": "guest",
-			},
+// defaultSearchResults is how many SearchResults requests keep by default
+const defaultSearchResults = 20
+
+// qualityProfile loads the QualityProfile the request references, nil when
+// it has none. On failure the Ready condition explains why.
+func (r *TorrentRequestReconciler) qualityProfile(ctx context.Context, tr *torrentsv1alpha1.TorrentRequest) (*selection.Profile, error) {
+	if tr.Spec.QualityProfileRef == nil {
+		return nil, nil
+	}
+
+	var qp torrentsv1alpha1.QualityProfile
+	key := types.NamespacedName{Namespace: tr.Namespace, Name: tr.Spec.QualityProfileRef.Name}
+	if err := r.Get(ctx, key, &qp); err != nil {
+		reason := "QualityProfileUnavailable"
+		if errors.IsNotFound(err) {
+			reason = "QualityProfileNotFound"
 		}
+		meta.SetStatusCondition(&tr.Status.Conditions, metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: fmt.Sprintf("QualityProfile %s: %v", key.Name, err),
+		})
+		return nil, err
+	}
 
-		funcMap := template.FuncMap{
-			"re_replace": func(input, pattern, replacement string) string {
-				re, err := regexp.Compile(pattern)
-				if err != nil {
-					return input
-				}
-				return re.ReplaceAllString(input, replacement)
-			},
-			"replace": func(input, from, to string) string {
-				return strings.ReplaceAll(input, from, to)
-			},
+	profile, err := selection.NewProfile(qp.Spec)
+	if err != nil {
+		meta.SetStatusCondition(&tr.Status.Conditions, metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidQualityProfile",
+			Message: fmt.Sprintf("QualityProfile %s: %v", key.Name, err),
+		})
+		return nil, err
+	}
+	return profile, nil
+}
+
+// selectionOptions compiles the title words and the filter and sortBy
+// expressions of the request. On failure the Ready condition explains why.
+func (r *TorrentRequestReconciler) selectionOptions(tr *torrentsv1alpha1.TorrentRequest) (selection.Options, error) {
+	opts := selection.Options{MinSeeders: tr.Spec.MinSeeders}
+	words, err := selection.NewWords(tr.Spec.MustContain, tr.Spec.MustNotContain, tr.Spec.PreferredWords)
+	if err == nil {
+		opts.Words = words
+		if opts.Filter, err = r.expressions.Filter(tr.Spec.Filter); err != nil {
+			err = fmt.Errorf("filter: %w", err)
+		}
+	}
+	if err == nil {
+		if opts.SortBy, err = r.expressions.SortBy(tr.Spec.SortBy); err != nil {
+			err = fmt.Errorf("sortBy: %w", err)
 		}
+	}
+	if err != nil {
+		meta.SetStatusCondition(&tr.Status.Conditions, metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidExpression",
+			Message: err.Error(),
+		})
+		return opts, err
+	}
+	return opts, nil
+}
 
-		// Parse and Execute
-		t, err := template.New("path").Funcs(funcMap).Parse(pathTmpl)
-		if err == nil {
-			var buf bytes.Buffer
-			if err := t.Execute(&buf, data); err == nil {
-				if !strings.HasPrefix(buf.String(), "/") {
-					targetURL = baseURL + "/" + buf.String()
-				} else {
-					targetURL = baseURL + buf.String()
-				}
-			} else {
-				l.Error(err, "Template execute failed, falling back")
-				targetURL = baseURL
-			}
-		} else {
-			l.Error(err, "Template parse failed")
-			targetURL = baseURL
+// customFormats loads the CustomFormats of the namespace. Formats that do not
+// compile are skipped, so one bad format does not block every request.
+func (r *TorrentRequestReconciler) customFormats(ctx context.Context, namespace string) ([]*selection.Format, error) {
+	var list torrentsv1alpha1.CustomFormatList
+	if err := r.List(ctx, &list, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	formats := make([]*selection.Format, 0, len(list.Items))
+	for _, cf := range list.Items {
+		f, err := selection.NewFormat(cf.Name, cf.Spec)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Skipping invalid custom format", "name", cf.Name)
+			continue
 		}
+		formats = append(formats, f)
+	}
+	return formats, nil
+}
+
+// formatAnnotations records the custom formats the chosen release matched
+func formatAnnotations(formats []string, score int) map[string]string {
+	if len(formats) == 0 {
+		return nil
+	}
+	return map[string]string{
+		torrentsv1alpha1.CustomFormatsAnnotation: strings.Join(formats, ","),
+		torrentsv1alpha1.FormatScoreAnnotation:   strconv.Itoa(score),
+	}
+}
+
+// candidateStatuses reports the best ranked candidates. When choose is set
+// the first one is chosen unless it was rejected.
+func candidateStatuses(candidates []selection.Candidate, choose bool) []torrentsv1alpha1.CandidateStatus {
+	statuses := make([]torrentsv1alpha1.CandidateStatus, 0, min(len(candidates), maxCandidates))
+	for i := range candidates[:min(len(candidates), maxCandidates)] {
+		statuses = append(statuses, candidateStatus(&candidates[i], choose && i == 0))
 	}
-	fmt.Printf("DEBUG: Searching Indexer %s URL: %s\n", indexer.Name, targetURL)
+	return statuses
+}
+
+// candidateStatus describes a candidate, first tells whether it is the one
+// picked when accepted
+func candidateStatus(c *selection.Candidate, first bool) torrentsv1alpha1.CandidateStatus {
+	return torrentsv1alpha1.CandidateStatus{
+		Title:       c.Title,
+		Indexer:     c.Indexer,
+		Quality:     c.Quality,
+		Size:        c.Size,
+		Seeders:     c.Seeders,
+		InfoHash:    parser.InfoHash(c.Magnet),
+		Formats:     c.Formats,
+		FormatScore: c.FormatScore,
+		WordScore:   c.WordScore,
+		Chosen:      first && !c.Rejected(),
+		Rejected:    c.Rejected(),
+		Reason:      c.Reason(),
+	}
+}
 
-	var body []byte
-	var err error
+// syncSearchResults keeps a SearchResult for each of the best ranked
+// candidates of the request, and deletes those left from a previous search.
+// When choose is set the first one is chosen unless it was rejected. Failures
+// are logged and the request goes on, manual requests then only offer the
+// results that were saved.
+func (r *TorrentRequestReconciler) syncSearchResults(ctx context.Context, tr *torrentsv1alpha1.TorrentRequest, candidates []selection.Candidate, choose bool) {
+	l := log.FromContext(ctx)
+
+	n := defaultSearchResults
+	if tr.Spec.SearchResults != nil {
+		n = *tr.Spec.SearchResults
+	}
+	n = min(n, len(candidates))
 
-	useFlareSolverr := false
-	if r.FlareSolverrURL != "" {
-		for _, setting := range indexer.Spec.Settings {
-			if setting.Type == "info_flaresolverr" {
-				useFlareSolverr = true
-				break
+	for i := range candidates[:n] {
+		c := &candidates[i]
+		sr := &torrentsv1alpha1.SearchResult{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      searchResultName(tr.Name, i+1),
+				Namespace: tr.Namespace,
+			},
+		}
+		_, err := controllerutil.CreateOrUpdate(ctx, r.Client, sr, func() error {
+			sr.Labels = map[string]string{"created-by": tr.Name}
+			sr.Spec = torrentsv1alpha1.SearchResultSpec{
+				Request:         tr.Name,
+				Position:        i + 1,
+				CandidateStatus: candidateStatus(c, choose && i == 0),
+				Magnet:          c.Magnet,
+				Leechers:        c.Leechers,
+				Release:         releaseInfo(c.Release),
+			}
+			if c.PublishedAt != nil {
+				sr.Spec.PublishedAt = &metav1.Time{Time: *c.PublishedAt}
 			}
+			return ctrl.SetControllerReference(tr, sr, r.Scheme)
+		})
+		if err != nil {
+			l.Error(err, "Failed to save search result", "name", sr.Name)
 		}
 	}
 
-	if useFlareSolverr {
-		body, err = r.doRequestFS(ctx, targetURL)
-	} else {
-		req, _ := http.NewRequestWithContext(ctx, "GET", targetURL, nil)
-		req.Header.Set("User-Agent", "Prowlarr/1.0 (Text-Mode-Operator)")
-		resp, er := r.HTTPClient.Do(req)
-		if er != nil {
-			return nil, er
+	var stale torrentsv1alpha1.SearchResultList
+	if err := r.List(ctx, &stale, client.InNamespace(tr.Namespace), client.MatchingLabels{"created-by": tr.Name}); err != nil {
+		l.Error(err, "Failed to list search results")
+		return
+	}
+	for i := range stale.Items {
+		sr := &stale.Items[i]
+		if sr.Spec.Position <= n || !metav1.IsControlledBy(sr, tr) {
+			continue
+		}
+		if err := r.Delete(ctx, sr); client.IgnoreNotFound(err) != nil {
+			l.Error(err, "Failed to delete stale search result", "name", sr.Name)
 		}
-		defer resp.Body.Close()
-		body, err = io.ReadAll(resp.Body)
 	}
+}
+
+// searchResultName names the SearchResult at a position of a request
+func searchResultName(request string, position int) string {
+	suffix := fmt.Sprintf("-%d", position)
+	if len(request)+len(suffix) > validation.DNS1123SubdomainMaxLength {
+		request = strings.TrimRight(request[:validation.DNS1123SubdomainMaxLength-len(suffix)], "-.")
+	}
+	return request + suffix
+}
+
+// noAcceptableResultMessage explains a request whose results were all
+// rejected, naming the rule rejecting most of them
+func noAcceptableResultMessage(results int, eliminations []torrentsv1alpha1.Elimination) string {
+	msg := fmt.Sprintf("None of the %d results were acceptable", results)
+	if len(eliminations) > 0 {
+		msg += fmt.Sprintf(", %s rejected %d", eliminations[0].Rule, eliminations[0].Count)
+	}
+	return msg
+}
+
+// eliminations reports how many results each rule rejected
+func eliminations(candidates []selection.Candidate) []torrentsv1alpha1.Elimination {
+	var out []torrentsv1alpha1.Elimination
+	for _, e := range selection.Eliminations(candidates) {
+		out = append(out, torrentsv1alpha1.Elimination{Rule: e.Rule, Count: e.Count})
+	}
+	return out
+}
+
+// releaseInfo converts parsed release attributes to their API form
+func releaseInfo(r releaseparse.Release) *torrentsv1alpha1.ReleaseInfo {
+	return &torrentsv1alpha1.ReleaseInfo{
+		Title:      r.Title,
+		Year:       r.Year,
+		Seasons:    r.Seasons,
+		Episodes:   r.Episodes,
+		FullSeason: r.FullSeason,
+		Resolution: r.Resolution,
+		Source:     r.Source,
+		Codec:      r.Codec,
+		Audio:      r.Audio,
+		Channels:   r.Channels,
+		HDR:        r.HDR,
+		Group:      r.Group,
+		Proper:     r.Proper,
+		Repack:     r.Repack,
+		Languages:  r.Languages,
+		Edition:    r.Edition,
+	}
+}
 
+// Search runs the query against a single indexer and resolves download links
+// that point at details pages to magnets. The query is adapted to the modes
+// the indexer supports, and results not matching its structured parameters
+// are dropped. It backs both TorrentRequests and the Torznab API.
+func (r *TorrentRequestReconciler) Search(ctx context.Context, indexer *torrentsv1alpha1.Indexer, q search.Query) ([]parser.ParseResult, error) {
+	l := log.FromContext(ctx)
+
+	found, err := r.searchIndexer(ctx, indexer, q.ForIndexer(indexer))
 	if err != nil {
 		return nil, err
 	}
 
-	return parser.ParseHTML(string(body), indexer)
+	var results []parser.ParseResult
+	for _, res := range found {
+		if !q.Matches(res.Title) {
+			l.V(1).Info("Dropping result not matching the query", "title", res.Title)
+			continue
+		}
+		results = append(results, res)
+	}
+	return r.ResolveMagnets(ctx, indexer, results), nil
 }
 
-func (r *TorrentRequestReconciler) doRequestFS(ctx context.Context, targetURL string) ([]byte, error) {
-	reqBody := map[string]interface{}{
-		"cmd":        "request.get",
-		"url":        targetURL,
-		"maxTimeout": 60000,
+// LatestReleases fetches the latest releases of the indexer for the RSS
+// sync, from its rss.path or by searching without keywords. Details pages
+// are left unresolved, ResolveMagnets resolves the releases a request wants.
+func (r *TorrentRequestReconciler) LatestReleases(ctx context.Context, indexer *torrentsv1alpha1.Indexer) ([]parser.ParseResult, error) {
+	if indexer.Spec.RSS == nil || indexer.Spec.RSS.Path == "" {
+		return r.searchIndexer(ctx, indexer, search.Query{Mode: search.ModeSearch})
 	}
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal FS request: %v", err)
+	return r.fetchMirrors(ctx, indexer, func(baseURL string) ([]byte, error) {
+		return r.clients.fetch(ctx, indexer, pageRequest{URL: joinURL(baseURL, indexer.Spec.RSS.Path)})
+	})
+}
+
+// ResolveMagnets fills in the indexer of the results and resolves download
+// links that point at details pages to magnets
+func (r *TorrentRequestReconciler) ResolveMagnets(ctx context.Context, indexer *torrentsv1alpha1.Indexer, results []parser.ParseResult) []parser.ParseResult {
+	l := log.FromContext(ctx)
+	for i := range results {
+		// Quick fix to ensure indexer name is populated if parser didn't do it
+		if results[i].Indexer == "" {
+			results[i].Indexer = indexer.Name
+		}
+
+		// If Magnet is a URL (not magnet:), and we have a Download block, attempt to fetch details
+		if strings.HasPrefix(results[i].Magnet, "http") && !strings.HasPrefix(results[i].Magnet, "magnet:") && indexer.Spec.Download != nil {
+			l.Info("Fetching details page for magnet", "url", results[i].Magnet)
+
+			detBody, detErr := r.clients.fetch(ctx, indexer, pageRequest{URL: results[i].Magnet})
+			if detErr != nil {
+				l.Error(detErr, "Failed to fetch details page", "url", results[i].Magnet)
+				continue
+			}
+
+			// Parse details
+			magnetLink, parseErr := parser.ParseDetailsPage(string(detBody), indexer.Spec.Download)
+			if parseErr == nil && magnetLink != "" {
+				l.Info("Successfully resolved magnet from details", "magnet", magnetLink)
+				results[i].Magnet = magnetLink
+			} else {
+				l.Error(parseErr, "Failed to parse magnet from details page")
+			}
+		}
 	}
+	return results
+}
 
-	fsURL := fmt.Sprintf("%s/v1", strings.TrimRight(r.FlareSolverrURL, "/"))
-	req, err := http.NewRequestWithContext(ctx, "POST", fsURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create FS request: %v", err)
+// searchIndexer runs the search against the indexer mirrors in turn, failing
+// over to the next mirror when one cannot be fetched.
+func (r *TorrentRequestReconciler) searchIndexer(ctx context.Context, indexer *torrentsv1alpha1.Indexer, q search.Query) ([]parser.ParseResult, error) {
+	return r.fetchMirrors(ctx, indexer, func(baseURL string) ([]byte, error) {
+		return r.fetchSearchPage(ctx, indexer, baseURL, q)
+	})
+}
+
+// fetchMirrors fetches a page from the indexer mirrors in turn and parses
+// its results, failing over to the next mirror when one cannot be fetched
+func (r *TorrentRequestReconciler) fetchMirrors(ctx context.Context, indexer *torrentsv1alpha1.Indexer, fetch func(baseURL string) ([]byte, error)) ([]parser.ParseResult, error) {
+	l := log.FromContext(ctx)
+	mirrors := mirrorOrder(indexer)
+	if len(mirrors) == 0 {
+		return nil, fmt.Errorf("no links")
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := r.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("FlareSolverr connection failed: %v", err)
+	var lastErr error
+	for _, baseURL := range mirrors {
+		body, err := fetch(baseURL)
+		if err != nil {
+			l.Error(err, "Search failed on mirror, trying next", "indexer", indexer.Name, "mirror", baseURL)
+			lastErr = err
+			continue
+		}
+		return parser.ParseHTML(string(body), baseURL, indexer)
 	}
-	defer resp.Body.Close()
 
-	var fsResp struct {
-		Status   string `json:"status"`
-		Solution struct {
-			Response string `json:"response"` // HTML content
-		} `json:"solution"`
+	return nil, fmt.Errorf("all %d mirrors failed, last error: %w", len(mirrors), lastErr)
+}
+
+func (r *TorrentRequestReconciler) fetchSearchPage(ctx context.Context, indexer *torrentsv1alpha1.Indexer, baseURL string, q search.Query) ([]byte, error) {
+	l := log.FromContext(ctx)
+	// Construct URL (Reuse logic from checkHealth ideally, but we need parameter injection)
+	baseURL = strings.TrimRight(baseURL, "/")
+	page := pageRequest{URL: baseURL}
+	if indexer.Spec.Search != nil && len(indexer.Spec.Search.Paths) > 0 {
+		categories := search.TrackerCategories(indexer, q.Categories)
+		path := searchPath(indexer.Spec.Search.Paths, categories)
+
+		// Prepare template data
+		data := struct {
+			Keywords   string
+			Query      map[string]string
+			Config     map[string]string
+			Categories []string
+		}{
+			Keywords: url.QueryEscape(q.TemplateKeywords()),
+			Query:    q.EscapedTemplateValues(),
+			Config: map[string]string{
+				"username": "guest",
+				"password": "guest",
+			},
+			Categories: categories,
+		}
+
+		if rendered, err := renderTemplate(path.Path, data); err == nil {
+			page.URL = joinURL(baseURL, rendered)
+		} else {
+			l.Error(err, "Template failed, falling back")
+		}
+
+		// POST searches send their inputs as a form, which also makes
+		// FlareSolverr use request.post
+		if strings.EqualFold(path.Method, http.MethodPost) {
+			page.Method = http.MethodPost
+			page.Form = url.Values{}
+			data.Keywords = q.TemplateKeywords()
+			data.Query = q.TemplateValues()
+			inputs := map[string]string{}
+			if len(path.Inputs) == 0 || path.InheritInputs {
+				for k, v := range indexer.Spec.Search.Inputs {
+					inputs[k] = v
+				}
+			}
+			for k, v := range path.Inputs {
+				inputs[k] = v
+			}
+			for k, v := range inputs {
+				rendered, err := renderTemplate(v, data)
+				if err != nil {
+					l.Error(err, "Input template failed, skipping", "input", k)
+					continue
+				}
+				page.Form.Set(k, rendered)
+			}
+		}
+	}
+	// The URL is not logged, search paths may carry credentials
+	l.V(1).Info("Searching indexer", "indexer", indexer.Name)
+
+	return r.clients.fetch(ctx, indexer, page)
+}
+
+// searchPath picks the first path serving one of the tracker categories
+// searched, paths without categories serving them all
+func searchPath(paths []torrentsv1alpha1.SearchPathBlock, categories []string) torrentsv1alpha1.SearchPathBlock {
+	if len(categories) == 0 {
+		return paths[0]
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&fsResp); err != nil {
-		return nil, fmt.Errorf("failed to decode FS response: %v", err)
+	for _, path := range paths {
+		if len(path.Categories) == 0 {
+			return path
+		}
+		for _, c := range path.Categories {
+			if slices.Contains(categories, c) {
+				return path
+			}
+		}
 	}
+	return paths[0]
+}
 
-	if fsResp.Status == "ok" {
-		return []byte(fsResp.Solution.Response), nil
+// renderTemplate executes an indexer definition template against data
+func renderTemplate(tmpl string, data any) (string, error) {
+	funcMap := template.FuncMap{
+		"re_replace": func(input, pattern, replacement string) string {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return input
+			}
+			return re.ReplaceAllString(input, replacement)
+		},
+		"replace": func(input, from, to string) string {
+			return strings.ReplaceAll(input, from, to)
+		},
 	}
 
-	return nil, fmt.Errorf("FlareSolverr failed: %s", fsResp.Status)
+	// Parse and Execute
+	t, err := template.New("path").Funcs(funcMap).Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
 }
 
 func (r *TorrentRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.HTTPClient == nil {
+		r.HTTPClient = &http.Client{Timeout: 60 * time.Second}
+	}
+	r.clients = newIndexerClients(r.Client, r.HTTPClient, r.ProxyURL, r.Solvers)
+	r.rssEvents = make(chan event.GenericEvent, 100)
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&torrentsv1alpha1.TorrentRequest{}).
+		Owns(&torrentsv1alpha1.Torrent{}, builder.WithPredicates(torrentStalled)).
+		WatchesRawSource(source.Channel(r.rssEvents, &handler.EnqueueRequestForObject{})).
 		Complete(r)
 }
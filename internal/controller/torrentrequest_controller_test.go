@@ -8,10 +8,17 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/ghttp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/parser"
+	"vitoru.fun/torrents/internal/releaseparse"
+	"vitoru.fun/torrents/internal/selection"
 )
 
 var _ = Describe("TorrentRequest Controller", func() {
@@ -153,4 +160,1081 @@ var _ = Describe("TorrentRequest Controller", func() {
 			Expect(createdTorrent.Spec.Magnet).To(Equal("magnet:?xt=urn:btih:validmagnetlink"))
 		})
 	})
+	Context("When the first indexer mirror is down", func() {
+		It("Should fail over to the next mirror", func() {
+			ctx := context.Background()
+
+			handler := func(w http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+				Expect(req.URL.Path).To(Equal("/search"))
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`
+						<html>
+							<table>
+								<tr class="result">
+									<td class="title">Debian 12 ISO</td>
+									<td><a class="dl" href="/download/debian">Download</a></td>
+									<td>600 MB</td>
+									<td>50</td>
+								</tr>
+							</table>
+						</html>
+					`))
+				Expect(err).To(Succeed())
+			}
+			for i := 0; i < 5; i++ {
+				server.AppendHandlers(handler)
+			}
+
+			indexer := &torrentsv1alpha1.Indexer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-mirror-indexer",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.IndexerSpec{
+					// Nothing listens on port 1, so the first mirror always fails
+					Links: []string{"http://127.0.0.1:1", server.URL()},
+					Caps: torrentsv1alpha1.Caps{
+						Modes: torrentsv1alpha1.Modes{
+							Search: []string{"q"},
+						},
+					},
+					Search: &torrentsv1alpha1.Search{
+						Rows: torrentsv1alpha1.RowsBlock{
+							Selector: "tr.result",
+						},
+						Fields: torrentsv1alpha1.FieldsBlock{
+							"title":    torrentsv1alpha1.SelectorBlock{Selector: ".title"},
+							"download": torrentsv1alpha1.SelectorBlock{Selector: ".dl", Attribute: "href"},
+							"seeders":  torrentsv1alpha1.SelectorBlock{Selector: "td:nth-child(4)"},
+						},
+						Paths: []torrentsv1alpha1.SearchPathBlock{
+							{Path: "/search?q={{ .Keywords }}"},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, indexer)).To(Succeed())
+
+			indexer.Status.Conditions = []metav1.Condition{
+				{
+					Type:               "Ready",
+					Status:             metav1.ConditionTrue,
+					Reason:             "HealthCheckSucceeded",
+					Message:            "Indexer is healthy",
+					LastTransitionTime: metav1.Now(),
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, indexer)).To(Succeed())
+
+			tr := &torrentsv1alpha1.TorrentRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-debian-req",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.TorrentRequestSpec{
+					Keywords: "debian",
+					Indexers: []string{"test-mirror-indexer"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tr)).To(Succeed())
+
+			trLookupKey := types.NamespacedName{Name: "test-debian-req", Namespace: "default"}
+			createdTR := &torrentsv1alpha1.TorrentRequest{}
+			Eventually(func() string {
+				err := k8sClient.Get(ctx, trLookupKey, createdTR)
+				if err != nil {
+					return ""
+				}
+				return createdTR.Status.State
+			}, timeout, interval).Should(Equal("Completed"))
+
+			createdTorrent := &torrentsv1alpha1.Torrent{}
+			torrentLookupKey := types.NamespacedName{Name: createdTR.Status.FoundTorrent, Namespace: "default"}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, torrentLookupKey, createdTorrent)
+			}, timeout, interval).Should(Succeed())
+
+			// Relative links resolve against the mirror that served the page
+			Expect(createdTorrent.Spec.Magnet).To(Equal(server.URL() + "/download/debian"))
+		})
+	})
+	Context("When the indexer searches with POST", func() {
+		It("Should submit the search inputs as a form", func() {
+			ctx := context.Background()
+
+			handler := func(w http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+				Expect(req.Method).To(Equal("POST"))
+				Expect(req.URL.Path).To(Equal("/search"))
+				Expect(req.ParseForm()).To(Succeed())
+				Expect(req.PostForm.Get("query")).To(Equal("arch linux"))
+				Expect(req.PostForm.Get("cat")).To(Equal("0"))
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`
+						<html>
+							<table>
+								<tr class="result">
+									<td class="title">Arch Linux 2025.01.01</td>
+									<td><a class="dl" href="magnet:?xt=urn:btih:archmagnet">Download</a></td>
+									<td>1 GB</td>
+									<td>30</td>
+								</tr>
+							</table>
+						</html>
+					`))
+				Expect(err).To(Succeed())
+			}
+			for i := 0; i < 5; i++ {
+				server.AppendHandlers(handler)
+			}
+
+			indexer := &torrentsv1alpha1.Indexer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-post-indexer",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.IndexerSpec{
+					Links: []string{server.URL()},
+					Caps: torrentsv1alpha1.Caps{
+						Modes: torrentsv1alpha1.Modes{
+							Search: []string{"q"},
+						},
+					},
+					Search: &torrentsv1alpha1.Search{
+						Inputs: map[string]string{"cat": "0"},
+						Rows: torrentsv1alpha1.RowsBlock{
+							Selector: "tr.result",
+						},
+						Fields: torrentsv1alpha1.FieldsBlock{
+							"title":    torrentsv1alpha1.SelectorBlock{Selector: ".title"},
+							"download": torrentsv1alpha1.SelectorBlock{Selector: ".dl", Attribute: "href"},
+							"seeders":  torrentsv1alpha1.SelectorBlock{Selector: "td:nth-child(4)"},
+						},
+						Paths: []torrentsv1alpha1.SearchPathBlock{
+							{
+								Path:          "/search",
+								Method:        "post",
+								Inputs:        map[string]string{"query": "{{ .Keywords }}"},
+								InheritInputs: true,
+							},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, indexer)).To(Succeed())
+
+			indexer.Status.Conditions = []metav1.Condition{
+				{
+					Type:               "Ready",
+					Status:             metav1.ConditionTrue,
+					Reason:             "HealthCheckSucceeded",
+					Message:            "Indexer is healthy",
+					LastTransitionTime: metav1.Now(),
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, indexer)).To(Succeed())
+
+			tr := &torrentsv1alpha1.TorrentRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-arch-req",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.TorrentRequestSpec{
+					Keywords: "arch linux",
+					Indexers: []string{"test-post-indexer"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tr)).To(Succeed())
+
+			trLookupKey := types.NamespacedName{Name: "test-arch-req", Namespace: "default"}
+			createdTR := &torrentsv1alpha1.TorrentRequest{}
+			Eventually(func() string {
+				err := k8sClient.Get(ctx, trLookupKey, createdTR)
+				if err != nil {
+					return ""
+				}
+				return createdTR.Status.State
+			}, timeout, interval).Should(Equal("Completed"))
+		})
+	})
+
+	Context("When requesting a TV episode from an indexer without tv-search", func() {
+		It("Should search by synthesized keywords and pick the matching episode", func() {
+			ctx := context.Background()
+
+			handler := func(w http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+				Expect(req.URL.Query().Get("q")).To(Equal("The Show S01E02"))
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`
+						<html>
+							<table>
+								<tr class="result">
+									<td class="title">The.Show.S01E03.1080p.WEB</td>
+									<td><a class="dl" href="magnet:?xt=urn:btih:wrongepisode">Download</a></td>
+									<td>1 GB</td>
+									<td>500</td>
+								</tr>
+								<tr class="result">
+									<td class="title">The.Show.S01E02.1080p.WEB</td>
+									<td><a class="dl" href="magnet:?xt=urn:btih:rightepisode">Download</a></td>
+									<td>1 GB</td>
+									<td>20</td>
+								</tr>
+							</table>
+						</html>
+					`))
+				Expect(err).To(Succeed())
+			}
+			for i := 0; i < 5; i++ {
+				server.AppendHandlers(handler)
+			}
+
+			indexer := &torrentsv1alpha1.Indexer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-tv-indexer",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.IndexerSpec{
+					Links: []string{server.URL()},
+					Caps: torrentsv1alpha1.Caps{
+						Modes: torrentsv1alpha1.Modes{
+							Search: []string{"q"},
+						},
+					},
+					Search: &torrentsv1alpha1.Search{
+						Rows: torrentsv1alpha1.RowsBlock{
+							Selector: "tr.result",
+						},
+						Fields: torrentsv1alpha1.FieldsBlock{
+							"title":    torrentsv1alpha1.SelectorBlock{Selector: ".title"},
+							"download": torrentsv1alpha1.SelectorBlock{Selector: ".dl", Attribute: "href"},
+							"seeders":  torrentsv1alpha1.SelectorBlock{Selector: "td:nth-child(4)"},
+						},
+						Paths: []torrentsv1alpha1.SearchPathBlock{
+							{Path: "/search?q={{ .Keywords }}"},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, indexer)).To(Succeed())
+
+			indexer.Status.Conditions = []metav1.Condition{
+				{
+					Type:               "Ready",
+					Status:             metav1.ConditionTrue,
+					Reason:             "HealthCheckSucceeded",
+					Message:            "Indexer is healthy",
+					LastTransitionTime: metav1.Now(),
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, indexer)).To(Succeed())
+
+			tr := &torrentsv1alpha1.TorrentRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-tv-req",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.TorrentRequestSpec{
+					TV:       &torrentsv1alpha1.TVQuery{Series: "The Show", Season: 1, Episode: 2},
+					Indexers: []string{"test-tv-indexer"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tr)).To(Succeed())
+
+			trLookupKey := types.NamespacedName{Name: "test-tv-req", Namespace: "default"}
+			createdTR := &torrentsv1alpha1.TorrentRequest{}
+			Eventually(func() string {
+				err := k8sClient.Get(ctx, trLookupKey, createdTR)
+				if err != nil {
+					return ""
+				}
+				return createdTR.Status.State
+			}, timeout, interval).Should(Equal("Completed"))
+
+			torrent := &torrentsv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: createdTR.Status.FoundTorrent, Namespace: "default"}, torrent)).To(Succeed())
+			Expect(torrent.Spec.Magnet).To(Equal("magnet:?xt=urn:btih:rightepisode"))
+			Expect(torrent.Spec.Release).NotTo(BeNil())
+			Expect(torrent.Spec.Release.Title).To(Equal("The Show"))
+			Expect(torrent.Spec.Release.Episodes).To(Equal([]int{2}))
+			Expect(torrent.Spec.Release.Resolution).To(Equal("1080p"))
+		})
+	})
+	Context("When requesting with a QualityProfile", func() {
+		It("Should pick the best ranked quality and explain the candidates", func() {
+			ctx := context.Background()
+
+			handler := func(w http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`
+						<html>
+							<table>
+								<tr class="result">
+									<td class="title">Movie.2020.720p.HDTV.x264-LOL</td>
+									<td><a class="dl" href="magnet:?xt=urn:btih:hdtv">Download</a></td>
+									<td>1 GB</td>
+									<td>900</td>
+								</tr>
+								<tr class="result">
+									<td class="title">Movie.2020.1080p.WEB-DL.H.264-NTb</td>
+									<td><a class="dl" href="magnet:?xt=urn:btih:webdl">Download</a></td>
+									<td>4 GB</td>
+									<td>30</td>
+								</tr>
+								<tr class="result">
+									<td class="title">Movie.2020.CAM.x264-BAD</td>
+									<td><a class="dl" href="magnet:?xt=urn:btih:cam">Download</a></td>
+									<td>1 GB</td>
+									<td>2000</td>
+								</tr>
+							</table>
+						</html>
+					`))
+				Expect(err).To(Succeed())
+			}
+			for i := 0; i < 5; i++ {
+				server.AppendHandlers(handler)
+			}
+
+			indexer := &torrentsv1alpha1.Indexer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-quality-indexer",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.IndexerSpec{
+					Links: []string{server.URL()},
+					Caps: torrentsv1alpha1.Caps{
+						Modes: torrentsv1alpha1.Modes{
+							Search: []string{"q"},
+						},
+					},
+					Search: &torrentsv1alpha1.Search{
+						Rows: torrentsv1alpha1.RowsBlock{
+							Selector: "tr.result",
+						},
+						Fields: torrentsv1alpha1.FieldsBlock{
+							"title":    torrentsv1alpha1.SelectorBlock{Selector: ".title"},
+							"download": torrentsv1alpha1.SelectorBlock{Selector: ".dl", Attribute: "href"},
+							"size":     torrentsv1alpha1.SelectorBlock{Selector: "td:nth-child(3)"},
+							"seeders":  torrentsv1alpha1.SelectorBlock{Selector: "td:nth-child(4)"},
+						},
+						Paths: []torrentsv1alpha1.SearchPathBlock{
+							{Path: "/search?q={{ .Keywords }}"},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, indexer)).To(Succeed())
+
+			indexer.Status.Conditions = []metav1.Condition{
+				{
+					Type:               "Ready",
+					Status:             metav1.ConditionTrue,
+					Reason:             "HealthCheckSucceeded",
+					Message:            "Indexer is healthy",
+					LastTransitionTime: metav1.Now(),
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, indexer)).To(Succeed())
+
+			profile := &torrentsv1alpha1.QualityProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-hd",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.QualityProfileSpec{
+					Qualities: []torrentsv1alpha1.Quality{
+						{Resolution: "1080p", Source: "WEB-DL"},
+						{Resolution: "720p"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, profile)).To(Succeed())
+
+			format := &torrentsv1alpha1.CustomFormat{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-ntb",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.CustomFormatSpec{
+					Conditions: []torrentsv1alpha1.FormatCondition{{ReleaseGroup: "^NTb$"}},
+					Score:      10,
+				},
+			}
+			Expect(k8sClient.Create(ctx, format)).To(Succeed())
+
+			tr := &torrentsv1alpha1.TorrentRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-quality-req",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.TorrentRequestSpec{
+					Keywords:          "movie",
+					Indexers:          []string{"test-quality-indexer"},
+					QualityProfileRef: &corev1.LocalObjectReference{Name: "test-hd"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tr)).To(Succeed())
+
+			trLookupKey := types.NamespacedName{Name: "test-quality-req", Namespace: "default"}
+			createdTR := &torrentsv1alpha1.TorrentRequest{}
+			Eventually(func() string {
+				err := k8sClient.Get(ctx, trLookupKey, createdTR)
+				if err != nil {
+					return ""
+				}
+				return createdTR.Status.State
+			}, timeout, interval).Should(Equal("Completed"))
+
+			torrent := &torrentsv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: createdTR.Status.FoundTorrent, Namespace: "default"}, torrent)).To(Succeed())
+			Expect(torrent.Spec.Magnet).To(Equal("magnet:?xt=urn:btih:webdl"))
+			Expect(torrent.Annotations).To(HaveKeyWithValue(torrentsv1alpha1.CustomFormatsAnnotation, "test-ntb"))
+			Expect(torrent.Annotations).To(HaveKeyWithValue(torrentsv1alpha1.FormatScoreAnnotation, "10"))
+
+			Expect(createdTR.Status.Candidates).To(HaveLen(3))
+			Expect(createdTR.Status.Candidates[0].Chosen).To(BeTrue())
+			Expect(createdTR.Status.Candidates[0].Quality).To(Equal("WEB-DL 1080p"))
+			Expect(createdTR.Status.Candidates[0].Formats).To(Equal([]string{"test-ntb"}))
+			Expect(createdTR.Status.Candidates[2].Rejected).To(BeTrue())
+			Expect(createdTR.Status.Candidates[2].Reason).To(Equal("quality CAM is not allowed"))
+			Expect(createdTR.Status.Eliminations).To(Equal([]torrentsv1alpha1.Elimination{{Rule: "quality", Count: 1}}))
+
+			var results torrentsv1alpha1.SearchResultList
+			Expect(k8sClient.List(ctx, &results, client.InNamespace("default"),
+				client.MatchingLabels{"created-by": "test-quality-req"})).To(Succeed())
+			Expect(results.Items).To(HaveLen(3))
+
+			best := &torrentsv1alpha1.SearchResult{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "test-quality-req-1", Namespace: "default"}, best)).To(Succeed())
+			Expect(best.Spec.Request).To(Equal("test-quality-req"))
+			Expect(best.Spec.Chosen).To(BeTrue())
+			Expect(best.Spec.Magnet).To(Equal("magnet:?xt=urn:btih:webdl"))
+			Expect(best.Spec.Release.Group).To(Equal("NTb"))
+			Expect(best.OwnerReferences).To(HaveLen(1))
+			Expect(best.OwnerReferences[0].Name).To(Equal("test-quality-req"))
+		})
+	})
+
+	Context("When the filter expression is invalid", func() {
+		It("Should report it without searching", func() {
+			ctx := context.Background()
+
+			tr := &torrentsv1alpha1.TorrentRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-invalid-filter",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.TorrentRequestSpec{
+					Keywords: "movie",
+					Filter:   "seeders",
+				},
+			}
+			Expect(k8sClient.Create(ctx, tr)).To(Succeed())
+
+			trLookupKey := types.NamespacedName{Name: "test-invalid-filter", Namespace: "default"}
+			createdTR := &torrentsv1alpha1.TorrentRequest{}
+			Eventually(func() string {
+				err := k8sClient.Get(ctx, trLookupKey, createdTR)
+				if err != nil {
+					return ""
+				}
+				cond := meta.FindStatusCondition(createdTR.Status.Conditions, "Ready")
+				if cond == nil {
+					return ""
+				}
+				return cond.Reason
+			}, timeout, interval).Should(Equal("InvalidExpression"))
+			Expect(createdTR.Status.FoundTorrent).To(BeEmpty())
+		})
+	})
+
+	Context("When requesting with manual selection", func() {
+		manualIndexer := func(ctx context.Context, name string) {
+			handler := func(w http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`
+						<html>
+							<table>
+								<tr class="result">
+									<td class="title">Movie.2020.1080p.WEB-DL.H.264-NTb</td>
+									<td><a class="dl" href="magnet:?xt=urn:btih:1111111111111111111111111111111111111111">Download</a></td>
+									<td>500</td>
+								</tr>
+								<tr class="result">
+									<td class="title">Movie.2020.720p.HDTV.x264-LOL</td>
+									<td><a class="dl" href="magnet:?xt=urn:btih:2222222222222222222222222222222222222222">Download</a></td>
+									<td>50</td>
+								</tr>
+							</table>
+						</html>
+					`))
+				Expect(err).To(Succeed())
+			}
+			for i := 0; i < 5; i++ {
+				server.AppendHandlers(handler)
+			}
+
+			indexer := &torrentsv1alpha1.Indexer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.IndexerSpec{
+					Links: []string{server.URL()},
+					Caps: torrentsv1alpha1.Caps{
+						Modes: torrentsv1alpha1.Modes{
+							Search: []string{"q"},
+						},
+					},
+					Search: &torrentsv1alpha1.Search{
+						Rows: torrentsv1alpha1.RowsBlock{
+							Selector: "tr.result",
+						},
+						Fields: torrentsv1alpha1.FieldsBlock{
+							"title":    torrentsv1alpha1.SelectorBlock{Selector: ".title"},
+							"download": torrentsv1alpha1.SelectorBlock{Selector: ".dl", Attribute: "href"},
+							"seeders":  torrentsv1alpha1.SelectorBlock{Selector: "td:nth-child(3)"},
+						},
+						Paths: []torrentsv1alpha1.SearchPathBlock{
+							{Path: "/search?q={{ .Keywords }}"},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, indexer)).To(Succeed())
+
+			indexer.Status.Conditions = []metav1.Condition{
+				{
+					Type:               "Ready",
+					Status:             metav1.ConditionTrue,
+					Reason:             "HealthCheckSucceeded",
+					Message:            "Indexer is healthy",
+					LastTransitionTime: metav1.Now(),
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, indexer)).To(Succeed())
+		}
+
+		It("Should wait for a user to pick a candidate", func() {
+			ctx := context.Background()
+			manualIndexer(ctx, "test-manual-indexer")
+
+			tr := &torrentsv1alpha1.TorrentRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-manual-req",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.TorrentRequestSpec{
+					Keywords:  "movie",
+					Indexers:  []string{"test-manual-indexer"},
+					Selection: torrentsv1alpha1.SelectionManual,
+				},
+			}
+			Expect(k8sClient.Create(ctx, tr)).To(Succeed())
+
+			trLookupKey := types.NamespacedName{Name: "test-manual-req", Namespace: "default"}
+			createdTR := &torrentsv1alpha1.TorrentRequest{}
+			Eventually(func() string {
+				err := k8sClient.Get(ctx, trLookupKey, createdTR)
+				if err != nil {
+					return ""
+				}
+				return createdTR.Status.State
+			}, timeout, interval).Should(Equal("AwaitingSelection"))
+			Expect(createdTR.Status.FoundTorrent).To(BeEmpty())
+			Expect(createdTR.Status.Candidates).To(HaveLen(2))
+			Expect(createdTR.Status.Candidates[0].Chosen).To(BeFalse())
+
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, trLookupKey, createdTR); err != nil {
+					return err
+				}
+				createdTR.Spec.SelectedCandidate = "2222222222222222222222222222222222222222"
+				return k8sClient.Update(ctx, createdTR)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() string {
+				err := k8sClient.Get(ctx, trLookupKey, createdTR)
+				if err != nil {
+					return ""
+				}
+				return createdTR.Status.State
+			}, timeout, interval).Should(Equal("Completed"))
+
+			torrent := &torrentsv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: createdTR.Status.FoundTorrent, Namespace: "default"}, torrent)).To(Succeed())
+			Expect(torrent.Spec.Title).To(Equal("Movie.2020.720p.HDTV.x264-LOL"))
+			Expect(torrent.Spec.InfoHash).To(Equal("2222222222222222222222222222222222222222"))
+			Expect(createdTR.Status.Candidates[1].Chosen).To(BeTrue())
+
+			chosen := &torrentsv1alpha1.SearchResult{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "test-manual-req-2", Namespace: "default"}, chosen)).To(Succeed())
+			Expect(chosen.Spec.Chosen).To(BeTrue())
+		})
+
+		It("Should pick the best candidate once the approval timeout passes", func() {
+			ctx := context.Background()
+			manualIndexer(ctx, "test-timeout-indexer")
+
+			tr := &torrentsv1alpha1.TorrentRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-timeout-req",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.TorrentRequestSpec{
+					Keywords:        "movie",
+					Indexers:        []string{"test-timeout-indexer"},
+					Selection:       torrentsv1alpha1.SelectionManual,
+					ApprovalTimeout: &metav1.Duration{Duration: 2 * time.Second},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tr)).To(Succeed())
+
+			trLookupKey := types.NamespacedName{Name: "test-timeout-req", Namespace: "default"}
+			createdTR := &torrentsv1alpha1.TorrentRequest{}
+			Eventually(func() string {
+				err := k8sClient.Get(ctx, trLookupKey, createdTR)
+				if err != nil {
+					return ""
+				}
+				return createdTR.Status.State
+			}, timeout, interval).Should(Equal("Completed"))
+
+			torrent := &torrentsv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: createdTR.Status.FoundTorrent, Namespace: "default"}, torrent)).To(Succeed())
+			Expect(torrent.Spec.Title).To(Equal("Movie.2020.1080p.WEB-DL.H.264-NTb"))
+		})
+	})
+
+	Context("When a search finds nothing", func() {
+		emptyIndexer := func(ctx context.Context, name string, handler http.HandlerFunc) {
+			for i := 0; i < 10; i++ {
+				server.AppendHandlers(handler)
+			}
+
+			indexer := &torrentsv1alpha1.Indexer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.IndexerSpec{
+					Links: []string{server.URL()},
+					Caps: torrentsv1alpha1.Caps{
+						Modes: torrentsv1alpha1.Modes{
+							Search: []string{"q"},
+						},
+					},
+					Search: &torrentsv1alpha1.Search{
+						Rows: torrentsv1alpha1.RowsBlock{
+							Selector: "tr.result",
+						},
+						Fields: torrentsv1alpha1.FieldsBlock{
+							"title":    torrentsv1alpha1.SelectorBlock{Selector: ".title"},
+							"download": torrentsv1alpha1.SelectorBlock{Selector: ".dl", Attribute: "href"},
+						},
+						Paths: []torrentsv1alpha1.SearchPathBlock{
+							{Path: "/search?q={{ .Keywords }}"},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, indexer)).To(Succeed())
+
+			indexer.Status.Conditions = []metav1.Condition{
+				{
+					Type:               "Ready",
+					Status:             metav1.ConditionTrue,
+					Reason:             "HealthCheckSucceeded",
+					Message:            "Indexer is healthy",
+					LastTransitionTime: metav1.Now(),
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, indexer)).To(Succeed())
+		}
+
+		It("Should retry until the policy is exhausted", func() {
+			ctx := context.Background()
+			emptyIndexer(ctx, "test-retry-indexer", ghttp.RespondWith(http.StatusOK, "<html><table></table></html>"))
+
+			tr := &torrentsv1alpha1.TorrentRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-retry-req",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.TorrentRequestSpec{
+					Keywords: "unreleased",
+					Indexers: []string{"test-retry-indexer"},
+					Retry: &torrentsv1alpha1.RetryPolicy{
+						MaxAttempts: 2,
+						Backoff:     &metav1.Duration{Duration: time.Second},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tr)).To(Succeed())
+
+			trLookupKey := types.NamespacedName{Name: "test-retry-req", Namespace: "default"}
+			createdTR := &torrentsv1alpha1.TorrentRequest{}
+			Eventually(func() string {
+				err := k8sClient.Get(ctx, trLookupKey, createdTR)
+				if err != nil {
+					return ""
+				}
+				return createdTR.Status.State
+			}, timeout, interval).Should(Equal("Retrying"))
+			Expect(createdTR.Status.Attempts).To(Equal(1))
+			Expect(createdTR.Status.NextSearchTime).NotTo(BeNil())
+
+			Eventually(func() string {
+				err := k8sClient.Get(ctx, trLookupKey, createdTR)
+				if err != nil {
+					return ""
+				}
+				return createdTR.Status.State
+			}, timeout, interval).Should(Equal("Failed"))
+			Expect(createdTR.Status.Attempts).To(Equal(2))
+			Expect(createdTR.Status.NextSearchTime).To(BeNil())
+			Expect(createdTR.Status.LastSearchTime).NotTo(BeNil())
+			cond := meta.FindStatusCondition(createdTR.Status.Conditions, "Ready")
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal("NoResults"))
+		})
+
+		It("Should grab a release offered by the RSS sync while monitoring", func() {
+			ctx := context.Background()
+			emptyIndexer(ctx, "test-rss-indexer", ghttp.RespondWith(http.StatusOK, "<html><table></table></html>"))
+
+			tr := &torrentsv1alpha1.TorrentRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-rss-req",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.TorrentRequestSpec{
+					Keywords: "upcoming",
+					Indexers: []string{"test-rss-indexer"},
+					Mode:     torrentsv1alpha1.ModeMonitor,
+				},
+			}
+			Expect(k8sClient.Create(ctx, tr)).To(Succeed())
+
+			trLookupKey := types.NamespacedName{Name: "test-rss-req", Namespace: "default"}
+			createdTR := &torrentsv1alpha1.TorrentRequest{}
+			Eventually(func() string {
+				err := k8sClient.Get(ctx, trLookupKey, createdTR)
+				if err != nil {
+					return ""
+				}
+				return createdTR.Status.State
+			}, timeout, interval).Should(Equal("Monitoring"))
+			Expect(createdTR.Status.NextSearchTime).NotTo(BeNil())
+
+			torrentRequestReconciler.Offer(ctx, trLookupKey, []parser.ParseResult{
+				{Title: "Upcoming.2025.1080p.WEB-DL-GRP", Magnet: "magnet:?xt=urn:btih:rss", Indexer: "test-rss-indexer"},
+			})
+
+			Eventually(func() string {
+				err := k8sClient.Get(ctx, trLookupKey, createdTR)
+				if err != nil {
+					return ""
+				}
+				return createdTR.Status.State
+			}, timeout, interval).Should(Equal("Completed"))
+			Expect(createdTR.Status.Attempts).To(Equal(1))
+
+			torrent := &torrentsv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: createdTR.Status.FoundTorrent, Namespace: "default"}, torrent)).To(Succeed())
+			Expect(torrent.Spec.Magnet).To(Equal("magnet:?xt=urn:btih:rss"))
+		})
+
+		It("Should tell failing indexers from missing results", func() {
+			ctx := context.Background()
+			emptyIndexer(ctx, "test-broken-indexer", ghttp.RespondWith(http.StatusInternalServerError, "oops"))
+
+			tr := &torrentsv1alpha1.TorrentRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-broken-req",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.TorrentRequestSpec{
+					Keywords: "anything",
+					Indexers: []string{"test-broken-indexer"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tr)).To(Succeed())
+
+			trLookupKey := types.NamespacedName{Name: "test-broken-req", Namespace: "default"}
+			createdTR := &torrentsv1alpha1.TorrentRequest{}
+			Eventually(func() string {
+				err := k8sClient.Get(ctx, trLookupKey, createdTR)
+				if err != nil {
+					return ""
+				}
+				return createdTR.Status.State
+			}, timeout, interval).Should(Equal("Failed"))
+			Expect(createdTR.Status.Attempts).To(Equal(1))
+			cond := meta.FindStatusCondition(createdTR.Status.Conditions, "Ready")
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal("AllIndexersFailed"))
+		})
+	})
+	Context("When monitoring a request for upgrades", func() {
+		It("Should replace the Torrent until the upgradeUntil quality is found", func() {
+			ctx := context.Background()
+
+			row := func(title, hash string) string {
+				return `<tr class="result"><td class="title">` + title +
+					`</td><td><a class="dl" href="magnet:?xt=urn:btih:` + hash + `">Download</a></td></tr>`
+			}
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusOK, "<html><table></table></html>"),
+				ghttp.RespondWith(http.StatusOK, "<html><table>"+row("Show.2021.720p.HDTV.x264-LOL", "hdtv")+"</table></html>"),
+			)
+			for i := 0; i < 5; i++ {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusOK, "<html><table>"+
+					row("Show.2021.720p.HDTV.x264-LOL", "hdtv")+
+					row("Show.2021.1080p.WEB-DL.H.264-NTb", "webdl")+"</table></html>"))
+			}
+
+			indexer := &torrentsv1alpha1.Indexer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-monitor-indexer",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.IndexerSpec{
+					Links: []string{server.URL()},
+					Caps: torrentsv1alpha1.Caps{
+						Modes: torrentsv1alpha1.Modes{
+							Search: []string{"q"},
+						},
+					},
+					Search: &torrentsv1alpha1.Search{
+						Rows: torrentsv1alpha1.RowsBlock{
+							Selector: "tr.result",
+						},
+						Fields: torrentsv1alpha1.FieldsBlock{
+							"title":    torrentsv1alpha1.SelectorBlock{Selector: ".title"},
+							"download": torrentsv1alpha1.SelectorBlock{Selector: ".dl", Attribute: "href"},
+						},
+						Paths: []torrentsv1alpha1.SearchPathBlock{
+							{Path: "/search?q={{ .Keywords }}"},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, indexer)).To(Succeed())
+
+			indexer.Status.Conditions = []metav1.Condition{
+				{
+					Type:               "Ready",
+					Status:             metav1.ConditionTrue,
+					Reason:             "HealthCheckSucceeded",
+					Message:            "Indexer is healthy",
+					LastTransitionTime: metav1.Now(),
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, indexer)).To(Succeed())
+
+			profile := &torrentsv1alpha1.QualityProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-monitor-hd",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.QualityProfileSpec{
+					Qualities: []torrentsv1alpha1.Quality{
+						{Resolution: "2160p"},
+						{Resolution: "1080p", Source: "WEB-DL"},
+						{Resolution: "720p"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, profile)).To(Succeed())
+
+			tr := &torrentsv1alpha1.TorrentRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-monitor-req",
+					Namespace: "default",
+				},
+				Spec: torrentsv1alpha1.TorrentRequestSpec{
+					Keywords:          "show",
+					Indexers:          []string{"test-monitor-indexer"},
+					QualityProfileRef: &corev1.LocalObjectReference{Name: "test-monitor-hd"},
+					Mode:              torrentsv1alpha1.ModeMonitor,
+					SearchInterval:    &metav1.Duration{Duration: 2 * time.Second},
+					UpgradeUntil:      "WEB-DL 1080p",
+				},
+			}
+			Expect(k8sClient.Create(ctx, tr)).To(Succeed())
+
+			trLookupKey := types.NamespacedName{Name: "test-monitor-req", Namespace: "default"}
+			createdTR := &torrentsv1alpha1.TorrentRequest{}
+			Eventually(func() string {
+				err := k8sClient.Get(ctx, trLookupKey, createdTR)
+				if err != nil {
+					return ""
+				}
+				return createdTR.Status.FoundTorrent
+			}, timeout, interval).ShouldNot(BeEmpty())
+			Expect(createdTR.Status.State).To(Equal("Monitoring"))
+			Expect(createdTR.Status.BestCandidate).NotTo(BeNil())
+			Expect(createdTR.Status.BestCandidate.Quality).To(Equal("HDTV 720p"))
+			first := createdTR.Status.FoundTorrent
+
+			Eventually(func() string {
+				err := k8sClient.Get(ctx, trLookupKey, createdTR)
+				if err != nil {
+					return ""
+				}
+				return createdTR.Status.State
+			}, timeout, interval).Should(Equal("Completed"))
+			Expect(createdTR.Status.FoundTorrent).NotTo(Equal(first))
+			Expect(createdTR.Status.BestCandidate.Quality).To(Equal("WEB-DL 1080p"))
+			Expect(createdTR.Status.NextSearchTime).To(BeNil())
+
+			torrent := &torrentsv1alpha1.Torrent{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: createdTR.Status.FoundTorrent, Namespace: "default"}, torrent)).To(Succeed())
+			Expect(torrent.Spec.Magnet).To(Equal("magnet:?xt=urn:btih:webdl"))
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: first, Namespace: "default"}, &torrentsv1alpha1.Torrent{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("upgradeCandidate", func() {
+	profile, err := selection.NewProfile(torrentsv1alpha1.QualityProfileSpec{
+		Qualities: []torrentsv1alpha1.Quality{
+			{Resolution: "2160p"},
+			{Resolution: "1080p", Source: "WEB-DL"},
+			{Resolution: "720p"},
+		},
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	candidate := func(title string, rejected bool) selection.Candidate {
+		c := selection.Candidate{ParseResult: parser.ParseResult{Title: title}, Release: releaseparse.Parse(title)}
+		if rejected {
+			c.Rejections = []string{"rejected"}
+		}
+		return c
+	}
+	candidates := []selection.Candidate{
+		candidate("Show.720p.HDTV-A", false),
+		candidate("Show.2160p.WEB-DL-B", true),
+		candidate("Show.1080p.WEB-DL-C", false),
+	}
+
+	DescribeTable("picks the best accepted quality above the current one",
+		func(resolution, source, want string) {
+			upgrade := upgradeCandidate(profile, candidates, resolution, source)
+			if want == "" {
+				Expect(upgrade).To(BeNil())
+				return
+			}
+			Expect(upgrade).NotTo(BeNil())
+			Expect(upgrade.Title).To(Equal(want))
+		},
+		Entry("from an unlisted quality", "480p", "DVD", "Show.1080p.WEB-DL-C"),
+		Entry("from a lower quality", "720p", "HDTV", "Show.1080p.WEB-DL-C"),
+		Entry("at the best accepted quality", "1080p", "WEB-DL", ""),
+		Entry("above every candidate", "2160p", "BluRay", ""),
+	)
+
+	DescribeTable("stops at upgradeUntil",
+		func(upgradeUntil, resolution, source string, done bool) {
+			tr := &torrentsv1alpha1.TorrentRequest{Spec: torrentsv1alpha1.TorrentRequestSpec{UpgradeUntil: upgradeUntil}}
+			Expect(upgradeDone(tr, profile, resolution, source)).To(Equal(done))
+		},
+		Entry("without upgradeUntil", "", "720p", "HDTV", true),
+		Entry("below it", "WEB-DL 1080p", "720p", "HDTV", false),
+		Entry("at it", "WEB-DL 1080p", "1080p", "WEB-DL", true),
+		Entry("above it", "WEB-DL 1080p", "2160p", "BluRay", true),
+	)
+
+	It("stops at the cutoff of the profile", func() {
+		withCutoff, err := selection.NewProfile(torrentsv1alpha1.QualityProfileSpec{
+			Qualities: []torrentsv1alpha1.Quality{{Resolution: "2160p"}, {Resolution: "1080p"}, {Resolution: "720p"}},
+			Cutoff:    "1080p",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		tr := &torrentsv1alpha1.TorrentRequest{Spec: torrentsv1alpha1.TorrentRequestSpec{UpgradeUntil: "2160p"}}
+		Expect(upgradeDone(tr, withCutoff, "720p", "HDTV")).To(BeFalse())
+		Expect(upgradeDone(tr, withCutoff, "1080p", "WEB-DL")).To(BeTrue())
+	})
+})
+
+var _ = Describe("nextSearchTime", func() {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	request := func(policy *torrentsv1alpha1.RetryPolicy, attempts int) *torrentsv1alpha1.TorrentRequest {
+		return &torrentsv1alpha1.TorrentRequest{
+			Spec:   torrentsv1alpha1.TorrentRequestSpec{Retry: policy},
+			Status: torrentsv1alpha1.TorrentRequestStatus{Attempts: attempts},
+		}
+	}
+
+	DescribeTable("schedules retries",
+		func(policy *torrentsv1alpha1.RetryPolicy, attempts int, want time.Duration, retry bool) {
+			next, ok := nextSearchTime(request(policy, attempts), now)
+			Expect(ok).To(Equal(retry))
+			if retry {
+				Expect(next.Sub(now)).To(Equal(want))
+			}
+		},
+		Entry("without a policy", nil, 1, time.Duration(0), false),
+		Entry("first retry", &torrentsv1alpha1.RetryPolicy{}, 1, 5*time.Minute, true),
+		Entry("doubling", &torrentsv1alpha1.RetryPolicy{}, 3, 20*time.Minute, true),
+		Entry("custom backoff", &torrentsv1alpha1.RetryPolicy{Backoff: &metav1.Duration{Duration: time.Hour}}, 2, 2*time.Hour, true),
+		Entry("capped at a day", &torrentsv1alpha1.RetryPolicy{}, 40, 24*time.Hour, true),
+		Entry("attempts exhausted", &torrentsv1alpha1.RetryPolicy{MaxAttempts: 3}, 3, time.Duration(0), false),
+		Entry("attempts left", &torrentsv1alpha1.RetryPolicy{MaxAttempts: 3}, 2, 10*time.Minute, true),
+		Entry("past retryUntil", &torrentsv1alpha1.RetryPolicy{RetryUntil: &metav1.Time{Time: now.Add(time.Minute)}}, 1, time.Duration(0), false),
+		Entry("before retryUntil", &torrentsv1alpha1.RetryPolicy{RetryUntil: &metav1.Time{Time: now.Add(time.Hour)}}, 1, 5*time.Minute, true),
+	)
+})
+
+var _ = Describe("nextCandidate", func() {
+	tr := &torrentsv1alpha1.TorrentRequest{ObjectMeta: metav1.ObjectMeta{Name: "movie", UID: "movie-uid"}}
+	stalled := &torrentsv1alpha1.Torrent{Spec: torrentsv1alpha1.TorrentSpec{Title: "Movie.1080p-A", InfoHash: "aaaa"}}
+	result := func(position int, title, infoHash string, chosen, rejected bool) torrentsv1alpha1.SearchResult {
+		sr := torrentsv1alpha1.SearchResult{Spec: torrentsv1alpha1.SearchResultSpec{
+			Position: position,
+			Magnet:   "magnet:?xt=urn:btih:" + infoHash,
+			CandidateStatus: torrentsv1alpha1.CandidateStatus{
+				Title: title, InfoHash: infoHash, Chosen: chosen, Rejected: rejected,
+			},
+		}}
+		sr.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(tr, torrentsv1alpha1.GroupVersion.WithKind("TorrentRequest"))}
+		return sr
+	}
+	title := func(results ...torrentsv1alpha1.SearchResult) string {
+		if sr := nextCandidate(tr, results, stalled); sr != nil {
+			return sr.Spec.Title
+		}
+		return ""
+	}
+
+	It("picks the best ranked result not tried yet", func() {
+		Expect(title(
+			result(3, "Movie.720p-D", "dddd", false, false),
+			result(1, "Movie.1080p-A", "AAAA", true, false),
+			result(2, "Movie.1080p-B", "bbbb", false, false),
+		)).To(Equal("Movie.1080p-B"))
+	})
+
+	It("skips rejected results and the stalled release", func() {
+		Expect(title(
+			result(1, "Movie.1080p-A", "AAAA", false, false),
+			result(2, "Movie.CAM-C", "cccc", false, true),
+			result(3, "Movie.720p-D", "dddd", false, false),
+		)).To(Equal("Movie.720p-D"))
+	})
+
+	It("finds nothing once every result was tried", func() {
+		other := result(2, "Movie.720p-D", "dddd", false, false)
+		other.OwnerReferences = nil
+		Expect(title(result(1, "Movie.1080p-B", "bbbb", true, false), other)).To(BeEmpty())
+	})
 })
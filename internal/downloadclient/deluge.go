@@ -0,0 +1,296 @@
+package downloadclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// delugeNotAuthenticated is the error code of calls without a session
+const delugeNotAuthenticated = 1
+
+// delugeFields are the get_torrent_status keys a Status is built from
+var delugeFields = []string{
+	"hash", "name", "state", "progress", "download_payload_rate", "upload_payload_rate",
+	"eta", "ratio", "download_location", "completed_time", "num_seeds", "message",
+}
+
+// Deluge talks to the JSON-RPC API of the Deluge web UI, which forwards the
+// core calls to the daemon it is connected to
+type Deluge struct {
+	url        string
+	password   string
+	httpClient *http.Client
+
+	// mu serializes logins, the session cookie lives in the client jar
+	mu       sync.Mutex
+	loggedIn bool
+	nextID   atomic.Int64
+}
+
+// NewDeluge returns a client for the Deluge web UI at cfg.URL. The web UI
+// only takes a password, the username is ignored.
+func NewDeluge(cfg Config) *Deluge {
+	httpClient := *cfg.HTTPClient
+	httpClient.Jar, _ = cookiejar.New(nil)
+	return &Deluge{
+		url:        strings.TrimRight(cfg.URL, "/") + "/json",
+		password:   cfg.Password,
+		httpClient: &httpClient,
+	}
+}
+
+// delugeError is the error member of a JSON-RPC response
+type delugeError struct {
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+}
+
+func (e *delugeError) Error() string {
+	return e.Message
+}
+
+// delugeTorrent is a torrent as returned by core.get_torrent_status
+type delugeTorrent struct {
+	Hash             string  `json:"hash"`
+	Name             string  `json:"name"`
+	State            string  `json:"state"`
+	Progress         float64 `json:"progress"`
+	DownloadRate     float64 `json:"download_payload_rate"`
+	UploadRate       float64 `json:"upload_payload_rate"`
+	ETA              float64 `json:"eta"`
+	Ratio            float64 `json:"ratio"`
+	DownloadLocation string  `json:"download_location"`
+	CompletedTime    float64 `json:"completed_time"`
+	NumSeeds         int     `json:"num_seeds"`
+	Message          string  `json:"message"`
+}
+
+// Add submits the magnet, or the .torrent file, and files it under the
+// category as a label. Deluge has a single label per torrent and no tags.
+func (c *Deluge) Add(ctx context.Context, t Torrent) (string, error) {
+	if t.InfoHash == "" {
+		return "", fmt.Errorf("torrent %q has no info hash", t.Name)
+	}
+	options := map[string]any{}
+	if t.SavePath != "" {
+		options["download_location"] = t.SavePath
+	}
+
+	var hash *string
+	var err error
+	if len(t.File) > 0 {
+		err = c.call(ctx, "core.add_torrent_file", []any{t.Name + ".torrent", base64.StdEncoding.EncodeToString(t.File), options}, &hash)
+	} else {
+		err = c.call(ctx, "core.add_torrent_magnet", []any{t.Magnet, options}, &hash)
+	}
+	// Torrents already downloading, e.g. added by hand, are kept as is
+	var rpcErr *delugeError
+	if err != nil && !(errors.As(err, &rpcErr) && strings.Contains(rpcErr.Message, "already in session")) {
+		return "", err
+	}
+	if hash == nil || *hash == "" {
+		hash = &t.InfoHash
+	}
+	id := strings.ToLower(*hash)
+
+	if t.Category != "" {
+		if err := c.setLabel(ctx, id, strings.ToLower(t.Category)); err != nil {
+			return "", fmt.Errorf("labelling torrent %q: %w", t.Name, err)
+		}
+	}
+	return id, nil
+}
+
+// setLabel sets the label of the torrent, creating it on first use. Labels
+// need the Label plugin, and are lowercase.
+func (c *Deluge) setLabel(ctx context.Context, hash, label string) error {
+	err := c.call(ctx, "label.set_torrent", []any{hash, label}, nil)
+	var rpcErr *delugeError
+	if err == nil || !errors.As(err, &rpcErr) || !strings.Contains(rpcErr.Message, "Unknown Label") {
+		return err
+	}
+	if err := c.call(ctx, "label.add", []any{label}, nil); err != nil {
+		return err
+	}
+	return c.call(ctx, "label.set_torrent", []any{hash, label}, nil)
+}
+
+// Get returns the state of the torrent from core.get_torrent_status
+func (c *Deluge) Get(ctx context.Context, hash string) (*Status, error) {
+	var t *delugeTorrent
+	if err := c.call(ctx, "core.get_torrent_status", []any{hash, delugeFields}, &t); err != nil {
+		return nil, err
+	}
+	// Unknown torrents have an empty status
+	if t == nil || t.Hash == "" {
+		return nil, ErrNotFound
+	}
+
+	status := &Status{
+		Hash:         strings.ToLower(t.Hash),
+		Name:         t.Name,
+		State:        delugeState(t.State),
+		Progress:     t.Progress / 100,
+		DownloadRate: int64(t.DownloadRate),
+		UploadRate:   int64(t.UploadRate),
+		ETA:          time.Duration(max(t.ETA, 0)) * time.Second,
+		Ratio:        max(t.Ratio, 0),
+		SavePath:     t.DownloadLocation,
+		Seeders:      t.NumSeeds,
+	}
+	if t.CompletedTime > 0 {
+		status.CompletedAt = time.Unix(int64(t.CompletedTime), 0)
+	}
+	if status.State == StateError {
+		status.Error = t.Message
+	}
+	return status, nil
+}
+
+// delugeState maps the torrent states of Deluge
+func delugeState(state string) State {
+	switch state {
+	case "Queued":
+		return StateQueued
+	case "Checking", "Moving", "Allocating":
+		return StateChecking
+	case "Seeding":
+		return StateSeeding
+	case "Paused":
+		return StatePaused
+	case "Error":
+		return StateError
+	default:
+		return StateDownloading
+	}
+}
+
+// Remove deletes the torrent from Deluge
+func (c *Deluge) Remove(ctx context.Context, hash string, removeData bool) error {
+	err := c.call(ctx, "core.remove_torrent", []any{hash, removeData}, nil)
+	var rpcErr *delugeError
+	if errors.As(err, &rpcErr) && strings.Contains(rpcErr.Message, "not in session") {
+		return nil
+	}
+	return err
+}
+
+// Version returns the version of the Deluge daemon
+func (c *Deluge) Version(ctx context.Context) (string, error) {
+	var version string
+	if err := c.call(ctx, "daemon.info", []any{}, &version); err != nil {
+		return "", err
+	}
+	return version, nil
+}
+
+// call invokes the RPC method and decodes its result into out, logging in
+// first if needed and once more when the session expired
+func (c *Deluge) call(ctx context.Context, method string, params []any, out any) error {
+	if err := c.login(ctx, false); err != nil {
+		return err
+	}
+	err := c.do(ctx, method, params, out)
+	var rpcErr *delugeError
+	if errors.As(err, &rpcErr) && rpcErr.Code == delugeNotAuthenticated {
+		if err := c.login(ctx, true); err != nil {
+			return err
+		}
+		err = c.do(ctx, method, params, out)
+	}
+	return err
+}
+
+// login opens a session unless one is open already, or force is set, and
+// connects the web UI to its first daemon when it is not connected to one
+func (c *Deluge) login(ctx context.Context, force bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.loggedIn && !force {
+		return nil
+	}
+
+	c.loggedIn = false
+	var ok bool
+	if err := c.do(ctx, "auth.login", []any{c.password}, &ok); err != nil {
+		return err
+	}
+	if !ok {
+		return ErrUnauthorized
+	}
+
+	var connected bool
+	if err := c.do(ctx, "web.connected", []any{}, &connected); err != nil {
+		return err
+	}
+	if !connected {
+		// Hosts are listed as [id, host, port, status]
+		var hosts [][]any
+		if err := c.do(ctx, "web.get_hosts", []any{}, &hosts); err != nil {
+			return err
+		}
+		if len(hosts) == 0 || len(hosts[0]) == 0 {
+			return fmt.Errorf("deluge web UI has no daemon to connect to")
+		}
+		if err := c.do(ctx, "web.connect", []any{hosts[0][0]}, nil); err != nil {
+			return fmt.Errorf("connecting deluge web UI to its daemon: %w", err)
+		}
+	}
+	c.loggedIn = true
+	return nil
+}
+
+func (c *Deluge) do(ctx context.Context, method string, params []any, out any) error {
+	body, err := json.Marshal(map[string]any{"method": method, "params": params, "id": c.nextID.Add(1)})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("deluge %s returned status %d", method, resp.StatusCode)
+	}
+
+	var rpc struct {
+		Result json.RawMessage `json:"result"`
+		Error  *delugeError    `json:"error"`
+	}
+	if err := json.Unmarshal(data, &rpc); err != nil {
+		return fmt.Errorf("invalid deluge %s response: %w", method, err)
+	}
+	if rpc.Error != nil {
+		return fmt.Errorf("deluge %s failed: %w", method, rpc.Error)
+	}
+	if out == nil || len(rpc.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(rpc.Result, out); err != nil {
+		return fmt.Errorf("invalid deluge %s response: %w", method, err)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package downloadclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+)
+
+// fakeDeluge stands in for the JSON-RPC API of the Deluge web UI
+type fakeDeluge struct {
+	mu        sync.Mutex
+	sessions  map[string]bool
+	logins    int
+	connected bool
+	labels    map[string]bool
+	// calls records the params of each call by method
+	calls    map[string][][]any
+	torrents map[string]map[string]any
+}
+
+func newFakeDeluge(t *testing.T) (*fakeDeluge, *httptest.Server) {
+	f := &fakeDeluge{
+		sessions: map[string]bool{},
+		labels:   map[string]bool{},
+		calls:    map[string][][]any{},
+		torrents: map[string]map[string]any{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /json", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			Params []any  `json:"params"`
+			ID     int    `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.calls[req.Method] = append(f.calls[req.Method], req.Params)
+
+		var result, rpcErr any
+		fail := func(code int, message string) {
+			rpcErr = map[string]any{"message": message, "code": code}
+		}
+		cookie, err := r.Cookie("_session_id")
+		authed := err == nil && f.sessions[cookie.Value]
+		switch {
+		case req.Method == "auth.login":
+			result = req.Params[0] == "deluge"
+			if result == true {
+				f.logins++
+				sid := fmt.Sprintf("session%d", f.logins)
+				f.sessions[sid] = true
+				http.SetCookie(w, &http.Cookie{Name: "_session_id", Value: sid, Path: "/"})
+			}
+		case !authed:
+			fail(1, "Not authenticated")
+		case req.Method == "web.connected":
+			result = f.connected
+		case req.Method == "web.get_hosts":
+			result = []any{[]any{"c0ffee", "127.0.0.1", 58846, "Offline"}}
+		case req.Method == "web.connect":
+			f.connected = req.Params[0] == "c0ffee"
+		case !f.connected:
+			fail(2, "Unknown method")
+		case req.Method == "daemon.info":
+			result = "2.1.1"
+		case req.Method == "core.add_torrent_magnet" && req.Params[0] == "magnet:?xt=urn:btih:DUPLICATE":
+			fail(3, "AddTorrentError: Torrent already in session (dddd).")
+		case req.Method == "core.add_torrent_magnet", req.Method == "core.add_torrent_file":
+			result = "abcdef"
+		case req.Method == "label.add":
+			f.labels[req.Params[0].(string)] = true
+		case req.Method == "label.set_torrent":
+			if !f.labels[req.Params[1].(string)] {
+				fail(3, "Exception: Unknown Label")
+			}
+		case req.Method == "core.get_torrent_status":
+			torrent, ok := f.torrents[req.Params[0].(string)]
+			if !ok {
+				torrent = map[string]any{}
+			}
+			result = torrent
+		case req.Method == "core.remove_torrent":
+			if req.Params[0] == "unknown" {
+				fail(3, "InvalidTorrentError: torrent_id unknown not in session.")
+			} else {
+				result = true
+			}
+		default:
+			fail(2, "Unknown method")
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": result, "error": rpcErr, "id": req.ID})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+// expire drops every session, as a web UI restart does
+func (f *fakeDeluge) expire() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sessions = map[string]bool{}
+}
+
+func newTestDeluge(t *testing.T, url, password string) Client {
+	c, err := New(torrentsv1alpha1.DownloadClientDeluge, Config{URL: url, Password: password})
+	require.NoError(t, err)
+	return c
+}
+
+func TestDelugeConnectsToDaemon(t *testing.T) {
+	f, server := newFakeDeluge(t)
+	c := newTestDeluge(t, server.URL+"/", "deluge")
+
+	version, err := c.Version(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "2.1.1", version)
+	assert.True(t, f.connected)
+	assert.Equal(t, [][]any{{"c0ffee"}}, f.calls["web.connect"])
+
+	// The session is reused
+	_, err = c.Version(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.logins)
+}
+
+func TestDelugeAdd(t *testing.T) {
+	f, server := newFakeDeluge(t)
+	c := newTestDeluge(t, server.URL, "deluge")
+
+	hash, err := c.Add(context.Background(), Torrent{
+		Name:     "The.Show.S01E02.1080p.WEB-DL-NTb",
+		Magnet:   "magnet:?xt=urn:btih:ABCDEF",
+		InfoHash: "abcdef",
+		Category: "TV",
+		SavePath: "/downloads/tv",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "abcdef", hash)
+	assert.Equal(t, [][]any{{"magnet:?xt=urn:btih:ABCDEF", map[string]any{"download_location": "/downloads/tv"}}}, f.calls["core.add_torrent_magnet"])
+	// The label is created on first use
+	assert.Equal(t, [][]any{{"tv"}}, f.calls["label.add"])
+	assert.Equal(t, [][]any{{"abcdef", "tv"}, {"abcdef", "tv"}}, f.calls["label.set_torrent"])
+
+	_, err = c.Add(context.Background(), Torrent{Name: "Some.Movie.2020", File: []byte("d4:infodee"), InfoHash: "abcdef"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{"Some.Movie.2020.torrent", "ZDQ6aW5mb2RlZQ==", map[string]any{}}}, f.calls["core.add_torrent_file"])
+
+	hash, err = c.Add(context.Background(), Torrent{Name: "dup", Magnet: "magnet:?xt=urn:btih:DUPLICATE", InfoHash: "DDDD"})
+	require.NoError(t, err)
+	assert.Equal(t, "dddd", hash)
+}
+
+func TestDelugeGet(t *testing.T) {
+	f, server := newFakeDeluge(t)
+	c := newTestDeluge(t, server.URL, "deluge")
+	f.torrents["abcdef"] = map[string]any{
+		"hash": "abcdef", "name": "Some.Movie", "state": "Downloading", "progress": 42.5,
+		"download_payload_rate": 2048, "upload_payload_rate": 512, "eta": 90, "ratio": -1,
+		"download_location": "/downloads", "completed_time": 0, "num_seeds": 4, "message": "OK",
+	}
+	f.torrents["broken"] = map[string]any{"hash": "broken", "state": "Error", "message": "No space left on device"}
+
+	status, err := c.Get(context.Background(), "abcdef")
+	require.NoError(t, err)
+	assert.Equal(t, &Status{
+		Hash: "abcdef", Name: "Some.Movie", State: StateDownloading, Progress: 0.425,
+		DownloadRate: 2048, UploadRate: 512, ETA: 90 * time.Second,
+		SavePath: "/downloads", Seeders: 4,
+	}, status)
+
+	status, err = c.Get(context.Background(), "broken")
+	require.NoError(t, err)
+	assert.Equal(t, StateError, status.State)
+	assert.Equal(t, "No space left on device", status.Error)
+
+	_, err = c.Get(context.Background(), "unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestDelugeRemove(t *testing.T) {
+	f, server := newFakeDeluge(t)
+	c := newTestDeluge(t, server.URL, "deluge")
+
+	require.NoError(t, c.Remove(context.Background(), "abcdef", true))
+	require.NoError(t, c.Remove(context.Background(), "unknown", false))
+	assert.Equal(t, [][]any{{"abcdef", true}, {"unknown", false}}, f.calls["core.remove_torrent"])
+}
@@ -0,0 +1,174 @@
+package downloadclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+)
+
+// Torrent is a torrent handed to a download client
+type Torrent struct {
+	// Name of the release, used as the file name of uploaded .torrent files
+	Name string
+	// Magnet link, unset when File is set
+	Magnet string
+	// File is the content of a .torrent file
+	File []byte
+	// InfoHash is the BitTorrent v1 info hash as lowercase hex
+	InfoHash string
+	// Category the client files the torrent under
+	Category string
+	// SavePath the torrent is downloaded to, the client default when empty
+	SavePath string
+	// Tags added to the torrent
+	Tags []string
+}
+
+// State is the state of a torrent in a download client, common to all
+// clients
+type State string
+
+const (
+	// StateQueued torrents wait for a download slot
+	StateQueued State = "Queued"
+	// StateChecking torrents have their data verified or moved
+	StateChecking State = "Checking"
+	// StateDownloading torrents are downloading, or looking for peers to
+	// download from
+	StateDownloading State = "Downloading"
+	// StateSeeding torrents are complete and uploading
+	StateSeeding State = "Seeding"
+	// StatePaused torrents were stopped, complete or not
+	StatePaused State = "Paused"
+	// StateError torrents failed, e.g. on missing files or a full disk
+	StateError State = "Error"
+)
+
+// Status is the state of a torrent in a download client
+type Status struct {
+	Hash  string
+	Name  string
+	State State
+	// Progress of the download, from 0 to 1
+	Progress float64
+	// DownloadRate and UploadRate in bytes per second
+	DownloadRate int64
+	UploadRate   int64
+	// ETA is the time left to complete the download, 0 when unknown
+	ETA time.Duration
+	// Ratio of the uploaded to the downloaded data
+	Ratio float64
+	// SavePath the torrent is downloaded to
+	SavePath string
+	// CompletedAt is when the download completed, zero until then
+	CompletedAt time.Time
+	// Seeders connected to
+	Seeders int
+	// Error reported by the client for StateError torrents
+	Error string
+}
+
+// ErrNotFound is returned for torrents the client does not know
+var ErrNotFound = errors.New("torrent not found in download client")
+
+// Client hands torrents to a download client
+type Client interface {
+	// Add submits the torrent and returns the hash identifying it in the
+	// client
+	Add(ctx context.Context, t Torrent) (string, error)
+	// Get returns the state of the torrent, ErrNotFound when the client
+	// does not know it
+	Get(ctx context.Context, hash string) (*Status, error)
+	// Remove deletes the torrent from the client, along with its files when
+	// removeData is set. Removing an unknown torrent is not an error.
+	Remove(ctx context.Context, hash string, removeData bool) error
+	// Version checks the client is reachable and returns its version
+	Version(ctx context.Context) (string, error)
+}
+
+// Config holds what a client needs to reach its API
+type Config struct {
+	// URL of the client API, e.g. http://qbittorrent:8080
+	URL      string
+	Username string
+	Password string
+	// HTTPClient used to reach the API, http.DefaultClient when nil
+	HTTPClient *http.Client
+}
+
+// ErrUnauthorized is returned when the client rejects the credentials
+var ErrUnauthorized = errors.New("download client rejected the credentials")
+
+// ErrRemoveDataUnsupported is returned by clients that cannot delete the
+// files of the torrents they remove
+var ErrRemoveDataUnsupported = errors.New("download client cannot delete downloaded files")
+
+// New returns the client of the given type
+func New(typ torrentsv1alpha1.DownloadClientType, cfg Config) (Client, error) {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	switch typ {
+	case torrentsv1alpha1.DownloadClientQBittorrent:
+		return NewQBittorrent(cfg), nil
+	case torrentsv1alpha1.DownloadClientTransmission:
+		return NewTransmission(cfg), nil
+	case torrentsv1alpha1.DownloadClientDeluge:
+		return NewDeluge(cfg), nil
+	case torrentsv1alpha1.DownloadClientRTorrent:
+		return NewRTorrent(cfg), nil
+	default:
+		return nil, fmt.Errorf("unsupported download client type %q", typ)
+	}
+}
+
+// Entry is a client registered from a DownloadClient resource
+type Entry struct {
+	Client Client
+	// Ready is false while the client fails its health check
+	Ready bool
+}
+
+// Registry holds the clients built from DownloadClient resources, so
+// Torrents reuse their sessions
+type Registry struct {
+	mu      sync.RWMutex
+	entries map[types.NamespacedName]*Entry
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Set registers or replaces the client of the DownloadClient called key
+func (r *Registry) Set(key types.NamespacedName, entry *Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.entries == nil {
+		r.entries = make(map[types.NamespacedName]*Entry)
+	}
+	r.entries[key] = entry
+}
+
+// Get returns the client of the DownloadClient called key
+func (r *Registry) Get(key types.NamespacedName) (*Entry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.entries[key]
+	return entry, ok
+}
+
+// Remove unregisters the client of the DownloadClient called key
+func (r *Registry) Remove(key types.NamespacedName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, key)
+}
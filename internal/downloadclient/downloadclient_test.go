@@ -0,0 +1,103 @@
+package downloadclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+)
+
+// fakeClient is a fake download client server as the shared tests see it
+type fakeClient struct {
+	url string
+	// expire drops the sessions of the client, nil when it keeps none
+	expire func()
+	// logins counts the sessions the client opened
+	logins func() int
+}
+
+// clientCases are the download clients the shared tests run against
+var clientCases = []struct {
+	name     string
+	typ      torrentsv1alpha1.DownloadClientType
+	username string
+	password string
+	serve    func(t *testing.T) fakeClient
+}{
+	{
+		name:     "qBittorrent",
+		typ:      torrentsv1alpha1.DownloadClientQBittorrent,
+		username: "admin",
+		password: "secret",
+		serve: func(t *testing.T) fakeClient {
+			f, server := newFakeQBittorrent(t)
+			return fakeClient{url: server.URL, expire: f.expire, logins: func() int { return f.logins }}
+		},
+	},
+	{
+		name:     "Transmission",
+		typ:      torrentsv1alpha1.DownloadClientTransmission,
+		username: "admin",
+		password: "secret",
+		serve: func(t *testing.T) fakeClient {
+			f, server := newFakeTransmission(t)
+			return fakeClient{url: server.URL, expire: f.expire, logins: func() int { return f.conflicts }}
+		},
+	},
+	{
+		name:     "Deluge",
+		typ:      torrentsv1alpha1.DownloadClientDeluge,
+		password: "deluge",
+		serve: func(t *testing.T) fakeClient {
+			f, server := newFakeDeluge(t)
+			return fakeClient{url: server.URL, expire: f.expire, logins: func() int { return f.logins }}
+		},
+	},
+	{
+		name:     "rTorrent",
+		typ:      torrentsv1alpha1.DownloadClientRTorrent,
+		username: "admin",
+		password: "secret",
+		serve: func(t *testing.T) fakeClient {
+			_, server := newFakeRTorrent(t)
+			return fakeClient{url: server.URL}
+		},
+	},
+}
+
+func TestClientLoginAgainWhenSessionExpires(t *testing.T) {
+	for _, tc := range clientCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := tc.serve(t)
+			if fake.expire == nil {
+				t.Skip("no session to expire")
+			}
+			c, err := New(tc.typ, Config{URL: fake.url, Username: tc.username, Password: tc.password})
+			require.NoError(t, err)
+
+			_, err = c.Version(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, 1, fake.logins())
+
+			fake.expire()
+			require.NoError(t, c.Remove(context.Background(), "abcdef", false))
+			assert.Equal(t, 2, fake.logins())
+		})
+	}
+}
+
+func TestClientBadCredentials(t *testing.T) {
+	for _, tc := range clientCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := tc.serve(t)
+			c, err := New(tc.typ, Config{URL: fake.url, Username: tc.username, Password: "wrong"})
+			require.NoError(t, err)
+
+			_, err = c.Version(context.Background())
+			assert.ErrorIs(t, err, ErrUnauthorized)
+		})
+	}
+}
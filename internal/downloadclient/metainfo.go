@@ -0,0 +1,84 @@
+package downloadclient
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"strconv"
+)
+
+// maxDepth bounds the nesting of bencoded values walked through
+const maxDepth = 64
+
+var errMalformed = errors.New("malformed .torrent file")
+
+// FileInfoHash computes the BitTorrent v1 info hash of a .torrent file, the
+// SHA-1 of its bencoded info dictionary, as lowercase hex
+func FileInfoHash(data []byte) (string, error) {
+	if len(data) == 0 || data[0] != 'd' {
+		return "", errMalformed
+	}
+	for i := 1; i < len(data) && data[i] != 'e'; {
+		key, start, err := bencodedString(data, i)
+		if err != nil {
+			return "", err
+		}
+		end, err := skipValue(data, start, 0)
+		if err != nil {
+			return "", err
+		}
+		if key == "info" {
+			sum := sha1.Sum(data[start:end])
+			return hex.EncodeToString(sum[:]), nil
+		}
+		i = end
+	}
+	return "", errors.New("no info dictionary in .torrent file")
+}
+
+// skipValue returns the offset following the bencoded value at i
+func skipValue(data []byte, i, depth int) (int, error) {
+	if i >= len(data) || depth > maxDepth {
+		return 0, errMalformed
+	}
+	switch c := data[i]; {
+	case c == 'i':
+		end := bytes.IndexByte(data[i:], 'e')
+		if end < 0 {
+			return 0, errMalformed
+		}
+		return i + end + 1, nil
+	case c == 'l' || c == 'd':
+		i++
+		for i < len(data) && data[i] != 'e' {
+			var err error
+			if i, err = skipValue(data, i, depth+1); err != nil {
+				return 0, err
+			}
+		}
+		if i >= len(data) {
+			return 0, errMalformed
+		}
+		return i + 1, nil
+	case c >= '0' && c <= '9':
+		_, end, err := bencodedString(data, i)
+		return end, err
+	default:
+		return 0, errMalformed
+	}
+}
+
+// bencodedString decodes the string at i and returns the offset following it
+func bencodedString(data []byte, i int) (string, int, error) {
+	colon := bytes.IndexByte(data[i:], ':')
+	if colon < 0 {
+		return "", 0, errMalformed
+	}
+	n, err := strconv.Atoi(string(data[i : i+colon]))
+	start := i + colon + 1
+	if err != nil || n < 0 || start+n > len(data) {
+		return "", 0, errMalformed
+	}
+	return string(data[start : start+n]), start + n, nil
+}
@@ -0,0 +1,35 @@
+package downloadclient
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileInfoHash(t *testing.T) {
+	info := "d6:lengthi1024e4:name8:file.iso12:piece lengthi16384e6:pieces20:abcdefghijklmnopqrste"
+	file := "d8:announce23:http://tracker/announce13:announce-listll23:http://tracker/announceee4:info" + info + "7:comment2:hie"
+	sum := sha1.Sum([]byte(info))
+
+	hash, err := FileInfoHash([]byte(file))
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(sum[:]), hash)
+}
+
+func TestFileInfoHashMalformed(t *testing.T) {
+	for _, file := range []string{
+		"",
+		"<html>not a torrent</html>",
+		"d8:announce3:url",
+		"d4:infod4:name",
+		"d4:infoi12",
+		"d8:announce99:short4:infodee",
+		"d7:comment2:hie",
+	} {
+		_, err := FileInfoHash([]byte(file))
+		assert.Error(t, err, file)
+	}
+}
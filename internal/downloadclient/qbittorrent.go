@@ -0,0 +1,287 @@
+package downloadclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// qbInfiniteETA is the ETA qBittorrent reports when it cannot estimate one
+const qbInfiniteETA = 8640000
+
+// qbTorrent is a torrent as listed by torrents/info
+type qbTorrent struct {
+	Hash         string  `json:"hash"`
+	Name         string  `json:"name"`
+	State        string  `json:"state"`
+	Progress     float64 `json:"progress"`
+	DLSpeed      int64   `json:"dlspeed"`
+	UPSpeed      int64   `json:"upspeed"`
+	ETA          int64   `json:"eta"`
+	Ratio        float64 `json:"ratio"`
+	SavePath     string  `json:"save_path"`
+	CompletionOn int64   `json:"completion_on"`
+	NumSeeds     int     `json:"num_seeds"`
+}
+
+// QBittorrent talks to the qBittorrent WebUI API v2
+type QBittorrent struct {
+	url        string
+	username   string
+	password   string
+	httpClient *http.Client
+
+	// mu serializes logins, the session cookie lives in the client jar
+	mu       sync.Mutex
+	loggedIn bool
+}
+
+// NewQBittorrent returns a client for the qBittorrent WebUI at cfg.URL. It
+// logs in on first use and again whenever the session expires.
+func NewQBittorrent(cfg Config) *QBittorrent {
+	httpClient := *cfg.HTTPClient
+	httpClient.Jar, _ = cookiejar.New(nil)
+	return &QBittorrent{
+		url:        strings.TrimRight(cfg.URL, "/"),
+		username:   cfg.Username,
+		password:   cfg.Password,
+		httpClient: &httpClient,
+	}
+}
+
+// Add submits the magnet, or uploads the .torrent file. Adding a torrent
+// qBittorrent already has succeeds, so an Add whose result was lost can be
+// retried.
+func (q *QBittorrent) Add(ctx context.Context, t Torrent) (string, error) {
+	if t.InfoHash == "" {
+		return "", fmt.Errorf("torrent %q has no info hash", t.Name)
+	}
+	body, err := q.call(ctx, http.MethodPost, "/api/v2/torrents/add", func() (io.Reader, string, error) {
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		if len(t.File) > 0 {
+			part, err := w.CreateFormFile("torrents", t.Name+".torrent")
+			if err != nil {
+				return nil, "", err
+			}
+			if _, err := part.Write(t.File); err != nil {
+				return nil, "", err
+			}
+		} else {
+			_ = w.WriteField("urls", t.Magnet)
+		}
+		if t.SavePath != "" {
+			_ = w.WriteField("savepath", t.SavePath)
+		}
+		if t.Category != "" {
+			_ = w.WriteField("category", t.Category)
+		}
+		if len(t.Tags) > 0 {
+			_ = w.WriteField("tags", strings.Join(t.Tags, ","))
+		}
+		if err := w.Close(); err != nil {
+			return nil, "", err
+		}
+		return &buf, w.FormDataContentType(), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	hash := strings.ToLower(t.InfoHash)
+	// Torrents the client refuses, like invalid ones or those it already
+	// has, are answered "Fails."
+	if strings.TrimSpace(string(body)) == "Fails." {
+		if _, err := q.Get(ctx, hash); err == nil {
+			return hash, nil
+		}
+		return "", fmt.Errorf("qBittorrent refused torrent %q", t.Name)
+	}
+	return hash, nil
+}
+
+// Get returns the state of the torrent from torrents/info
+func (q *QBittorrent) Get(ctx context.Context, hash string) (*Status, error) {
+	body, err := q.call(ctx, http.MethodPost, "/api/v2/torrents/info", formBody(url.Values{"hashes": {hash}}))
+	if err != nil {
+		return nil, err
+	}
+	var torrents []qbTorrent
+	if err := json.Unmarshal(body, &torrents); err != nil {
+		return nil, fmt.Errorf("invalid qBittorrent torrents/info response: %w", err)
+	}
+	if len(torrents) == 0 {
+		return nil, ErrNotFound
+	}
+
+	t := torrents[0]
+	status := &Status{
+		Hash:         t.Hash,
+		Name:         t.Name,
+		State:        qbState(t.State),
+		Progress:     t.Progress,
+		DownloadRate: t.DLSpeed,
+		UploadRate:   t.UPSpeed,
+		Ratio:        t.Ratio,
+		SavePath:     t.SavePath,
+		Seeders:      t.NumSeeds,
+	}
+	if t.ETA > 0 && t.ETA < qbInfiniteETA {
+		status.ETA = time.Duration(t.ETA) * time.Second
+	}
+	if t.CompletionOn > 0 {
+		status.CompletedAt = time.Unix(t.CompletionOn, 0)
+	}
+	if status.State == StateError {
+		status.Error = t.State
+	}
+	return status, nil
+}
+
+// qbState maps the torrent states of qBittorrent, "stopped" ones being named
+// "paused" before qBittorrent 5
+func qbState(state string) State {
+	switch state {
+	case "error", "missingFiles":
+		return StateError
+	case "uploading", "stalledUP", "forcedUP", "queuedUP":
+		return StateSeeding
+	case "pausedUP", "stoppedUP", "pausedDL", "stoppedDL":
+		return StatePaused
+	case "queuedDL":
+		return StateQueued
+	case "checkingUP", "checkingDL", "checkingResumeData", "moving":
+		return StateChecking
+	default:
+		// downloading, stalledDL, forcedDL, metaDL, allocating
+		return StateDownloading
+	}
+}
+
+// Remove deletes the torrent from qBittorrent
+func (q *QBittorrent) Remove(ctx context.Context, hash string, removeData bool) error {
+	_, err := q.call(ctx, http.MethodPost, "/api/v2/torrents/delete", formBody(url.Values{
+		"hashes":      {hash},
+		"deleteFiles": {fmt.Sprint(removeData)},
+	}))
+	return err
+}
+
+// Version returns the qBittorrent application version
+func (q *QBittorrent) Version(ctx context.Context) (string, error) {
+	body, err := q.call(ctx, http.MethodGet, "/api/v2/app/version", nil)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// formBody sends the form url encoded
+func formBody(form url.Values) func() (io.Reader, string, error) {
+	return func() (io.Reader, string, error) {
+		return strings.NewReader(form.Encode()), "application/x-www-form-urlencoded", nil
+	}
+}
+
+// call requests the API path, logging in first if needed and once more when
+// the session expired. body builds the request body and its content type, nil
+// for GET requests.
+func (q *QBittorrent) call(ctx context.Context, method, path string, body func() (io.Reader, string, error)) ([]byte, error) {
+	if err := q.login(ctx, false); err != nil {
+		return nil, err
+	}
+	resp, err := q.do(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.status == http.StatusForbidden {
+		if err := q.login(ctx, true); err != nil {
+			return nil, err
+		}
+		if resp, err = q.do(ctx, method, path, body); err != nil {
+			return nil, err
+		}
+	}
+	if resp.status < 200 || resp.status >= 300 {
+		return nil, fmt.Errorf("qBittorrent %s returned status %d: %s", path, resp.status, strings.TrimSpace(string(resp.body)))
+	}
+	return resp.body, nil
+}
+
+// login opens a session unless one is open already, or force is set.
+// Clients without credentials rely on qBittorrent bypassing authentication
+// for their network.
+func (q *QBittorrent) login(ctx context.Context, force bool) error {
+	if q.username == "" {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.loggedIn && !force {
+		return nil
+	}
+
+	q.loggedIn = false
+	resp, err := q.do(ctx, http.MethodPost, "/api/v2/auth/login", formBody(url.Values{
+		"username": {q.username},
+		"password": {q.password},
+	}))
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.status == http.StatusForbidden:
+		return fmt.Errorf("qBittorrent banned the operator after too many failed logins: %w", ErrUnauthorized)
+	case resp.status != http.StatusOK:
+		return fmt.Errorf("qBittorrent login returned status %d", resp.status)
+	case strings.TrimSpace(string(resp.body)) != "Ok.":
+		return ErrUnauthorized
+	}
+	q.loggedIn = true
+	return nil
+}
+
+type qbResponse struct {
+	status int
+	body   []byte
+}
+
+func (q *QBittorrent) do(ctx context.Context, method, path string, body func() (io.Reader, string, error)) (*qbResponse, error) {
+	var reader io.Reader
+	var contentType string
+	if body != nil {
+		var err error
+		if reader, contentType, err = body(); err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, q.url+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	// The WebUI CSRF protection rejects requests from another origin
+	req.Header.Set("Referer", q.url)
+	req.Header.Set("Origin", q.url)
+
+	resp, err := q.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	return &qbResponse{status: resp.StatusCode, body: data}, nil
+}
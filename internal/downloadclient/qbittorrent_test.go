@@ -0,0 +1,237 @@
+package downloadclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+)
+
+// fakeQBittorrent stands in for the qBittorrent WebUI API
+type fakeQBittorrent struct {
+	mu       sync.Mutex
+	sessions map[string]bool
+	logins   int
+	// added records the form fields of each torrents/add call
+	added []map[string]string
+	// files records the .torrent files uploaded
+	files   [][]byte
+	deleted []string
+	// torrents are listed by torrents/info, keyed by hash
+	torrents map[string]map[string]any
+}
+
+func newFakeQBittorrent(t *testing.T) (*fakeQBittorrent, *httptest.Server) {
+	f := &fakeQBittorrent{sessions: map[string]bool{}, torrents: map[string]map[string]any{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v2/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if r.FormValue("username") != "admin" || r.FormValue("password") != "secret" {
+			_, _ = io.WriteString(w, "Fails.")
+			return
+		}
+		f.logins++
+		sid := fmt.Sprintf("sid%d", f.logins)
+		f.sessions[sid] = true
+		http.SetCookie(w, &http.Cookie{Name: "SID", Value: sid, Path: "/"})
+		_, _ = io.WriteString(w, "Ok.")
+	})
+	authed := func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			cookie, err := r.Cookie("SID")
+			f.mu.Lock()
+			ok := err == nil && f.sessions[cookie.Value]
+			f.mu.Unlock()
+			if !ok {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = io.WriteString(w, "Forbidden")
+				return
+			}
+			h(w, r)
+		}
+	}
+	mux.HandleFunc("POST /api/v2/torrents/add", authed(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		fields := map[string]string{}
+		for k, v := range r.MultipartForm.Value {
+			fields[k] = v[0]
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if files := r.MultipartForm.File["torrents"]; len(files) > 0 {
+			file, err := files[0].Open()
+			require.NoError(t, err)
+			data, _ := io.ReadAll(file)
+			f.files = append(f.files, data)
+			fields["filename"] = files[0].Filename
+		}
+		_, duplicate := f.torrents[strings.TrimPrefix(fields["urls"], "magnet:?xt=urn:btih:")]
+		if fields["urls"] == "invalid" || duplicate {
+			_, _ = io.WriteString(w, "Fails.")
+			return
+		}
+		f.added = append(f.added, fields)
+		_, _ = io.WriteString(w, "Ok.")
+	}))
+	mux.HandleFunc("POST /api/v2/torrents/delete", authed(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.deleted = append(f.deleted, r.FormValue("hashes")+" "+r.FormValue("deleteFiles"))
+	}))
+	mux.HandleFunc("POST /api/v2/torrents/info", authed(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		torrents := []any{}
+		if torrent, ok := f.torrents[r.FormValue("hashes")]; ok {
+			torrents = append(torrents, torrent)
+		}
+		_ = json.NewEncoder(w).Encode(torrents)
+	}))
+	mux.HandleFunc("GET /api/v2/app/version", authed(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "v4.6.7")
+	}))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+// expire drops every session, as a qBittorrent restart does
+func (f *fakeQBittorrent) expire() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sessions = map[string]bool{}
+}
+
+func newTestQBittorrent(t *testing.T, url, password string) Client {
+	c, err := New(torrentsv1alpha1.DownloadClientQBittorrent, Config{URL: url, Username: "admin", Password: password})
+	require.NoError(t, err)
+	return c
+}
+
+func TestQBittorrentAddMagnet(t *testing.T) {
+	f, server := newFakeQBittorrent(t)
+	c := newTestQBittorrent(t, server.URL+"/", "secret")
+
+	hash, err := c.Add(context.Background(), Torrent{
+		Name:     "The.Show.S01E02.1080p.WEB-DL-NTb",
+		Magnet:   "magnet:?xt=urn:btih:ABCDEF",
+		InfoHash: "ABCDEF",
+		Category: "tv",
+		SavePath: "/downloads/tv",
+		Tags:     []string{"k8s-arr", "upgrade"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "abcdef", hash)
+	assert.Equal(t, []map[string]string{{
+		"urls":     "magnet:?xt=urn:btih:ABCDEF",
+		"category": "tv",
+		"savepath": "/downloads/tv",
+		"tags":     "k8s-arr,upgrade",
+	}}, f.added)
+
+	// The session is reused
+	_, err = c.Add(context.Background(), Torrent{Name: "other", Magnet: "magnet:?xt=urn:btih:012345", InfoHash: "012345"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.logins)
+}
+
+func TestQBittorrentAddFile(t *testing.T) {
+	f, server := newFakeQBittorrent(t)
+	c := newTestQBittorrent(t, server.URL, "secret")
+
+	_, err := c.Add(context.Background(), Torrent{Name: "Some.Movie.2020", File: []byte("d4:infod4:name1:xee"), InfoHash: "abc"})
+	require.NoError(t, err)
+	require.Len(t, f.added, 1)
+	assert.Equal(t, "Some.Movie.2020.torrent", f.added[0]["filename"])
+	assert.NotContains(t, f.added[0], "urls")
+	assert.Equal(t, [][]byte{[]byte("d4:infod4:name1:xee")}, f.files)
+}
+
+func TestQBittorrentAddRefused(t *testing.T) {
+	_, server := newFakeQBittorrent(t)
+	c := newTestQBittorrent(t, server.URL, "secret")
+
+	_, err := c.Add(context.Background(), Torrent{Name: "bad", Magnet: "invalid", InfoHash: "abc"})
+	assert.ErrorContains(t, err, "refused")
+	_, err = c.Add(context.Background(), Torrent{Name: "no hash", Magnet: "magnet:?dn=x"})
+	assert.ErrorContains(t, err, "no info hash")
+}
+
+func TestQBittorrentAddExisting(t *testing.T) {
+	f, server := newFakeQBittorrent(t)
+	c := newTestQBittorrent(t, server.URL, "secret")
+	f.torrents["abcdef"] = map[string]any{"hash": "abcdef", "state": "downloading"}
+
+	hash, err := c.Add(context.Background(), Torrent{Name: "again", Magnet: "magnet:?xt=urn:btih:abcdef", InfoHash: "ABCDEF"})
+	require.NoError(t, err)
+	assert.Equal(t, "abcdef", hash)
+	assert.Empty(t, f.added)
+}
+
+func TestQBittorrentGet(t *testing.T) {
+	f, server := newFakeQBittorrent(t)
+	c := newTestQBittorrent(t, server.URL, "secret")
+	f.torrents["abcdef"] = map[string]any{
+		"hash": "abcdef", "name": "Some.Movie", "state": "downloading", "progress": 0.5,
+		"dlspeed": 4096, "upspeed": 1024, "eta": 120, "ratio": 0.2,
+		"save_path": "/downloads/", "completion_on": -1, "num_seeds": 7,
+	}
+	f.torrents["done"] = map[string]any{"hash": "done", "state": "stoppedUP", "progress": 1, "eta": qbInfiniteETA, "completion_on": 1736542800}
+	f.torrents["broken"] = map[string]any{"hash": "broken", "state": "missingFiles"}
+
+	status, err := c.Get(context.Background(), "abcdef")
+	require.NoError(t, err)
+	assert.Equal(t, &Status{
+		Hash: "abcdef", Name: "Some.Movie", State: StateDownloading, Progress: 0.5,
+		DownloadRate: 4096, UploadRate: 1024, ETA: 2 * time.Minute, Ratio: 0.2,
+		SavePath: "/downloads/", Seeders: 7,
+	}, status)
+
+	status, err = c.Get(context.Background(), "done")
+	require.NoError(t, err)
+	assert.Equal(t, StatePaused, status.State)
+	assert.Zero(t, status.ETA)
+	assert.Equal(t, time.Unix(1736542800, 0), status.CompletedAt)
+
+	status, err = c.Get(context.Background(), "broken")
+	require.NoError(t, err)
+	assert.Equal(t, StateError, status.State)
+	assert.Equal(t, "missingFiles", status.Error)
+
+	_, err = c.Get(context.Background(), "unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestQBittorrentVersion(t *testing.T) {
+	_, server := newFakeQBittorrent(t)
+	c := newTestQBittorrent(t, server.URL, "secret")
+
+	version, err := c.Version(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "v4.6.7", version)
+}
+
+func TestQBittorrentRemove(t *testing.T) {
+	f, server := newFakeQBittorrent(t)
+	c := newTestQBittorrent(t, server.URL, "secret")
+
+	require.NoError(t, c.Remove(context.Background(), "abcdef", true))
+	assert.Equal(t, []string{"abcdef true"}, f.deleted)
+}
+
+func TestNewUnsupportedType(t *testing.T) {
+	_, err := New("utorrent", Config{URL: "http://localhost"})
+	assert.Error(t, err)
+}
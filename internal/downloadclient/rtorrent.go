@@ -0,0 +1,249 @@
+package downloadclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// rtorrentRPCPath is where web servers in front of rTorrent usually serve its
+// XML-RPC API
+const rtorrentRPCPath = "/RPC2"
+
+// rtorrentFields are the commands a Status is built from, in the order of
+// the values of a row
+var rtorrentFields = []string{
+	"d.hash", "d.name", "d.state", "d.is_active", "d.complete", "d.hashing",
+	"d.completed_bytes", "d.size_bytes", "d.down.rate", "d.up.rate", "d.ratio",
+	"d.directory", "d.timestamp.finished", "d.peers_complete", "d.message",
+}
+
+// RTorrent talks to the XML-RPC API of rTorrent, as served over HTTP by
+// ruTorrent or a web server in front of its SCGI socket
+type RTorrent struct {
+	url        string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// NewRTorrent returns a client for the rTorrent XML-RPC API at cfg.URL, at
+// /RPC2 when the URL has no path. The credentials are those of the web
+// server, sent with basic authentication.
+func NewRTorrent(cfg Config) *RTorrent {
+	rpcURL := strings.TrimRight(cfg.URL, "/")
+	if u, err := url.Parse(rpcURL); err == nil && u.Path == "" {
+		rpcURL += rtorrentRPCPath
+	}
+	return &RTorrent{
+		url:        rpcURL,
+		username:   cfg.Username,
+		password:   cfg.Password,
+		httpClient: cfg.HTTPClient,
+	}
+}
+
+// Add loads and starts the magnet, or the .torrent file. The category is set
+// as the ruTorrent label, rTorrent has no tags. rTorrent does not answer with
+// the hash, the info hash is returned.
+func (c *RTorrent) Add(ctx context.Context, t Torrent) (string, error) {
+	if t.InfoHash == "" {
+		return "", fmt.Errorf("torrent %q has no info hash", t.Name)
+	}
+	// The first parameter is the target, none for load commands
+	params := []any{""}
+	method := "load.start"
+	if len(t.File) > 0 {
+		method = "load.raw_start"
+		params = append(params, t.File)
+	} else {
+		params = append(params, t.Magnet)
+	}
+	if t.SavePath != "" {
+		params = append(params, "d.directory.set="+rtorrentQuote(t.SavePath))
+	}
+	if t.Category != "" {
+		params = append(params, "d.custom1.set="+rtorrentQuote(t.Category))
+	}
+	if _, err := c.call(ctx, method, params...); err != nil {
+		return "", err
+	}
+	return strings.ToLower(t.InfoHash), nil
+}
+
+// rtorrentQuote quotes a command argument
+func rtorrentQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Get returns the state of the torrent, its fields queried in a single
+// system.multicall targeting its hash
+func (c *RTorrent) Get(ctx context.Context, hash string) (*Status, error) {
+	calls := make([]any, len(rtorrentFields))
+	for i, field := range rtorrentFields {
+		calls[i] = map[string]any{"methodName": field, "params": []any{rtorrentHash(hash)}}
+	}
+	result, err := c.call(ctx, "system.multicall", calls)
+	if err != nil {
+		return nil, err
+	}
+	results, _ := result.([]any)
+	if len(results) != len(rtorrentFields) {
+		return nil, fmt.Errorf("invalid rTorrent system.multicall response")
+	}
+	row := make([]any, len(results))
+	for i, r := range results {
+		// Each call answers its result in an array of one, or a fault
+		switch r := r.(type) {
+		case []any:
+			if len(r) == 1 {
+				row[i] = r[0]
+				continue
+			}
+		case map[string]any:
+			code, _ := r["faultCode"].(int64)
+			fault := &xmlrpcFault{Code: code, String: rtorrentString(r["faultString"])}
+			if unknownHash(fault) {
+				return nil, ErrNotFound
+			}
+			return nil, fmt.Errorf("rTorrent %s failed: %w", rtorrentFields[i], fault)
+		}
+		return nil, fmt.Errorf("invalid rTorrent %s response", rtorrentFields[i])
+	}
+	return rtorrentStatus(row), nil
+}
+
+// rtorrentHash is the hash as rTorrent lists it, in upper case
+func rtorrentHash(hash string) string {
+	return strings.ToUpper(hash)
+}
+
+// rtorrentStatus builds the Status of a row of rtorrentFields values
+func rtorrentStatus(row []any) *Status {
+	var (
+		state, active, complete, hashing = rtorrentInt(row[2]), rtorrentInt(row[3]), rtorrentInt(row[4]), rtorrentInt(row[5])
+		completed, size                  = rtorrentInt(row[6]), rtorrentInt(row[7])
+		message                          = rtorrentString(row[14])
+	)
+	status := &Status{
+		Hash:         strings.ToLower(rtorrentString(row[0])),
+		Name:         rtorrentString(row[1]),
+		DownloadRate: rtorrentInt(row[8]),
+		UploadRate:   rtorrentInt(row[9]),
+		// Ratios are in thousandths
+		Ratio:    float64(rtorrentInt(row[10])) / 1000,
+		SavePath: rtorrentString(row[11]),
+		Seeders:  int(rtorrentInt(row[13])),
+	}
+	if size > 0 {
+		status.Progress = float64(completed) / float64(size)
+	}
+	if finished := rtorrentInt(row[12]); finished > 0 {
+		status.CompletedAt = time.Unix(finished, 0)
+	}
+
+	switch {
+	case hashing != 0:
+		status.State = StateChecking
+	case state == 0 && message != "":
+		// rTorrent stops torrents it fails to download, with a message
+		status.State = StateError
+		status.Error = message
+	case state == 0 || active == 0:
+		status.State = StatePaused
+	case complete != 0:
+		status.State = StateSeeding
+	default:
+		status.State = StateDownloading
+	}
+	if status.State == StateDownloading && status.DownloadRate > 0 {
+		status.ETA = time.Duration((size-completed)/status.DownloadRate) * time.Second
+	}
+	return status
+}
+
+func rtorrentInt(v any) int64 {
+	i, _ := v.(int64)
+	return i
+}
+
+func rtorrentString(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+// Remove erases the torrent from rTorrent. rTorrent leaves the files behind
+// and cannot delete them, so removeData fails with ErrRemoveDataUnsupported.
+func (c *RTorrent) Remove(ctx context.Context, hash string, removeData bool) error {
+	if removeData {
+		return ErrRemoveDataUnsupported
+	}
+	if _, err := c.call(ctx, "d.erase", rtorrentHash(hash)); err != nil && !unknownHash(err) {
+		return err
+	}
+	return nil
+}
+
+// unknownHash reports whether err is the fault rTorrent answers for unknown
+// torrents
+func unknownHash(err error) bool {
+	var fault *xmlrpcFault
+	return errors.As(err, &fault) && strings.Contains(fault.String, "Could not find info-hash")
+}
+
+// Version returns the rTorrent version
+func (c *RTorrent) Version(ctx context.Context) (string, error) {
+	result, err := c.call(ctx, "system.client_version")
+	if err != nil {
+		return "", err
+	}
+	return rtorrentString(result), nil
+}
+
+// call invokes the XML-RPC method and returns its decoded result
+func (c *RTorrent) call(ctx context.Context, method string, params ...any) (any, error) {
+	body, err := encodeXMLRPC(method, params...)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text/xml")
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return nil, ErrUnauthorized
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, fmt.Errorf("rTorrent %s returned status %d", method, resp.StatusCode)
+	}
+
+	result, err := decodeXMLRPC(data)
+	var fault *xmlrpcFault
+	if errors.As(err, &fault) {
+		return nil, fmt.Errorf("rTorrent %s failed: %w", method, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid rTorrent %s response: %w", method, err)
+	}
+	return result, nil
+}
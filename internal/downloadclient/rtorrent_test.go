@@ -0,0 +1,175 @@
+package downloadclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+)
+
+// fakeRTorrent stands in for the XML-RPC API of rTorrent behind a web server
+type fakeRTorrent struct {
+	mu sync.Mutex
+	// calls records the params of each call by method
+	calls map[string][][]any
+	// torrents are the values of rtorrentFields of each torrent, by hash
+	torrents map[string][]any
+}
+
+func newFakeRTorrent(t *testing.T) (*fakeRTorrent, *httptest.Server) {
+	f := &fakeRTorrent{calls: map[string][][]any{}, torrents: map[string][]any{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /RPC2", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "admin" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var call struct {
+			MethodName string        `xml:"methodName"`
+			Params     []xmlrpcValue `xml:"params>param>value"`
+		}
+		require.NoError(t, xml.NewDecoder(r.Body).Decode(&call))
+		var params []any
+		for i := range call.Params {
+			v, err := call.Params[i].decode()
+			require.NoError(t, err)
+			params = append(params, v)
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.calls[call.MethodName] = append(f.calls[call.MethodName], params)
+
+		unknownHash := map[string]any{"faultCode": -501, "faultString": "Could not find info-hash."}
+		var result any = 0
+		switch call.MethodName {
+		case "system.client_version":
+			result = "0.9.8"
+		case "system.multicall":
+			var results []any
+			for _, c := range params[0].([]any) {
+				c := c.(map[string]any)
+				target := c["params"].([]any)[0].(string)
+				torrent, ok := f.torrents[target]
+				if !ok {
+					results = append(results, unknownHash)
+					continue
+				}
+				i := slices.Index(rtorrentFields, c["methodName"].(string))
+				results = append(results, []any{torrent[i]})
+			}
+			result = results
+		case "d.erase":
+			if params[0] == "UNKNOWN" {
+				var buf bytes.Buffer
+				require.NoError(t, encodeXMLRPCValue(&buf, unknownHash))
+				_, _ = fmt.Fprintf(w, `<?xml version="1.0"?><methodResponse><fault>%s</fault></methodResponse>`, buf.String())
+				return
+			}
+		}
+		var buf bytes.Buffer
+		require.NoError(t, encodeXMLRPCValue(&buf, result))
+		_, _ = fmt.Fprintf(w, `<?xml version="1.0"?><methodResponse><params><param>%s</param></params></methodResponse>`, buf.String())
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+func newTestRTorrent(t *testing.T, url string) Client {
+	c, err := New(torrentsv1alpha1.DownloadClientRTorrent, Config{URL: url, Username: "admin", Password: "secret"})
+	require.NoError(t, err)
+	return c
+}
+
+func TestRTorrentAdd(t *testing.T) {
+	f, server := newFakeRTorrent(t)
+	c := newTestRTorrent(t, server.URL)
+
+	hash, err := c.Add(context.Background(), Torrent{
+		Name:     "The.Show.S01E02.1080p.WEB-DL-NTb",
+		Magnet:   "magnet:?xt=urn:btih:ABCDEF",
+		InfoHash: "ABCDEF",
+		Category: "tv",
+		SavePath: `/downloads/"tv"`,
+		Tags:     []string{"k8s-arr"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "abcdef", hash)
+	assert.Equal(t, [][]any{{
+		"", "magnet:?xt=urn:btih:ABCDEF", `d.directory.set="/downloads/\"tv\""`, `d.custom1.set="tv"`,
+	}}, f.calls["load.start"])
+
+	_, err = c.Add(context.Background(), Torrent{Name: "Some.Movie.2020", File: []byte("d4:infodee"), InfoHash: "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{"", []byte("d4:infodee")}}, f.calls["load.raw_start"])
+
+	_, err = c.Add(context.Background(), Torrent{Name: "no hash", Magnet: "magnet:?dn=x"})
+	assert.ErrorContains(t, err, "no info hash")
+}
+
+func TestRTorrentGet(t *testing.T) {
+	f, server := newFakeRTorrent(t)
+	c := newTestRTorrent(t, server.URL+"/RPC2")
+	f.torrents["ABCDEF"] = []any{"ABCDEF", "Some.Movie", 1, 1, 0, 0, 250, 1000, 50, 10, 120, "/downloads/Some.Movie", 0, 2, ""}
+	f.torrents["DONE"] = []any{"DONE", "Other", 1, 1, 1, 0, 1000, 1000, 0, 30, 1500, "/downloads", 1736542800, 0, ""}
+	f.torrents["BROKEN"] = []any{"BROKEN", "Broken", 0, 0, 0, 0, 0, 1000, 0, 0, 0, "/downloads", 0, 0, "Storage error: No space left"}
+
+	status, err := c.Get(context.Background(), "abcdef")
+	require.NoError(t, err)
+	assert.Equal(t, &Status{
+		Hash: "abcdef", Name: "Some.Movie", State: StateDownloading, Progress: 0.25,
+		DownloadRate: 50, UploadRate: 10, ETA: 15 * time.Second, Ratio: 0.12,
+		SavePath: "/downloads/Some.Movie", Seeders: 2,
+	}, status)
+	// Only the torrent is queried, in a single call
+	require.Len(t, f.calls["system.multicall"], 1)
+	calls := f.calls["system.multicall"][0][0].([]any)
+	require.Len(t, calls, len(rtorrentFields))
+	assert.Equal(t, map[string]any{"methodName": "d.hash", "params": []any{"ABCDEF"}}, calls[0])
+
+	status, err = c.Get(context.Background(), "done")
+	require.NoError(t, err)
+	assert.Equal(t, StateSeeding, status.State)
+	assert.Equal(t, 1.5, status.Ratio)
+	assert.Equal(t, time.Unix(1736542800, 0), status.CompletedAt)
+
+	status, err = c.Get(context.Background(), "broken")
+	require.NoError(t, err)
+	assert.Equal(t, StateError, status.State)
+	assert.Equal(t, "Storage error: No space left", status.Error)
+
+	_, err = c.Get(context.Background(), "unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestRTorrentRemove(t *testing.T) {
+	f, server := newFakeRTorrent(t)
+	c := newTestRTorrent(t, server.URL)
+
+	require.NoError(t, c.Remove(context.Background(), "abcdef", false))
+	require.NoError(t, c.Remove(context.Background(), "unknown", false))
+	assert.Equal(t, [][]any{{"ABCDEF"}, {"UNKNOWN"}}, f.calls["d.erase"])
+
+	// The files are never deleted, nor the torrent erased without them
+	assert.ErrorIs(t, c.Remove(context.Background(), "other", true), ErrRemoveDataUnsupported)
+	assert.Len(t, f.calls["d.erase"], 2)
+}
+
+func TestRTorrentVersion(t *testing.T) {
+	_, server := newFakeRTorrent(t)
+
+	version, err := newTestRTorrent(t, server.URL).Version(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "0.9.8", version)
+}
@@ -0,0 +1,249 @@
+package downloadclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// transmissionRPCPath is where Transmission serves its RPC API by default
+	transmissionRPCPath = "/transmission/rpc"
+	// transmissionSessionHeader carries the CSRF token of the RPC API
+	transmissionSessionHeader = "X-Transmission-Session-Id"
+)
+
+// transmissionFields are the torrent-get fields a Status is built from
+var transmissionFields = []string{
+	"hashString", "name", "status", "percentDone", "rateDownload", "rateUpload",
+	"eta", "uploadRatio", "downloadDir", "doneDate", "peersSendingToUs", "error", "errorString",
+}
+
+// Transmission talks to the Transmission RPC API
+type Transmission struct {
+	url        string
+	username   string
+	password   string
+	httpClient *http.Client
+
+	mu sync.Mutex
+	// sessionID is the CSRF token Transmission last handed out
+	sessionID string
+}
+
+// NewTransmission returns a client for the Transmission RPC API at cfg.URL,
+// at /transmission/rpc when the URL has no path
+func NewTransmission(cfg Config) *Transmission {
+	rpcURL := strings.TrimRight(cfg.URL, "/")
+	if u, err := url.Parse(rpcURL); err == nil && u.Path == "" {
+		rpcURL += transmissionRPCPath
+	}
+	return &Transmission{
+		url:        rpcURL,
+		username:   cfg.Username,
+		password:   cfg.Password,
+		httpClient: cfg.HTTPClient,
+	}
+}
+
+// transmissionTorrent is a torrent as returned by torrent-get and torrent-add
+type transmissionTorrent struct {
+	HashString       string  `json:"hashString"`
+	Name             string  `json:"name"`
+	Status           int     `json:"status"`
+	PercentDone      float64 `json:"percentDone"`
+	RateDownload     int64   `json:"rateDownload"`
+	RateUpload       int64   `json:"rateUpload"`
+	ETA              int64   `json:"eta"`
+	UploadRatio      float64 `json:"uploadRatio"`
+	DownloadDir      string  `json:"downloadDir"`
+	DoneDate         int64   `json:"doneDate"`
+	PeersSendingToUs int     `json:"peersSendingToUs"`
+	Error            int     `json:"error"`
+	ErrorString      string  `json:"errorString"`
+}
+
+// Add submits the magnet, or the .torrent file as metainfo. The category is
+// added to the labels, Transmission has no categories.
+func (c *Transmission) Add(ctx context.Context, t Torrent) (string, error) {
+	args := map[string]any{}
+	if len(t.File) > 0 {
+		args["metainfo"] = base64.StdEncoding.EncodeToString(t.File)
+	} else {
+		args["filename"] = t.Magnet
+	}
+	if t.SavePath != "" {
+		args["download-dir"] = t.SavePath
+	}
+	var labels []string
+	if t.Category != "" {
+		labels = append(labels, t.Category)
+	}
+	if labels = append(labels, t.Tags...); len(labels) > 0 {
+		args["labels"] = labels
+	}
+
+	var added struct {
+		Added     *transmissionTorrent `json:"torrent-added"`
+		Duplicate *transmissionTorrent `json:"torrent-duplicate"`
+	}
+	if err := c.call(ctx, "torrent-add", args, &added); err != nil {
+		return "", err
+	}
+	switch {
+	case added.Added != nil:
+		return strings.ToLower(added.Added.HashString), nil
+	case added.Duplicate != nil:
+		// Already downloading, e.g. added by hand
+		return strings.ToLower(added.Duplicate.HashString), nil
+	default:
+		return "", fmt.Errorf("transmission did not add torrent %q", t.Name)
+	}
+}
+
+// Get returns the state of the torrent from torrent-get
+func (c *Transmission) Get(ctx context.Context, hash string) (*Status, error) {
+	var got struct {
+		Torrents []transmissionTorrent `json:"torrents"`
+	}
+	if err := c.call(ctx, "torrent-get", map[string]any{"ids": []string{hash}, "fields": transmissionFields}, &got); err != nil {
+		return nil, err
+	}
+	if len(got.Torrents) == 0 {
+		return nil, ErrNotFound
+	}
+
+	t := got.Torrents[0]
+	status := &Status{
+		Hash:         strings.ToLower(t.HashString),
+		Name:         t.Name,
+		State:        transmissionState(t.Status),
+		Progress:     t.PercentDone,
+		DownloadRate: t.RateDownload,
+		UploadRate:   t.RateUpload,
+		Ratio:        max(t.UploadRatio, 0),
+		SavePath:     t.DownloadDir,
+		Seeders:      t.PeersSendingToUs,
+	}
+	// Negative ETAs stand for unknown
+	if t.ETA > 0 {
+		status.ETA = time.Duration(t.ETA) * time.Second
+	}
+	if t.DoneDate > 0 {
+		status.CompletedAt = time.Unix(t.DoneDate, 0)
+	}
+	// Tracker warnings (1) leave the torrent working, errors do not
+	if t.Error >= 2 {
+		status.State = StateError
+		status.Error = t.ErrorString
+	}
+	return status, nil
+}
+
+// transmissionState maps the tr_torrent_activity of torrent-get
+func transmissionState(status int) State {
+	switch status {
+	case 0:
+		return StatePaused
+	case 1, 2:
+		return StateChecking
+	case 3:
+		return StateQueued
+	case 4:
+		return StateDownloading
+	default:
+		// Seeding (6), or queued to seed (5)
+		return StateSeeding
+	}
+}
+
+// Remove deletes the torrent from Transmission
+func (c *Transmission) Remove(ctx context.Context, hash string, removeData bool) error {
+	return c.call(ctx, "torrent-remove", map[string]any{"ids": []string{hash}, "delete-local-data": removeData}, nil)
+}
+
+// Version returns the Transmission version from session-get
+func (c *Transmission) Version(ctx context.Context) (string, error) {
+	var session struct {
+		Version string `json:"version"`
+	}
+	if err := c.call(ctx, "session-get", map[string]any{"fields": []string{"version"}}, &session); err != nil {
+		return "", err
+	}
+	return session.Version, nil
+}
+
+// call invokes the RPC method and decodes its arguments into out. A request
+// answered 409 is sent again with the session id Transmission hands out with
+// that answer.
+func (c *Transmission) call(ctx context.Context, method string, args any, out any) error {
+	body, err := json.Marshal(map[string]any{"method": method, "arguments": args})
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		c.mu.Lock()
+		if c.sessionID != "" {
+			req.Header.Set(transmissionSessionHeader, c.sessionID)
+		}
+		c.mu.Unlock()
+		if c.username != "" {
+			req.SetBasicAuth(c.username, c.password)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusConflict:
+			c.mu.Lock()
+			c.sessionID = resp.Header.Get(transmissionSessionHeader)
+			c.mu.Unlock()
+			continue
+		case resp.StatusCode == http.StatusUnauthorized:
+			return ErrUnauthorized
+		case resp.StatusCode < 200 || resp.StatusCode >= 300:
+			return fmt.Errorf("transmission %s returned status %d", method, resp.StatusCode)
+		}
+
+		var rpc struct {
+			Result    string          `json:"result"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(data, &rpc); err != nil {
+			return fmt.Errorf("invalid transmission %s response: %w", method, err)
+		}
+		if rpc.Result != "success" {
+			return fmt.Errorf("transmission %s failed: %s", method, rpc.Result)
+		}
+		if out == nil || len(rpc.Arguments) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(rpc.Arguments, out); err != nil {
+			return fmt.Errorf("invalid transmission %s response: %w", method, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("transmission %s kept rejecting the session id", method)
+}
@@ -0,0 +1,179 @@
+package downloadclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+)
+
+// fakeTransmission stands in for the Transmission RPC API
+type fakeTransmission struct {
+	mu        sync.Mutex
+	sessionID string
+	// conflicts counts the requests answered 409
+	conflicts int
+	// calls records the arguments of each call by method
+	calls    map[string][]map[string]any
+	torrents map[string]map[string]any
+}
+
+func newFakeTransmission(t *testing.T) (*fakeTransmission, *httptest.Server) {
+	f := &fakeTransmission{sessionID: "token-1", calls: map[string][]map[string]any{}, torrents: map[string]map[string]any{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /transmission/rpc", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if user, pass, _ := r.BasicAuth(); user != "admin" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get(transmissionSessionHeader) != f.sessionID {
+			f.conflicts++
+			w.Header().Set(transmissionSessionHeader, f.sessionID)
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		var req struct {
+			Method    string         `json:"method"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		f.calls[req.Method] = append(f.calls[req.Method], req.Arguments)
+
+		args := map[string]any{}
+		switch req.Method {
+		case "session-get":
+			args["version"] = "4.0.5 (a6fe2a64aa)"
+		case "torrent-add":
+			if req.Arguments["filename"] == "magnet:?xt=urn:btih:DUPLICATE" {
+				args["torrent-duplicate"] = map[string]any{"hashString": "DDDD", "id": 1}
+			} else {
+				args["torrent-added"] = map[string]any{"hashString": "ABCDEF", "id": 2}
+			}
+		case "torrent-get":
+			var torrents []any
+			for _, id := range req.Arguments["ids"].([]any) {
+				if torrent, ok := f.torrents[id.(string)]; ok {
+					torrents = append(torrents, torrent)
+				}
+			}
+			args["torrents"] = torrents
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": "success", "arguments": args})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+// expire renews the session id, as a Transmission restart does
+func (f *fakeTransmission) expire() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sessionID = "token-2"
+}
+
+func newTestTransmission(t *testing.T, url string) Client {
+	c, err := New(torrentsv1alpha1.DownloadClientTransmission, Config{URL: url, Username: "admin", Password: "secret"})
+	require.NoError(t, err)
+	return c
+}
+
+func TestTransmissionSessionHandshake(t *testing.T) {
+	f, server := newFakeTransmission(t)
+	c := newTestTransmission(t, server.URL)
+
+	version, err := c.Version(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "4.0.5 (a6fe2a64aa)", version)
+	assert.Equal(t, 1, f.conflicts)
+
+	// The session id is kept for the next requests
+	_, err = c.Version(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.conflicts)
+}
+
+func TestTransmissionAdd(t *testing.T) {
+	f, server := newFakeTransmission(t)
+	c := newTestTransmission(t, server.URL+"/transmission/rpc")
+
+	hash, err := c.Add(context.Background(), Torrent{
+		Name:     "The.Show.S01E02.1080p.WEB-DL-NTb",
+		Magnet:   "magnet:?xt=urn:btih:ABCDEF",
+		InfoHash: "abcdef",
+		Category: "tv",
+		SavePath: "/downloads/tv",
+		Tags:     []string{"k8s-arr"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "abcdef", hash)
+
+	hash, err = c.Add(context.Background(), Torrent{Name: "dup", Magnet: "magnet:?xt=urn:btih:DUPLICATE", InfoHash: "dddd"})
+	require.NoError(t, err)
+	assert.Equal(t, "dddd", hash)
+
+	_, err = c.Add(context.Background(), Torrent{Name: "file", File: []byte("d4:infodee"), InfoHash: "abc"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []map[string]any{
+		{"filename": "magnet:?xt=urn:btih:ABCDEF", "download-dir": "/downloads/tv", "labels": []any{"tv", "k8s-arr"}},
+		{"filename": "magnet:?xt=urn:btih:DUPLICATE"},
+		{"metainfo": "ZDQ6aW5mb2RlZQ=="},
+	}, f.calls["torrent-add"])
+}
+
+func TestTransmissionGet(t *testing.T) {
+	f, server := newFakeTransmission(t)
+	c := newTestTransmission(t, server.URL)
+	f.torrents["abcdef"] = map[string]any{
+		"hashString": "ABCDEF", "name": "Some.Movie", "status": 4, "percentDone": 0.25,
+		"rateDownload": 2048, "rateUpload": 512, "eta": 600, "uploadRatio": 0.1,
+		"downloadDir": "/downloads", "doneDate": 0, "peersSendingToUs": 3,
+	}
+	f.torrents["done"] = map[string]any{
+		"hashString": "done", "status": 6, "percentDone": 1.0, "eta": -1, "uploadRatio": 1.5, "doneDate": 1736542800,
+	}
+	f.torrents["broken"] = map[string]any{"hashString": "broken", "status": 0, "error": 3, "errorString": "No data found"}
+
+	status, err := c.Get(context.Background(), "abcdef")
+	require.NoError(t, err)
+	assert.Equal(t, &Status{
+		Hash: "abcdef", Name: "Some.Movie", State: StateDownloading, Progress: 0.25,
+		DownloadRate: 2048, UploadRate: 512, ETA: 10 * time.Minute, Ratio: 0.1,
+		SavePath: "/downloads", Seeders: 3,
+	}, status)
+	assert.Equal(t, []any{"abcdef"}, f.calls["torrent-get"][0]["ids"])
+
+	status, err = c.Get(context.Background(), "done")
+	require.NoError(t, err)
+	assert.Equal(t, StateSeeding, status.State)
+	assert.Zero(t, status.ETA)
+	assert.Equal(t, time.Unix(1736542800, 0), status.CompletedAt)
+
+	status, err = c.Get(context.Background(), "broken")
+	require.NoError(t, err)
+	assert.Equal(t, StateError, status.State)
+	assert.Equal(t, "No data found", status.Error)
+
+	_, err = c.Get(context.Background(), "unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestTransmissionRemove(t *testing.T) {
+	f, server := newFakeTransmission(t)
+	c := newTestTransmission(t, server.URL)
+
+	require.NoError(t, c.Remove(context.Background(), "abcdef", true))
+	assert.Equal(t, []map[string]any{{"ids": []any{"abcdef"}, "delete-local-data": true}}, f.calls["torrent-remove"])
+}
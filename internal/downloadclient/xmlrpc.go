@@ -0,0 +1,185 @@
+package downloadclient
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// xmlrpcFault is the fault an XML-RPC server answers a failed call with
+type xmlrpcFault struct {
+	Code   int64
+	String string
+}
+
+func (f *xmlrpcFault) Error() string {
+	return fmt.Sprintf("fault %d: %s", f.Code, f.String)
+}
+
+// encodeXMLRPC builds the methodCall of method. Parameters are strings,
+// integers, booleans, []byte sent as base64, or []any arrays and
+// map[string]any structs of those.
+func encodeXMLRPC(method string, params ...any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString("<methodCall><methodName>")
+	if err := xml.EscapeText(&buf, []byte(method)); err != nil {
+		return nil, err
+	}
+	buf.WriteString("</methodName><params>")
+	for _, p := range params {
+		buf.WriteString("<param>")
+		if err := encodeXMLRPCValue(&buf, p); err != nil {
+			return nil, err
+		}
+		buf.WriteString("</param>")
+	}
+	buf.WriteString("</params></methodCall>")
+	return buf.Bytes(), nil
+}
+
+func encodeXMLRPCValue(buf *bytes.Buffer, v any) error {
+	buf.WriteString("<value>")
+	switch v := v.(type) {
+	case string:
+		buf.WriteString("<string>")
+		if err := xml.EscapeText(buf, []byte(v)); err != nil {
+			return err
+		}
+		buf.WriteString("</string>")
+	case int:
+		fmt.Fprintf(buf, "<i8>%d</i8>", v)
+	case int64:
+		fmt.Fprintf(buf, "<i8>%d</i8>", v)
+	case bool:
+		b := 0
+		if v {
+			b = 1
+		}
+		fmt.Fprintf(buf, "<boolean>%d</boolean>", b)
+	case []byte:
+		fmt.Fprintf(buf, "<base64>%s</base64>", base64.StdEncoding.EncodeToString(v))
+	case []any:
+		buf.WriteString("<array><data>")
+		for _, e := range v {
+			if err := encodeXMLRPCValue(buf, e); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("</data></array>")
+	case map[string]any:
+		buf.WriteString("<struct>")
+		// Members are sorted, for calls to be reproducible
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			buf.WriteString("<member><name>")
+			if err := xml.EscapeText(buf, []byte(name)); err != nil {
+				return err
+			}
+			buf.WriteString("</name>")
+			if err := encodeXMLRPCValue(buf, v[name]); err != nil {
+				return err
+			}
+			buf.WriteString("</member>")
+		}
+		buf.WriteString("</struct>")
+	default:
+		return fmt.Errorf("unsupported XML-RPC parameter type %T", v)
+	}
+	buf.WriteString("</value>")
+	return nil
+}
+
+// xmlrpcValue is a decoded <value>, one of its typed members being set
+type xmlrpcValue struct {
+	String  *string `xml:"string"`
+	Int     *string `xml:"int"`
+	I4      *string `xml:"i4"`
+	I8      *string `xml:"i8"`
+	Boolean *string `xml:"boolean"`
+	Double  *string `xml:"double"`
+	Base64  *string `xml:"base64"`
+	Array   *struct {
+		Values []xmlrpcValue `xml:"data>value"`
+	} `xml:"array"`
+	Struct *struct {
+		Members []struct {
+			Name  string      `xml:"name"`
+			Value xmlrpcValue `xml:"value"`
+		} `xml:"member"`
+	} `xml:"struct"`
+	// Text is the value of untyped values, which are strings
+	Text string `xml:",chardata"`
+}
+
+// decodeXMLRPC returns the result of a methodResponse as a string, int64,
+// bool, float64, []byte, []any or map[string]any, or its fault
+func decodeXMLRPC(data []byte) (any, error) {
+	var resp struct {
+		Params []xmlrpcValue `xml:"params>param>value"`
+		Fault  *xmlrpcValue  `xml:"fault>value"`
+	}
+	if err := xml.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Fault != nil {
+		v, err := resp.Fault.decode()
+		if err != nil {
+			return nil, err
+		}
+		members, _ := v.(map[string]any)
+		code, _ := members["faultCode"].(int64)
+		str, _ := members["faultString"].(string)
+		return nil, &xmlrpcFault{Code: code, String: str}
+	}
+	if len(resp.Params) == 0 {
+		return nil, fmt.Errorf("XML-RPC response has no result")
+	}
+	return resp.Params[0].decode()
+}
+
+func (v *xmlrpcValue) decode() (any, error) {
+	switch {
+	case v.String != nil:
+		return *v.String, nil
+	case v.Int != nil:
+		return strconv.ParseInt(strings.TrimSpace(*v.Int), 10, 64)
+	case v.I4 != nil:
+		return strconv.ParseInt(strings.TrimSpace(*v.I4), 10, 64)
+	case v.I8 != nil:
+		return strconv.ParseInt(strings.TrimSpace(*v.I8), 10, 64)
+	case v.Boolean != nil:
+		return strings.TrimSpace(*v.Boolean) == "1", nil
+	case v.Double != nil:
+		return strconv.ParseFloat(strings.TrimSpace(*v.Double), 64)
+	case v.Base64 != nil:
+		return base64.StdEncoding.DecodeString(strings.TrimSpace(*v.Base64))
+	case v.Array != nil:
+		values := make([]any, 0, len(v.Array.Values))
+		for i := range v.Array.Values {
+			e, err := v.Array.Values[i].decode()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, e)
+		}
+		return values, nil
+	case v.Struct != nil:
+		members := make(map[string]any, len(v.Struct.Members))
+		for i := range v.Struct.Members {
+			m := &v.Struct.Members[i]
+			e, err := m.Value.decode()
+			if err != nil {
+				return nil, err
+			}
+			members[m.Name] = e
+		}
+		return members, nil
+	default:
+		return v.Text, nil
+	}
+}
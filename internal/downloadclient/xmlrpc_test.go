@@ -0,0 +1,59 @@
+package downloadclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeXMLRPC(t *testing.T) {
+	data, err := encodeXMLRPC("load.start", "", "a<b", 42, true, []byte("hi"), []any{"x", 1}, map[string]any{"b": 2, "a": "y"})
+	require.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<methodCall><methodName>load.start</methodName><params>`+
+		`<param><value><string></string></value></param>`+
+		`<param><value><string>a&lt;b</string></value></param>`+
+		`<param><value><i8>42</i8></value></param>`+
+		`<param><value><boolean>1</boolean></value></param>`+
+		`<param><value><base64>aGk=</base64></value></param>`+
+		`<param><value><array><data><value><string>x</string></value><value><i8>1</i8></value></data></array></value></param>`+
+		`<param><value><struct><member><name>a</name><value><string>y</string></value></member>`+
+		`<member><name>b</name><value><i8>2</i8></value></member></struct></value></param>`+
+		`</params></methodCall>`, string(data))
+
+	_, err = encodeXMLRPC("d.erase", 1.5)
+	assert.Error(t, err)
+}
+
+func TestDecodeXMLRPC(t *testing.T) {
+	for _, tt := range []struct {
+		value string
+		want  any
+	}{
+		{"<string>a&amp;b</string>", "a&b"},
+		{"untyped", "untyped"},
+		{"<i4> 7 </i4>", int64(7)},
+		{"<int>-1</int>", int64(-1)},
+		{"<i8>1736542800</i8>", int64(1736542800)},
+		{"<boolean>1</boolean>", true},
+		{"<double>0.5</double>", 0.5},
+		{"<base64>aGk=</base64>", []byte("hi")},
+		{"<array><data></data></array>", []any{}},
+		{"<array><data><value><i8>1</i8></value><value>x</value></data></array>", []any{int64(1), "x"}},
+		{"<struct><member><name>a</name><value><i4>1</i4></value></member></struct>", map[string]any{"a": int64(1)}},
+	} {
+		got, err := decodeXMLRPC([]byte(`<?xml version="1.0"?><methodResponse><params><param><value>` + tt.value + `</value></param></params></methodResponse>`))
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.want, got, tt.value)
+	}
+
+	_, err := decodeXMLRPC([]byte(`<methodResponse><fault><value><struct>` +
+		`<member><name>faultCode</name><value><i4>-506</i4></value></member>` +
+		`<member><name>faultString</name><value><string>Method 'x' not defined</string></value></member>` +
+		`</struct></value></fault></methodResponse>`))
+	assert.Equal(t, &xmlrpcFault{Code: -506, String: "Method 'x' not defined"}, err)
+
+	_, err = decodeXMLRPC([]byte("<html>Bad Gateway</html>"))
+	assert.Error(t, err)
+}
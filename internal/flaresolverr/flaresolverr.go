@@ -0,0 +1,195 @@
+package flaresolverr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultMaxTimeout is how long FlareSolverr may spend solving a challenge
+const DefaultMaxTimeout = 60 * time.Second
+
+// Client talks to the FlareSolverr v1 API
+type Client struct {
+	// URL of the FlareSolverr service, e.g. http://flaresolverr:8191
+	URL string
+	// HTTPClient used to reach FlareSolverr itself
+	HTTPClient *http.Client
+	// MaxTimeout bounds each request.get/request.post call
+	MaxTimeout time.Duration
+}
+
+// NewClient returns a client for the FlareSolverr service at baseURL
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		URL:        baseURL,
+		HTTPClient: httpClient,
+		MaxTimeout: DefaultMaxTimeout,
+	}
+}
+
+// Proxy makes FlareSolverr's browser go through the given proxy
+type Proxy struct {
+	URL      string `json:"url"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// NewProxy converts a proxy URL, with optional credentials, to the API form
+func NewProxy(proxy *url.URL) *Proxy {
+	if proxy == nil {
+		return nil
+	}
+	p := &Proxy{
+		URL: (&url.URL{Scheme: proxy.Scheme, Host: proxy.Host}).String(),
+	}
+	if proxy.User != nil {
+		p.Username = proxy.User.Username()
+		p.Password, _ = proxy.User.Password()
+	}
+	return p
+}
+
+type request struct {
+	Cmd        string `json:"cmd"`
+	URL        string `json:"url,omitempty"`
+	Session    string `json:"session,omitempty"`
+	MaxTimeout int    `json:"maxTimeout,omitempty"`
+	PostData   string `json:"postData,omitempty"`
+	Proxy      *Proxy `json:"proxy,omitempty"`
+}
+
+type response struct {
+	Status   string   `json:"status"`
+	Message  string   `json:"message"`
+	Session  string   `json:"session,omitempty"`
+	Solution Solution `json:"solution"`
+}
+
+// Solution is the outcome of a solved request
+type Solution struct {
+	URL       string   `json:"url"`
+	Status    int      `json:"status"`
+	Response  string   `json:"response"`
+	Cookies   []Cookie `json:"cookies"`
+	UserAgent string   `json:"userAgent"`
+}
+
+// Cookie is a browser cookie returned by FlareSolverr
+type Cookie struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	Domain   string  `json:"domain"`
+	Path     string  `json:"path"`
+	Expires  float64 `json:"expires"`
+	HTTPOnly bool    `json:"httpOnly"`
+	Secure   bool    `json:"secure"`
+}
+
+// HTTPCookie converts the cookie so it can be sent with net/http
+func (c Cookie) HTTPCookie() *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Domain:   c.Domain,
+		Path:     c.Path,
+		HttpOnly: c.HTTPOnly,
+		Secure:   c.Secure,
+	}
+	if c.Expires > 0 {
+		cookie.Expires = time.Unix(int64(c.Expires), 0)
+	}
+	return cookie
+}
+
+// Get fetches url through the browser, reusing session when not empty
+func (c *Client) Get(ctx context.Context, session, url string, proxy *Proxy) (*Solution, error) {
+	return c.solve(ctx, request{
+		Cmd:     "request.get",
+		URL:     url,
+		Session: session,
+		Proxy:   proxy,
+	})
+}
+
+// Post submits the url encoded postData to url through the browser
+func (c *Client) Post(ctx context.Context, session, url, postData string, proxy *Proxy) (*Solution, error) {
+	return c.solve(ctx, request{
+		Cmd:      "request.post",
+		URL:      url,
+		Session:  session,
+		PostData: postData,
+		Proxy:    proxy,
+	})
+}
+
+// CreateSession starts a persistent browser instance named id
+func (c *Client) CreateSession(ctx context.Context, id string, proxy *Proxy) error {
+	_, err := c.call(ctx, request{Cmd: "sessions.create", Session: id, Proxy: proxy})
+	return err
+}
+
+// DestroySession stops the browser instance named id
+func (c *Client) DestroySession(ctx context.Context, id string) error {
+	_, err := c.call(ctx, request{Cmd: "sessions.destroy", Session: id})
+	return err
+}
+
+// Ping checks FlareSolverr is up and answering API calls
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.call(ctx, request{Cmd: "sessions.list"})
+	return err
+}
+
+func (c *Client) solve(ctx context.Context, req request) (*Solution, error) {
+	maxTimeout := c.MaxTimeout
+	if maxTimeout <= 0 {
+		maxTimeout = DefaultMaxTimeout
+	}
+	req.MaxTimeout = int(maxTimeout / time.Millisecond)
+
+	resp, err := c.call(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Solution, nil
+}
+
+func (c *Client) call(ctx context.Context, reqBody request) (*response, error) {
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal FS request: %v", err)
+	}
+
+	fsURL := fmt.Sprintf("%s/v1", strings.TrimRight(c.URL, "/"))
+	req, err := http.NewRequestWithContext(ctx, "POST", fsURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create FS request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("FlareSolverr connection failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var fsResp response
+	if err := json.NewDecoder(resp.Body).Decode(&fsResp); err != nil {
+		return nil, fmt.Errorf("failed to decode FS response: %v", err)
+	}
+
+	if fsResp.Status != "ok" {
+		return nil, fmt.Errorf("FlareSolverr Status: %s, Msg: %s", fsResp.Status, fsResp.Message)
+	}
+
+	return &fsResp, nil
+}
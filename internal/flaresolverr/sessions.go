@@ -0,0 +1,289 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"vitoru.fun/torrents/internal/solver"
+)
+
+// ClearanceCookie is the cookie Cloudflare sets once a challenge is solved
+const ClearanceCookie = "cf_clearance"
+
+// Request is a page fetched on behalf of an indexer
+type Request = solver.Request
+
+// Sessions keeps one FlareSolverr browser session per indexer and caches the
+// clearance cookies and user agent it obtains, so requests can skip the
+// browser entirely until the cookies expire. It implements solver.Solver.
+type Sessions struct {
+	Client *Client
+	// MaxSessions bounds the browser sessions kept open at once, the least
+	// recently used one is closed to make room. Zero means unbounded.
+	MaxSessions int
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+type session struct {
+	id        string
+	proxy     string
+	cookies   []*http.Cookie
+	userAgent string
+	expires   time.Time
+	lastUsed  time.Time
+}
+
+var _ solver.Solver = &Sessions{}
+
+// NewSessions returns a session cache backed by client
+func NewSessions(client *Client) *Sessions {
+	return &Sessions{Client: client}
+}
+
+// Ping checks FlareSolverr is reachable
+func (s *Sessions) Ping(ctx context.Context) error {
+	return s.Client.Ping(ctx)
+}
+
+// DestroyAll closes every browser session
+func (s *Sessions) DestroyAll(ctx context.Context) error {
+	s.mu.Lock()
+	keys := make([]string, 0, len(s.sessions))
+	for key := range s.sessions {
+		keys = append(keys, key)
+	}
+	s.mu.Unlock()
+
+	var errs []error
+	for _, key := range keys {
+		errs = append(errs, s.Destroy(ctx, key))
+	}
+	return errors.Join(errs...)
+}
+
+// Do fetches the page for the indexer identified by key. When clearance
+// cookies from a previous solve are still valid, the request is sent directly
+// with httpClient; otherwise it goes through the indexer's browser session.
+func (s *Sessions) Do(ctx context.Context, key string, httpClient *http.Client, req Request) ([]byte, error) {
+	if body, ok, err := s.doDirect(ctx, key, httpClient, req); ok {
+		return body, err
+	}
+	return s.doSolve(ctx, key, req)
+}
+
+// Destroy closes the browser session of the indexer identified by key
+func (s *Sessions) Destroy(ctx context.Context, key string) error {
+	s.mu.Lock()
+	sess, ok := s.sessions[key]
+	delete(s.sessions, key)
+	s.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return s.Client.DestroySession(ctx, sess.id)
+}
+
+// doDirect sends the request without the browser using cached clearance.
+// ok is false when there is no usable clearance and the caller should solve.
+func (s *Sessions) doDirect(ctx context.Context, key string, httpClient *http.Client, req Request) ([]byte, bool, error) {
+	s.mu.Lock()
+	sess, found := s.sessions[key]
+	if !found || sess.userAgent == "" || time.Now().After(sess.expires) || sess.proxy != proxyKey(req.Proxy) {
+		s.mu.Unlock()
+		return nil, false, nil
+	}
+	cookies, userAgent := sess.cookies, sess.userAgent
+	s.mu.Unlock()
+
+	var body io.Reader
+	if req.Method == http.MethodPost {
+		body = strings.NewReader(req.PostData)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, methodOrGet(req.Method), req.URL, body)
+	if err != nil {
+		return nil, true, err
+	}
+	if req.Method == http.MethodPost {
+		httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	// Clearance is bound to the user agent that solved the challenge
+	httpReq.Header.Set("User-Agent", userAgent)
+	for _, c := range cookies {
+		if cookieDomainMatches(c, httpReq.URL.Hostname()) {
+			httpReq.AddCookie(c)
+		}
+	}
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, true, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusServiceUnavailable:
+		// Challenged again, the clearance is no longer accepted
+		s.forgetClearance(key)
+		return nil, false, nil
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, true, fmt.Errorf("HTTP Status: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	return data, true, err
+}
+
+// doSolve sends the request through the browser session of the indexer,
+// creating the session first when needed.
+func (s *Sessions) doSolve(ctx context.Context, key string, req Request) ([]byte, error) {
+	id, err := s.ensureSession(ctx, key, req.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := NewProxy(req.Proxy)
+	var solution *Solution
+	if req.Method == http.MethodPost {
+		solution, err = s.Client.Post(ctx, id, req.URL, req.PostData, proxy)
+	} else {
+		solution, err = s.Client.Get(ctx, id, req.URL, proxy)
+	}
+	if err != nil {
+		// The browser may be wedged, start from a fresh one next time
+		_ = s.Destroy(ctx, key)
+		return nil, err
+	}
+	if solution.Status >= 400 {
+		return nil, fmt.Errorf("HTTP Status: %d", solution.Status)
+	}
+
+	s.storeClearance(key, solution)
+	return []byte(solution.Response), nil
+}
+
+func (s *Sessions) ensureSession(ctx context.Context, key string, proxy *url.URL) (string, error) {
+	s.mu.Lock()
+	sess, found := s.sessions[key]
+	s.mu.Unlock()
+
+	if found && sess.proxy == proxyKey(proxy) {
+		s.mu.Lock()
+		sess.lastUsed = time.Now()
+		s.mu.Unlock()
+		return sess.id, nil
+	}
+	if found {
+		// Sessions are bound to the proxy they were created with
+		_ = s.Destroy(ctx, key)
+	}
+	if evict := s.leastRecentlyUsed(); evict != "" {
+		_ = s.Destroy(ctx, evict)
+	}
+
+	id := sessionID(key)
+	if err := s.Client.CreateSession(ctx, id, NewProxy(proxy)); err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sessions == nil {
+		s.sessions = make(map[string]*session)
+	}
+	s.sessions[key] = &session{id: id, proxy: proxyKey(proxy), lastUsed: time.Now()}
+	return id, nil
+}
+
+// leastRecentlyUsed returns the session to close before opening a new one,
+// or an empty key while there is room.
+func (s *Sessions) leastRecentlyUsed() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.MaxSessions <= 0 || len(s.sessions) < s.MaxSessions {
+		return ""
+	}
+	var oldest string
+	for key, sess := range s.sessions {
+		if oldest == "" || sess.lastUsed.Before(s.sessions[oldest].lastUsed) {
+			oldest = key
+		}
+	}
+	return oldest
+}
+
+// storeClearance remembers the cookies of a solved challenge. Solutions
+// without a clearance cookie are not reused, since the page may need the
+// browser for reasons other than Cloudflare.
+func (s *Sessions) storeClearance(key string, solution *Solution) {
+	var clearance *Cookie
+	for i := range solution.Cookies {
+		if solution.Cookies[i].Name == ClearanceCookie {
+			clearance = &solution.Cookies[i]
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, found := s.sessions[key]
+	if !found {
+		return
+	}
+	if clearance == nil || clearance.Expires <= 0 || solution.UserAgent == "" {
+		sess.cookies, sess.userAgent, sess.expires = nil, "", time.Time{}
+		return
+	}
+
+	cookies := make([]*http.Cookie, 0, len(solution.Cookies))
+	for _, c := range solution.Cookies {
+		cookies = append(cookies, c.HTTPCookie())
+	}
+	sess.cookies = cookies
+	sess.userAgent = solution.UserAgent
+	sess.expires = time.Unix(int64(clearance.Expires), 0)
+}
+
+func (s *Sessions) forgetClearance(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sess, found := s.sessions[key]; found {
+		sess.cookies, sess.userAgent, sess.expires = nil, "", time.Time{}
+	}
+}
+
+// sessionID derives a stable FlareSolverr session name, so sessions left
+// behind by a previous operator instance are picked up again.
+func sessionID(key string) string {
+	return "k8s-arr-" + strings.ReplaceAll(key, "/", "-")
+}
+
+// cookieDomainMatches reports whether the cookie is sent to host, as a
+// browser would. Cookies without a domain are those of the solved host.
+func cookieDomainMatches(c *http.Cookie, host string) bool {
+	domain := strings.ToLower(strings.TrimPrefix(c.Domain, "."))
+	host = strings.ToLower(host)
+	return domain == "" || host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+func proxyKey(proxy *url.URL) string {
+	if proxy == nil {
+		return ""
+	}
+	return proxy.String()
+}
+
+func methodOrGet(method string) string {
+	if method == "" {
+		return http.MethodGet
+	}
+	return method
+}
@@ -0,0 +1,230 @@
+package flaresolverr
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFlareSolverr records the API calls and answers like FlareSolverr after
+// solving a challenge on the target site.
+type fakeFlareSolverr struct {
+	mu       sync.Mutex
+	calls    []request
+	expires  time.Time
+	response string
+	// domain of the clearance cookie
+	domain string
+}
+
+func (f *fakeFlareSolverr) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, req)
+	domain := f.domain
+	f.mu.Unlock()
+
+	resp := response{Status: "ok"}
+	switch req.Cmd {
+	case "sessions.create":
+		resp.Session = req.Session
+	case "request.get", "request.post":
+		resp.Solution = Solution{
+			URL:      req.URL,
+			Status:   http.StatusOK,
+			Response: f.response,
+			Cookies: []Cookie{
+				{Name: ClearanceCookie, Value: "cleared", Domain: domain, Expires: float64(f.expires.Unix())},
+			},
+			UserAgent: "FlareSolverr-Browser",
+		}
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (f *fakeFlareSolverr) commands() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var cmds []string
+	for _, c := range f.calls {
+		cmds = append(cmds, c.Cmd)
+	}
+	return cmds
+}
+
+// protectedSite only serves clients presenting the clearance of the browser
+func protectedSite() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(ClearanceCookie)
+		if err != nil || cookie.Value != "cleared" || r.UserAgent() != "FlareSolverr-Browser" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("direct " + r.Method))
+	}))
+}
+
+func TestSessionsReuseClearance(t *testing.T) {
+	fs := &fakeFlareSolverr{expires: time.Now().Add(time.Hour), response: "solved"}
+	fsServer := httptest.NewServer(fs)
+	defer fsServer.Close()
+	site := protectedSite()
+	defer site.Close()
+
+	sessions := NewSessions(NewClient(fsServer.URL, nil))
+	ctx := context.Background()
+
+	body, err := sessions.Do(ctx, "default/tracker", http.DefaultClient, Request{Method: http.MethodGet, URL: site.URL})
+	require.NoError(t, err)
+	assert.Equal(t, "solved", string(body))
+
+	body, err = sessions.Do(ctx, "default/tracker", http.DefaultClient, Request{Method: http.MethodGet, URL: site.URL})
+	require.NoError(t, err)
+	assert.Equal(t, "direct GET", string(body))
+
+	body, err = sessions.Do(ctx, "default/tracker", http.DefaultClient, Request{Method: http.MethodPost, URL: site.URL, PostData: "q=ubuntu"})
+	require.NoError(t, err)
+	assert.Equal(t, "direct POST", string(body))
+
+	assert.Equal(t, []string{"sessions.create", "request.get"}, fs.commands())
+	assert.Equal(t, "k8s-arr-default-tracker", fs.calls[1].Session)
+	assert.Equal(t, int(DefaultMaxTimeout/time.Millisecond), fs.calls[1].MaxTimeout)
+
+	require.NoError(t, sessions.Destroy(ctx, "default/tracker"))
+	assert.Equal(t, "sessions.destroy", fs.commands()[2])
+}
+
+func TestSessionsClearanceDomain(t *testing.T) {
+	fs := &fakeFlareSolverr{expires: time.Now().Add(time.Hour), response: "solved", domain: ".127.0.0.1"}
+	fsServer := httptest.NewServer(fs)
+	defer fsServer.Close()
+	site := protectedSite()
+	defer site.Close()
+
+	sessions := NewSessions(NewClient(fsServer.URL, nil))
+	ctx := context.Background()
+
+	_, err := sessions.Do(ctx, "default/tracker", http.DefaultClient, Request{Method: http.MethodGet, URL: site.URL})
+	require.NoError(t, err)
+	body, err := sessions.Do(ctx, "default/tracker", http.DefaultClient, Request{Method: http.MethodGet, URL: site.URL})
+	require.NoError(t, err)
+	assert.Equal(t, "direct GET", string(body))
+
+	// The clearance of another domain is not sent, the page is solved again
+	fs.mu.Lock()
+	fs.domain = "tracker.example"
+	fs.mu.Unlock()
+	_, err = sessions.Do(ctx, "default/other", http.DefaultClient, Request{Method: http.MethodGet, URL: site.URL})
+	require.NoError(t, err)
+	body, err = sessions.Do(ctx, "default/other", http.DefaultClient, Request{Method: http.MethodGet, URL: site.URL})
+	require.NoError(t, err)
+	assert.Equal(t, "solved", string(body))
+}
+
+func TestSessionsExpiredClearance(t *testing.T) {
+	fs := &fakeFlareSolverr{expires: time.Now().Add(-time.Minute), response: "solved"}
+	fsServer := httptest.NewServer(fs)
+	defer fsServer.Close()
+	site := protectedSite()
+	defer site.Close()
+
+	sessions := NewSessions(NewClient(fsServer.URL, nil))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		body, err := sessions.Do(ctx, "default/tracker", http.DefaultClient, Request{Method: http.MethodGet, URL: site.URL})
+		require.NoError(t, err)
+		assert.Equal(t, "solved", string(body))
+	}
+
+	// The session is created once and reused for both solves
+	assert.Equal(t, []string{"sessions.create", "request.get", "request.get"}, fs.commands())
+}
+
+func TestSessionsPost(t *testing.T) {
+	fs := &fakeFlareSolverr{expires: time.Now().Add(time.Hour), response: "results"}
+	fsServer := httptest.NewServer(fs)
+	defer fsServer.Close()
+
+	sessions := NewSessions(NewClient(fsServer.URL, nil))
+	_, err := sessions.Do(context.Background(), "default/tracker", http.DefaultClient, Request{
+		Method:   http.MethodPost,
+		URL:      "https://tracker.invalid/search",
+		PostData: "q=ubuntu&cat=0",
+	})
+	require.NoError(t, err)
+
+	require.Len(t, fs.calls, 2)
+	assert.Equal(t, "request.post", fs.calls[1].Cmd)
+	assert.Equal(t, "q=ubuntu&cat=0", fs.calls[1].PostData)
+}
+
+func TestSessionsRechallenged(t *testing.T) {
+	fs := &fakeFlareSolverr{expires: time.Now().Add(time.Hour), response: "solved"}
+	fsServer := httptest.NewServer(fs)
+	defer fsServer.Close()
+
+	// The site never accepts the clearance, so every request needs the browser
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer site.Close()
+
+	sessions := NewSessions(NewClient(fsServer.URL, nil))
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		body, err := sessions.Do(ctx, "default/tracker", http.DefaultClient, Request{Method: http.MethodGet, URL: site.URL})
+		require.NoError(t, err)
+		assert.Equal(t, "solved", string(body))
+	}
+	assert.Equal(t, []string{"sessions.create", "request.get", "request.get"}, fs.commands())
+}
+
+func TestClientError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(response{Status: "error", Message: "Error solving the challenge"})
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, nil).Get(context.Background(), "", "https://tracker.invalid", nil)
+	assert.ErrorContains(t, err, "Error solving the challenge")
+}
+
+func TestSessionsMaxSessions(t *testing.T) {
+	fs := &fakeFlareSolverr{expires: time.Now().Add(-time.Minute), response: "solved"}
+	fsServer := httptest.NewServer(fs)
+	defer fsServer.Close()
+
+	sessions := NewSessions(NewClient(fsServer.URL, nil))
+	sessions.MaxSessions = 2
+	ctx := context.Background()
+
+	for _, key := range []string{"default/a", "default/b", "default/a", "default/c"} {
+		_, err := sessions.Do(ctx, key, http.DefaultClient, Request{Method: http.MethodGet, URL: "https://tracker.invalid"})
+		require.NoError(t, err)
+	}
+
+	// b is the least recently used session when c needs room
+	var destroyed []string
+	for _, c := range fs.calls {
+		if c.Cmd == "sessions.destroy" {
+			destroyed = append(destroyed, c.Session)
+		}
+	}
+	assert.Equal(t, []string{"k8s-arr-default-b"}, destroyed)
+
+	require.NoError(t, sessions.DestroyAll(ctx))
+	assert.Len(t, sessions.sessions, 0)
+}
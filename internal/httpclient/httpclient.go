@@ -0,0 +1,167 @@
+package httpclient
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Options describes how requests to a single indexer should be sent
+type Options struct {
+	// Certificates are SHA-1 or SHA-256 fingerprints of server certificates
+	// that are accepted even when they fail normal verification
+	// (self-signed, expired or issued for another host).
+	Certificates []string
+
+	// CABundle is a PEM bundle of additional certificate authorities trusted
+	// on top of the system roots.
+	CABundle []byte
+
+	// Proxy routes every request through an HTTP (CONNECT) or SOCKS5 proxy.
+	// Credentials are taken from the URL user info.
+	Proxy *url.URL
+}
+
+// IsZero reports whether the options require nothing beyond the default client
+func (o Options) IsZero() bool {
+	return len(o.Certificates) == 0 && len(o.CABundle) == 0 && o.Proxy == nil
+}
+
+// New returns a client derived from base that applies the given options.
+// base is returned as is when the options are empty.
+func New(base *http.Client, opts Options) (*http.Client, error) {
+	if base == nil {
+		base = &http.Client{}
+	}
+	if opts.IsZero() {
+		return base, nil
+	}
+
+	transport := baseTransport(base).Clone()
+
+	if len(opts.Certificates) > 0 || len(opts.CABundle) > 0 {
+		tlsConfig, err := TLSConfig(opts)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	if opts.Proxy != nil {
+		if err := ValidateProxy(opts.Proxy); err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(opts.Proxy)
+	}
+
+	c := *base
+	c.Transport = transport
+	return &c, nil
+}
+
+// ValidateProxy checks the proxy uses a scheme supported by the transport
+func ValidateProxy(proxy *url.URL) error {
+	switch proxy.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("unsupported proxy scheme %q: expected http, https or socks5", proxy.Scheme)
+	}
+	if proxy.Host == "" {
+		return fmt.Errorf("proxy URL %q has no host", proxy.Redacted())
+	}
+	return nil
+}
+
+// TLSConfig builds the TLS configuration honoring the CA bundle and the
+// pinned certificate fingerprints.
+func TLSConfig(opts Options) (*tls.Config, error) {
+	var roots *x509.CertPool
+	if len(opts.CABundle) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(opts.CABundle) {
+			return nil, fmt.Errorf("CA bundle does not contain any PEM certificate")
+		}
+		roots = pool
+	}
+
+	pins, err := parseFingerprints(opts.Certificates)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{RootCAs: roots}
+	if len(pins) == 0 {
+		return config, nil
+	}
+
+	// Go cannot fall back to pinning after the standard verification fails,
+	// so that verification is disabled here and redone in VerifyConnection.
+	config.InsecureSkipVerify = true
+	config.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("server did not present a certificate")
+		}
+
+		verifyOpts := x509.VerifyOptions{
+			DNSName:       cs.ServerName,
+			Roots:         roots,
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range cs.PeerCertificates[1:] {
+			verifyOpts.Intermediates.AddCert(cert)
+		}
+		leaf := cs.PeerCertificates[0]
+		_, verifyErr := leaf.Verify(verifyOpts)
+		if verifyErr == nil {
+			return nil
+		}
+
+		// Only the leaf is pinned: the rest of the chain is whatever the
+		// server chooses to send, so a pinned certificate there proves nothing
+		if pins[Fingerprint(leaf, sha1.New())] || pins[Fingerprint(leaf, sha256.New())] {
+			return nil
+		}
+		return fmt.Errorf("certificate does not match any indexer certificate: %w", verifyErr)
+	}
+	return config, nil
+}
+
+// Fingerprint returns the upper case hex digest of the DER encoded certificate
+func Fingerprint(cert *x509.Certificate, h hash.Hash) string {
+	_, _ = h.Write(cert.Raw)
+	return strings.ToUpper(hex.EncodeToString(h.Sum(nil)))
+}
+
+// parseFingerprints normalizes the fingerprints, accepting both the plain hex
+// form used by Prowlarr definitions and the colon separated form printed by openssl.
+func parseFingerprints(certificates []string) (map[string]bool, error) {
+	pins := make(map[string]bool, len(certificates))
+	for _, c := range certificates {
+		fp := strings.ToUpper(strings.NewReplacer(":", "", " ", "").Replace(strings.TrimSpace(c)))
+		if fp == "" {
+			continue
+		}
+		if _, err := hex.DecodeString(fp); err != nil || (len(fp) != 2*sha1.Size && len(fp) != 2*sha256.Size) {
+			return nil, fmt.Errorf("invalid certificate fingerprint %q: expected a SHA-1 or SHA-256 hex digest", c)
+		}
+		pins[fp] = true
+	}
+	return pins, nil
+}
+
+func baseTransport(c *http.Client) *http.Transport {
+	if t, ok := c.Transport.(*http.Transport); ok && t != nil {
+		return t
+	}
+	return http.DefaultTransport.(*http.Transport)
+}
@@ -0,0 +1,251 @@
+package httpclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cert := server.Certificate()
+	sha1Pin := Fingerprint(cert, sha1.New())
+	sha256Pin := Fingerprint(cert, sha256.New())
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{name: "Default verification rejects self-signed", opts: Options{}, wantErr: true},
+		{name: "SHA-1 fingerprint", opts: Options{Certificates: []string{sha1Pin}}},
+		{name: "SHA-256 fingerprint lower case", opts: Options{Certificates: []string{strings.ToLower(sha256Pin)}}},
+		{name: "Colon separated fingerprint", opts: Options{Certificates: []string{colonSeparated(sha256Pin)}}},
+		{name: "Unknown fingerprint", opts: Options{Certificates: []string{strings.Repeat("A", 64)}}, wantErr: true},
+		{name: "CA bundle", opts: Options{CABundle: caBundle}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(&http.Client{}, tt.opts)
+			require.NoError(t, err)
+
+			resp, err := c.Get(server.URL)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestNewPinsOnlyTheLeaf(t *testing.T) {
+	pinned := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer pinned.Close()
+	pinnedCert := pinned.Certificate()
+
+	// The server presents an unpinned leaf, with the pinned certificate behind it
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	leaf, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{{
+		Certificate: [][]byte{leaf, pinnedCert.Raw},
+		PrivateKey:  key,
+	}}}
+	server.StartTLS()
+	defer server.Close()
+
+	c, err := New(&http.Client{}, Options{Certificates: []string{Fingerprint(pinnedCert, sha256.New())}})
+	require.NoError(t, err)
+	_, err = c.Get(server.URL)
+	assert.ErrorContains(t, err, "certificate does not match")
+}
+
+func TestNewInvalidOptions(t *testing.T) {
+	_, err := New(nil, Options{Certificates: []string{"not-a-hash"}})
+	assert.Error(t, err)
+
+	_, err = New(nil, Options{CABundle: []byte("garbage")})
+	assert.Error(t, err)
+}
+
+func TestNewReturnsBaseWhenUnconfigured(t *testing.T) {
+	base := &http.Client{}
+	c, err := New(base, Options{})
+	require.NoError(t, err)
+	assert.Same(t, base, c)
+}
+
+func TestNewHTTPProxy(t *testing.T) {
+	var gotAuth, gotURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Proxy-Authorization")
+		gotURL = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+	proxyURL.User = url.UserPassword("user", "secret")
+
+	c, err := New(&http.Client{}, Options{Proxy: proxyURL})
+	require.NoError(t, err)
+
+	resp, err := c.Get("http://tracker.invalid/search?q=ubuntu")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, "http://tracker.invalid/search?q=ubuntu", gotURL)
+	assert.Equal(t, "Basic dXNlcjpzZWNyZXQ=", gotAuth)
+}
+
+func TestNewSOCKS5Proxy(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer target.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	users := make(chan string, 1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() { _ = serveSOCKS5(conn, users) }()
+		}
+	}()
+
+	c, err := New(&http.Client{}, Options{Proxy: &url.URL{
+		Scheme: "socks5",
+		Host:   listener.Addr().String(),
+		User:   url.UserPassword("vpn", "secret"),
+	}})
+	require.NoError(t, err)
+
+	resp, err := c.Get(target.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(body))
+	assert.Equal(t, "vpn", <-users)
+}
+
+func TestNewInvalidProxy(t *testing.T) {
+	_, err := New(nil, Options{Proxy: &url.URL{Scheme: "ftp", Host: "proxy:21"}})
+	assert.Error(t, err)
+
+	_, err = New(nil, Options{Proxy: &url.URL{Scheme: "socks5"}})
+	assert.Error(t, err)
+}
+
+// serveSOCKS5 implements just enough of RFC 1928/1929 to tunnel a single
+// connection using username/password authentication. The username of each
+// authenticated connection is sent to users.
+func serveSOCKS5(conn net.Conn, users chan<- string) error {
+	defer conn.Close()
+
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(conn, make([]byte, header[1])); err != nil {
+		return err
+	}
+	if _, err := conn.Write([]byte{5, 2}); err != nil {
+		return err
+	}
+
+	// Username/password sub-negotiation
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+	user := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, user); err != nil {
+		return err
+	}
+	passLen := make([]byte, 1)
+	if _, err := io.ReadFull(conn, passLen); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(conn, make([]byte, passLen[0])); err != nil {
+		return err
+	}
+	if _, err := conn.Write([]byte{1, 0}); err != nil {
+		return err
+	}
+	users <- string(user)
+
+	// CONNECT request, only IPv4 addresses are needed for httptest servers
+	request := make([]byte, 10)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return err
+	}
+	addr := net.JoinHostPort(net.IP(request[4:8]).String(), strconv.Itoa(int(binary.BigEndian.Uint16(request[8:10]))))
+	upstream, err := net.Dial("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer upstream.Close()
+	if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+		return err
+	}
+
+	go func() { _, _ = io.Copy(upstream, conn) }()
+	_, _ = io.Copy(conn, upstream)
+	return nil
+}
+
+func colonSeparated(fp string) string {
+	var parts []string
+	for i := 0; i < len(fp); i += 2 {
+		parts = append(parts, fp[i:i+2])
+	}
+	return strings.Join(parts, ":")
+}
@@ -1,7 +1,11 @@
 package parser
 
 import (
+	"encoding/base32"
+	"encoding/hex"
 	"fmt"
+	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -21,8 +25,9 @@ type ParseResult struct {
 	Indexer     string
 }
 
-// ParseHTML parses the HTML content using the indexer definition selector
-func ParseHTML(htmlContent string, indexer *v1alpha1.Indexer) ([]ParseResult, error) {
+// ParseHTML parses the HTML content using the indexer definition selector.
+// baseURL is the mirror that served the page and is used to resolve relative links.
+func ParseHTML(htmlContent string, baseURL string, indexer *v1alpha1.Indexer) ([]ParseResult, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
 	if err != nil {
 		return nil, fmt.Errorf("failed to load HTML: %v", err)
@@ -36,9 +41,7 @@ func ParseHTML(htmlContent string, indexer *v1alpha1.Indexer) ([]ParseResult, er
 	}
 
 	// iterate over rows
-	fmt.Printf("Parser: URL=%s Selector=%s\n", indexer.Spec.Links[0], spec.Search.Rows.Selector)
 	doc.Find(spec.Search.Rows.Selector).Each(func(i int, s *goquery.Selection) {
-		fmt.Printf("Parser: Found Row %d\n", i)
 		result := ParseResult{
 			Indexer: indexer.Name,
 		}
@@ -58,18 +61,7 @@ func ParseHTML(htmlContent string, indexer *v1alpha1.Indexer) ([]ParseResult, er
 			}
 			val, exists := dlSel.Attr(attr)
 			if exists {
-				// Resolve relative URL
-				if !strings.HasPrefix(val, "http") && !strings.HasPrefix(val, "magnet:") {
-					// Use indexer link as base.
-					// We need base URL here. We have indexer.Spec.Links[0]
-					baseURL := strings.TrimRight(indexer.Spec.Links[0], "/")
-					if !strings.HasPrefix(val, "/") {
-						val = baseURL + "/" + val
-					} else {
-						val = baseURL + val
-					}
-				}
-				result.Magnet = val
+				result.Magnet = ResolveURL(val, baseURL, spec.LegacyLinks)
 			}
 		}
 
@@ -96,7 +88,6 @@ func ParseHTML(htmlContent string, indexer *v1alpha1.Indexer) ([]ParseResult, er
 		}
 
 		// Only add valid results (must have title and magnet)
-		fmt.Printf("Parser Row %d: Title='%s' Magnet='%s' Size='%s' Seeders=%d\n", i, result.Title, result.Magnet, result.Size, result.Seeders)
 		if result.Title != "" && result.Magnet != "" {
 			results = append(results, result)
 		}
@@ -105,6 +96,43 @@ func ParseHTML(htmlContent string, indexer *v1alpha1.Indexer) ([]ParseResult, er
 	return results, nil
 }
 
+// ResolveURL makes val absolute against the mirror that served the page and
+// rewrites links pointing at one of the legacy domains to that mirror.
+func ResolveURL(val string, baseURL string, legacyLinks []string) string {
+	if strings.HasPrefix(val, "magnet:") {
+		return val
+	}
+
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	// Resolve relative URL
+	if !strings.HasPrefix(val, "http") {
+		if !strings.HasPrefix(val, "/") {
+			return baseURL + "/" + val
+		}
+		return baseURL + val
+	}
+
+	// Legacy domains are matched regardless of scheme, since trackers moving
+	// domains often switched to https at the same time
+	rest := stripScheme(val)
+	for _, legacy := range legacyLinks {
+		prefix := strings.TrimRight(stripScheme(legacy), "/")
+		if prefix != "" && (rest == prefix || strings.HasPrefix(rest, prefix+"/")) {
+			return baseURL + strings.TrimPrefix(rest, prefix)
+		}
+	}
+
+	return val
+}
+
+func stripScheme(u string) string {
+	if i := strings.Index(u, "://"); i >= 0 {
+		return u[i+3:]
+	}
+	return u
+}
+
 // ParseDetailsPage parses the details page HTML to find the magnet link
 func ParseDetailsPage(htmlContent string, downloadBlock *v1alpha1.DownloadBlock) (string, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
@@ -131,3 +159,63 @@ func ParseDetailsPage(htmlContent string, downloadBlock *v1alpha1.DownloadBlock)
 
 	return "", fmt.Errorf("magnet link not found in details page")
 }
+
+var sizePattern = regexp.MustCompile(`(?i)([\d.,]+)\s*([kmgtp]?i?b|bytes?)?`)
+
+// ParseSize converts a human readable size such as "1.4 GB" or "700 MiB" to
+// bytes, returning 0 when the size cannot be understood
+func ParseSize(size string) int64 {
+	m := sizePattern.FindStringSubmatch(strings.TrimSpace(size))
+	if m == nil {
+		return 0
+	}
+	value, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+
+	// Trackers use binary units whatever suffix they print
+	multiplier := float64(1)
+	if unit := strings.ToUpper(m[2]); unit != "" {
+		switch unit[0] {
+		case 'K':
+			multiplier = 1 << 10
+		case 'M':
+			multiplier = 1 << 20
+		case 'G':
+			multiplier = 1 << 30
+		case 'T':
+			multiplier = 1 << 40
+		case 'P':
+			multiplier = 1 << 50
+		}
+	}
+	return int64(value * multiplier)
+}
+
+// InfoHash extracts the BitTorrent v1 info hash of a magnet link as
+// lowercase hex, returning "" for other links
+func InfoHash(magnet string) string {
+	u, err := url.Parse(magnet)
+	if err != nil || u.Scheme != "magnet" {
+		return ""
+	}
+	for _, xt := range u.Query()["xt"] {
+		hash, ok := strings.CutPrefix(strings.ToLower(xt), "urn:btih:")
+		if !ok {
+			continue
+		}
+		switch len(hash) {
+		case 40:
+			if _, err := hex.DecodeString(hash); err == nil {
+				return hash
+			}
+		case 32:
+			// Older links encode the hash in base32
+			if b, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash)); err == nil {
+				return hex.EncodeToString(b)
+			}
+		}
+	}
+	return ""
+}
@@ -15,7 +15,8 @@ func TestParseHTML(t *testing.T) {
 			Name: "test-indexer",
 		},
 		Spec: v1alpha1.IndexerSpec{
-			Links: []string{"https://example.com"},
+			Links:       []string{"https://example.com"},
+			LegacyLinks: []string{"http://old.example.com/"},
 			Search: &v1alpha1.Search{
 				Rows: v1alpha1.RowsBlock{
 					Selector: "tr.result",
@@ -90,11 +91,38 @@ func TestParseHTML(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Legacy Domain Rewrite",
+			html: `
+				<html>
+					<body>
+						<table>
+							<tr class="result">
+								<td class="title">Fedora ISO</td>
+								<td><a class="dl" href="https://old.example.com/torrent/fedora">Download</a></td>
+								<td class="size">2 GB</td>
+								<td class="seeds">20</td>
+								<td class="leechs">2</td>
+							</tr>
+						</table>
+					</body>
+				</html>
+			`,
+			expected: []ParseResult{
+				{
+					Title:    "Fedora ISO",
+					Magnet:   "https://example.com/torrent/fedora", // Expect rewritten to the active mirror
+					Size:     "2 GB",
+					Seeders:  20,
+					Leechers: 2,
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			results, err := ParseHTML(tt.html, indexer)
+			results, err := ParseHTML(tt.html, "https://example.com", indexer)
 			assert.NoError(t, err)
 			assert.Equal(t, len(tt.expected), len(results))
 			if len(results) > 0 {
@@ -105,3 +133,71 @@ func TestParseHTML(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveURL(t *testing.T) {
+	legacy := []string{"https://old.example.com", "http://older.example.com/tracker/"}
+
+	tests := []struct {
+		name     string
+		val      string
+		baseURL  string
+		expected string
+	}{
+		{"Magnet untouched", "magnet:?xt=urn:btih:123", "https://mirror.example.com", "magnet:?xt=urn:btih:123"},
+		{"Relative with slash", "/dl/1", "https://mirror.example.com/", "https://mirror.example.com/dl/1"},
+		{"Relative without slash", "dl/1", "https://mirror.example.com", "https://mirror.example.com/dl/1"},
+		{"Absolute foreign untouched", "https://other.example.com/dl/1", "https://mirror.example.com", "https://other.example.com/dl/1"},
+		{"Legacy domain", "https://old.example.com/dl/1", "https://mirror.example.com", "https://mirror.example.com/dl/1"},
+		{"Legacy domain different scheme", "http://old.example.com/dl/1", "https://mirror.example.com", "https://mirror.example.com/dl/1"},
+		{"Legacy with path", "https://older.example.com/tracker/dl/1", "https://mirror.example.com", "https://mirror.example.com/dl/1"},
+		{"Legacy prefix is not a host match", "https://old.example.com.evil/dl/1", "https://mirror.example.com", "https://old.example.com.evil/dl/1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ResolveURL(tt.val, tt.baseURL, legacy))
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		size     string
+		expected int64
+	}{
+		{"1.5 GB", 1610612736},
+		{"700 MiB", 734003200},
+		{"1,024 KB", 1048576},
+		{"2TB", 2199023255552},
+		{"512 bytes", 512},
+		{"unknown", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseSize(tt.size))
+		})
+	}
+}
+
+func TestInfoHash(t *testing.T) {
+	tests := []struct {
+		magnet   string
+		expected string
+	}{
+		{"magnet:?xt=urn:btih:C9E15763F722F23E98A29DECDFAE341B98D53056&dn=ubuntu", "c9e15763f722f23e98a29decdfae341b98d53056"},
+		{"magnet:?dn=ubuntu&xt=urn:btih:c9e15763f722f23e98a29decdfae341b98d53056", "c9e15763f722f23e98a29decdfae341b98d53056"},
+		{"magnet:?xt=urn:btih:ZHQVOY7XELZD5GFCTXWN7LRUDOMNKMCW", "c9e15763f722f23e98a29decdfae341b98d53056"},
+		{"magnet:?xt=urn:btmh:1220c9e15763f722f23e98a29decdfae341b98d53056", ""},
+		{"magnet:?xt=urn:btih:hdtv", ""},
+		{"https://tracker.example/download/1.torrent", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.magnet, func(t *testing.T) {
+			assert.Equal(t, tt.expected, InfoHash(tt.magnet))
+		})
+	}
+}
@@ -0,0 +1,413 @@
+// Package releaseparse extracts the attributes scene and P2P release names
+// encode, such as "Show.S01E02.1080p.WEB-DL.DDP5.1.H.264-GROUP".
+package releaseparse
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Release holds the attributes parsed from a release title. Attributes the
+// title does not mention are left empty.
+type Release struct {
+	// Title is the movie or series name, without the release attributes
+	Title string
+	Year  int
+
+	// Seasons and Episodes the release covers, Episodes is empty for season packs
+	Seasons    []int
+	Episodes   []int
+	FullSeason bool
+
+	// Resolution such as "2160p", "1080p" or "720p"
+	Resolution string
+	// Source such as "WEB-DL", "BluRay" or "HDTV", see the Source constants
+	Source string
+	// Codec such as "x264", "x265" or "AV1"
+	Codec string
+	// Audio codecs such as "DD+" or "TrueHD", with Channels such as "5.1"
+	Audio    []string
+	Channels string
+	// HDR formats such as "DV" or "HDR10"
+	HDR []string
+
+	Group     string
+	Proper    bool
+	Repack    bool
+	Languages []string
+	// Edition such as "Extended" or "Director's Cut"
+	Edition string
+}
+
+// Sources, from the lowest to the highest quality
+const (
+	SourceCAM      = "CAM"
+	SourceTelesync = "TELESYNC"
+	SourceTelecine = "TELECINE"
+	SourceScreener = "SCREENER"
+	SourceDVD      = "DVD"
+	SourceHDTV     = "HDTV"
+	SourceWEBRip   = "WEBRip"
+	SourceWEBDL    = "WEB-DL"
+	SourceBluRay   = "BluRay"
+	SourceRemux    = "Remux"
+)
+
+type rule struct {
+	value   string
+	pattern *regexp.Regexp
+}
+
+// token matches p as whole words of the release title. The pattern's first
+// group is the token itself, without the surrounding separators.
+func token(p string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}])(` + p + `)(?:[^\p{L}\p{N}]|$)`)
+}
+
+// channels is the optional channel layout following an audio codec, as in DDP5.1
+const channels = `(?:[ .]?[1-9][ .][01])?`
+
+var (
+	// S01E02, S01E02E03, S01E02-E04 and S01E02-04
+	episodePattern = token(`S(\d{1,2})[ ._-]?E(\d{1,3})((?:[ ._]?E\d{1,3})*)(?:-E?(\d{1,3}))?`)
+	// 1x02
+	crossPattern = token(`(\d{1,2})x(\d{2,3})`)
+	// S01-S03, S01-03 and Season 1-3
+	multiSeasonPattern = token(`(?:S|Seasons?[ ._]?)(\d{1,2})[ ._]?(?:-|to)[ ._]?(?:S|Season[ ._]?)?(\d{1,2})`)
+	// S01 or Season 1, for season packs
+	seasonPattern = token(`(?:S|Season[ ._-]?)(\d{1,2})`)
+
+	extraEpisodePattern = regexp.MustCompile(`\d{1,3}`)
+
+	yearPattern = token(`19\d{2}|20\d{2}`)
+
+	channelsPattern = regexp.MustCompile(`(?i)(?:ddp|dd\+|dd|e?ac-?3|aac|dts(?:-?hd)?(?:[ .-]?ma)?|dts-?x|truehd|atmos|flac|opus|l?pcm)[ .]?([1-9])[ .]([01])(?:[^\p{L}\p{N}]|$)`)
+
+	// A leading [Group] tag, common for anime releases
+	leadingGroupPattern = regexp.MustCompile(`^\[([^\]]+)\][ ._-]*`)
+	// Extensions and tracker tags such as [rartv] trailing the group
+	trailerPattern = regexp.MustCompile(`(?i)(?:\.(?:mkv|mp4|m4v|avi|wmv|ts)|[ ._-]*\[[^\]]*\])+$`)
+	groupPattern   = regexp.MustCompile(`-([\p{L}\p{N}]+)$`)
+)
+
+var resolutions = []rule{
+	{"2160p", token(`2160[pi]|3840x2160|4096x2160`)},
+	{"1080p", token(`1080[pi]|1920x1080`)},
+	{"720p", token(`720[pi]|1280x720`)},
+	{"576p", token(`576[pi]`)},
+	{"480p", token(`480[pi]|640x480|720x480`)},
+	{"360p", token(`360[pi]`)},
+}
+
+// fallbackResolutions are only looked for after the title, since they read
+// as words too
+var fallbackResolutions = []rule{
+	{"2160p", token(`4k|uhd`)},
+}
+
+var sources = []rule{
+	{SourceRemux, token(`(?:bd|uhd)?remux`)},
+	{SourceBluRay, token(`blu-?ray|bdrip|brrip|bd25|bd50|bd|uhd-?bd`)},
+	{SourceWEBDL, token(`web-?dl|webhd|amzn|nf|dsnp|hmax|atvp|hulu|pcok`)},
+	{SourceWEBRip, token(`web-?rip`)},
+	{SourceHDTV, token(`hdtv|pdtv|sdtv|dsr|tvrip|hdtvrip`)},
+	{SourceScreener, token(`dvdscr|bdscr|screener`)},
+	{SourceDVD, token(`dvd-?rip|dvd-?r|dvd5|dvd9`)},
+	{SourceCAM, token(`hdcam|camrip`)},
+	{SourceTelesync, token(`telesync|hdts|pdvd`)},
+	{SourceTelecine, token(`telecine|hdtc`)},
+}
+
+// fallbackSources are only looked for after the title, since they read as
+// words too
+var fallbackSources = []rule{
+	{SourceWEBDL, token(`web`)},
+	{SourceDVD, token(`dvd`)},
+	{SourceScreener, token(`scr`)},
+	{SourceCAM, token(`cam`)},
+	{SourceTelesync, token(`ts`)},
+	{SourceTelecine, token(`tc`)},
+}
+
+var codecs = []rule{
+	{"x265", token(`x\.?265|h\.?265|hevc`)},
+	{"x264", token(`x\.?264|h\.?264|avc`)},
+	{"AV1", token(`av1`)},
+	{"VC-1", token(`vc-?1`)},
+	{"MPEG-2", token(`mpeg-?2`)},
+	{"XviD", token(`xvid|divx`)},
+}
+
+var audio = []rule{
+	{"TrueHD", token(`truehd` + channels)},
+	{"Atmos", token(`atmos` + channels)},
+	{"DTS-HD MA", token(`dts-?hd[ .-]?ma` + channels)},
+	{"DTS:X", token(`dts-?x` + channels)},
+	{"DTS-HD", token(`dts-?hd` + channels)},
+	{"DTS", token(`dts` + channels)},
+	{"DD+", token(`(?:ddp|dd\+|e-?ac-?3)` + channels)},
+	{"DD", token(`(?:dd|ac-?3)` + channels)},
+	{"AAC", token(`aac` + channels)},
+	{"FLAC", token(`flac` + channels)},
+	{"Opus", token(`opus` + channels)},
+	{"MP3", token(`mp3|320kbps|v0`)},
+	{"PCM", token(`l?pcm` + channels)},
+}
+
+var hdr = []rule{
+	{"DV", token(`dv|dovi|dolby[ .-]?vision`)},
+	{"HDR10+", token(`hdr10(?:\+|plus)`)},
+	{"HDR10", token(`hdr10`)},
+	{"HDR", token(`hdr`)},
+	{"HLG", token(`hlg`)},
+}
+
+var languages = []rule{
+	{"Multi", token(`multi`)},
+	{"English", token(`english|eng`)},
+	{"French", token(`french|truefrench|vff|vfq|vf2|vf`)},
+	{"German", token(`german|ger`)},
+	{"Spanish", token(`spanish|esp|castellano|latino`)},
+	{"Italian", token(`italian|ita`)},
+	{"Portuguese", token(`portuguese|por|pt-br`)},
+	{"Dutch", token(`dutch|nl`)},
+	{"Russian", token(`russian|rus`)},
+	{"Polish", token(`polish|pl`)},
+	{"Japanese", token(`japanese|jap|jpn`)},
+	{"Korean", token(`korean|kor`)},
+	{"Chinese", token(`chinese|chi|mandarin|cantonese`)},
+	{"Hindi", token(`hindi`)},
+	{"Swedish", token(`swedish|swe`)},
+	{"Danish", token(`danish|dan`)},
+	{"Norwegian", token(`norwegian|nor`)},
+	{"Finnish", token(`finnish|fin`)},
+	{"Nordic", token(`nordic`)},
+	{"Turkish", token(`turkish|tur`)},
+	{"Hungarian", token(`hungarian|hun`)},
+	{"Czech", token(`czech|cze`)},
+	{"Arabic", token(`arabic|ara`)},
+}
+
+var editions = []rule{
+	{"Director's Cut", token(`director'?s[ .]?cut|dc`)},
+	{"Extended", token(`extended(?:[ .](?:cut|edition))?`)},
+	{"Unrated", token(`unrated`)},
+	{"Uncut", token(`uncut`)},
+	{"Theatrical", token(`theatrical(?:[ .](?:cut|edition))?`)},
+	{"Ultimate", token(`ultimate[ .](?:cut|edition)`)},
+	{"Final Cut", token(`final[ .]cut`)},
+	{"Special Edition", token(`special[ .]edition`)},
+	{"Anniversary", token(`\d{2,3}(?:th)?[ .]anniversary(?:[ .]edition)?|anniversary[ .]edition`)},
+	{"Criterion", token(`criterion(?:[ .]collection)?`)},
+	{"IMAX", token(`imax`)},
+	{"Remastered", token(`remastered|4k[ .]remaster`)},
+}
+
+var (
+	properPattern = token(`proper`)
+	repackPattern = token(`repack\d?|rerip`)
+)
+
+// notGroups are tokens that end a release name after a dash without being a
+// release group, as in "Show.S01E02.720p.WEB-DL"
+var notGroups = []string{"dl", "rip", "hd", "ma", "x", "ray", "remux", "hdtv", "web", "dts", "sample"}
+
+// parser tracks where the name ends while the attributes are looked up
+type parser struct {
+	title string
+	// start skips a leading group tag
+	start int
+	// end is the start of the first attribute found, where the name ends
+	end int
+}
+
+// find returns the first match of pattern's first group in title[from:],
+// moving the end of the name before it
+func (p *parser) find(pattern *regexp.Regexp, from int) []string {
+	loc := pattern.FindStringSubmatchIndex(p.title[from:])
+	if loc == nil {
+		return nil
+	}
+	if from+loc[2] < p.end {
+		p.end = from + loc[2]
+	}
+	m := make([]string, 0, len(loc)/2)
+	for i := 0; i < len(loc); i += 2 {
+		if loc[i] < 0 {
+			m = append(m, "")
+			continue
+		}
+		m = append(m, p.title[from+loc[i]:from+loc[i+1]])
+	}
+	return m
+}
+
+// first returns the value of the first rule matching title[from:]
+func (p *parser) first(rules []rule, from int) string {
+	for _, r := range rules {
+		if p.find(r.pattern, from) != nil {
+			return r.value
+		}
+	}
+	return ""
+}
+
+// all returns the values of every rule matching title, in the order they
+// appear. Matches are blanked out as they are found, so "DTS-HD MA" is not
+// also read as "DTS".
+func all(title string, rules []rule) []string {
+	type match struct {
+		at    int
+		value string
+	}
+	var matches []match
+	for _, r := range rules {
+		loc := r.pattern.FindStringSubmatchIndex(title)
+		if loc == nil {
+			continue
+		}
+		title = title[:loc[2]] + strings.Repeat(" ", loc[3]-loc[2]) + title[loc[3]:]
+		matches = append(matches, match{loc[2], r.value})
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].at < matches[j].at })
+
+	var values []string
+	for _, m := range matches {
+		if !slices.Contains(values, m.value) {
+			values = append(values, m.value)
+		}
+	}
+	return values
+}
+
+// Parse extracts the attributes of a release title. The name is what
+// precedes the year, episode or first quality attribute; attributes that read
+// as ordinary words, such as languages, are only looked for after it.
+func Parse(title string) Release {
+	var r Release
+	p := &parser{title: title, end: len(title)}
+
+	if m := leadingGroupPattern.FindStringSubmatch(title); m != nil {
+		r.Group = m[1]
+		p.start = len(m[0])
+	}
+
+	r.Seasons, r.Episodes = p.episodes()
+	r.FullSeason = len(r.Seasons) > 0 && len(r.Episodes) == 0
+	r.Resolution = p.first(resolutions, p.start)
+	r.Source = p.first(sources, p.start)
+	r.Codec = p.first(codecs, p.start)
+
+	var yearAt int
+	r.Year, yearAt = p.year()
+	if r.Year > 0 && yearAt < p.end {
+		p.end = yearAt
+	}
+
+	tail := p.end
+	if r.Resolution == "" {
+		r.Resolution = p.first(fallbackResolutions, tail)
+	}
+	if r.Source == "" {
+		r.Source = p.first(fallbackSources, tail)
+	}
+	r.Audio = all(title[tail:], audio)
+	if m := channelsPattern.FindStringSubmatch(title[tail:]); m != nil {
+		r.Channels = m[1] + "." + m[2]
+	}
+	r.HDR = all(title[tail:], hdr)
+	r.Languages = all(title[tail:], languages)
+	r.Edition = p.first(editions, tail)
+	r.Proper = properPattern.MatchString(title[tail:])
+	r.Repack = repackPattern.MatchString(title[tail:])
+
+	if group := releaseGroup(title[p.start:]); group != "" {
+		r.Group = group
+	}
+	r.Title = cleanTitle(title[p.start:tail])
+	return r
+}
+
+// episodes parses the seasons and episodes the release covers
+func (p *parser) episodes() (seasons, episodes []int) {
+	if m := p.find(episodePattern, p.start); m != nil {
+		season := atoi(m[2])
+		first := atoi(m[3])
+		episodes = []int{first}
+		for _, extra := range extraEpisodePattern.FindAllString(m[4], -1) {
+			episodes = append(episodes, atoi(extra))
+		}
+		if m[5] != "" {
+			for n := first + 1; n <= atoi(m[5]); n++ {
+				episodes = append(episodes, n)
+			}
+		}
+		return []int{season}, episodes
+	}
+	if m := p.find(crossPattern, p.start); m != nil {
+		return []int{atoi(m[2])}, []int{atoi(m[3])}
+	}
+	if m := p.find(multiSeasonPattern, p.start); m != nil {
+		for n := atoi(m[2]); n <= atoi(m[3]); n++ {
+			seasons = append(seasons, n)
+		}
+		if len(seasons) > 0 {
+			return seasons, nil
+		}
+	}
+	if m := p.find(seasonPattern, p.start); m != nil {
+		return []int{atoi(m[2])}, nil
+	}
+	return nil, nil
+}
+
+// year picks the release year and where it starts. Years are only taken
+// before the other attributes, and the last one wins so names such as
+// "Blade Runner 2049 2017" or "2001 A Space Odyssey 1968" keep their number.
+// A year opening the name is the name itself, as in "1917".
+func (p *parser) year() (int, int) {
+	year, at := 0, 0
+	for from := p.start; from < len(p.title); {
+		loc := yearPattern.FindStringSubmatchIndex(p.title[from:])
+		if loc == nil {
+			break
+		}
+		start := from + loc[2]
+		if start > p.end {
+			break
+		}
+		if start > p.start {
+			year, at = atoi(p.title[start:from+loc[3]]), start
+		}
+		from += loc[3]
+	}
+	return year, at
+}
+
+// releaseGroup returns the group a scene release name ends with
+func releaseGroup(title string) string {
+	title = trailerPattern.ReplaceAllString(title, "")
+	m := groupPattern.FindStringSubmatch(title)
+	if m == nil || slices.Contains(notGroups, strings.ToLower(m[1])) {
+		return ""
+	}
+	if _, err := strconv.Atoi(m[1]); err == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// cleanTitle turns the dotted name of a release into words
+func cleanTitle(name string) string {
+	name = strings.NewReplacer(".", " ", "_", " ").Replace(name)
+	name = strings.Join(strings.Fields(name), " ")
+	return strings.Trim(name, " -([")
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,321 @@
+package releaseparse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		title string
+		want  Release
+	}{
+		// TV episodes
+		{"Show.S01E02.1080p.WEB-DL.DDP5.1.H.264-NTb", Release{
+			Title: "Show", Seasons: []int{1}, Episodes: []int{2},
+			Resolution: "1080p", Source: SourceWEBDL, Codec: "x264", Audio: []string{"DD+"}, Channels: "5.1", Group: "NTb",
+		}},
+		{"The.Mandalorian.S02E01.2160p.DSNP.WEB-DL.DDP5.1.Atmos.DV.HDR.H.265-FLUX", Release{
+			Title: "The Mandalorian", Seasons: []int{2}, Episodes: []int{1},
+			Resolution: "2160p", Source: SourceWEBDL, Codec: "x265", Audio: []string{"DD+", "Atmos"}, Channels: "5.1",
+			HDR: []string{"DV", "HDR"}, Group: "FLUX",
+		}},
+		{"Show.Name.S01E02E03.720p.HDTV.x264-KILLERS[rartv]", Release{
+			Title: "Show Name", Seasons: []int{1}, Episodes: []int{2, 3},
+			Resolution: "720p", Source: SourceHDTV, Codec: "x264", Group: "KILLERS",
+		}},
+		{"Show S01E02E03 720p", Release{Title: "Show", Seasons: []int{1}, Episodes: []int{2, 3}, Resolution: "720p"}},
+		{"Show.S02E01-E03.720p", Release{Title: "Show", Seasons: []int{2}, Episodes: []int{1, 2, 3}, Resolution: "720p"}},
+		{"Show.S02E05-07.720p", Release{Title: "Show", Seasons: []int{2}, Episodes: []int{5, 6, 7}, Resolution: "720p"}},
+		{"Show 3x04 HDTV XviD-LOL", Release{
+			Title: "Show", Seasons: []int{3}, Episodes: []int{4}, Source: SourceHDTV, Codec: "XviD", Group: "LOL",
+		}},
+		{"show_name_s05e10_480p_hdtv", Release{
+			Title: "show name", Seasons: []int{5}, Episodes: []int{10}, Resolution: "480p", Source: SourceHDTV,
+		}},
+		{"Show Name - S01E100 - Episode Title [1080p]", Release{
+			Title: "Show Name", Seasons: []int{1}, Episodes: []int{100}, Resolution: "1080p",
+		}},
+		{"Show.2019.S03E04.1080p.AMZN.WEB-DL.DDP5.1.H.264-NTb", Release{
+			Title: "Show", Year: 2019, Seasons: []int{3}, Episodes: []int{4},
+			Resolution: "1080p", Source: SourceWEBDL, Codec: "x264", Audio: []string{"DD+"}, Channels: "5.1", Group: "NTb",
+		}},
+		{"Show.S01E05.REPACK.720p.AMZN.WEB-DL.DDP2.0.H.264-NTb.mkv", Release{
+			Title: "Show", Seasons: []int{1}, Episodes: []int{5},
+			Resolution: "720p", Source: SourceWEBDL, Codec: "x264", Audio: []string{"DD+"}, Channels: "2.0", Group: "NTb", Repack: true,
+		}},
+		{"Show.S01E05.PROPER.720p.HDTV.x264-DIMENSION", Release{
+			Title: "Show", Seasons: []int{1}, Episodes: []int{5},
+			Resolution: "720p", Source: SourceHDTV, Codec: "x264", Group: "DIMENSION", Proper: true,
+		}},
+		{"Show.S01E06.REPACK2.1080p.WEB.h264-GGEZ", Release{
+			Title: "Show", Seasons: []int{1}, Episodes: []int{6},
+			Resolution: "1080p", Source: SourceWEBDL, Codec: "x264", Group: "GGEZ", Repack: true,
+		}},
+		{"Show.S01E07.1080p.WEBRip.x265.10bit.AAC5.1-RARBG", Release{
+			Title: "Show", Seasons: []int{1}, Episodes: []int{7},
+			Resolution: "1080p", Source: SourceWEBRip, Codec: "x265", Audio: []string{"AAC"}, Channels: "5.1", Group: "RARBG",
+		}},
+		{"Show.S10E01.2160p.NF.WEB-DL.DDP5.1.HDR10+.HEVC-NPMS", Release{
+			Title: "Show", Seasons: []int{10}, Episodes: []int{1},
+			Resolution: "2160p", Source: SourceWEBDL, Codec: "x265", Audio: []string{"DD+"}, Channels: "5.1",
+			HDR: []string{"HDR10+"}, Group: "NPMS",
+		}},
+		{"Show.S01E01.German.DL.1080p.WEB.x264-WvF", Release{
+			Title: "Show", Seasons: []int{1}, Episodes: []int{1},
+			Resolution: "1080p", Source: SourceWEBDL, Codec: "x264", Languages: []string{"German"}, Group: "WvF",
+		}},
+		{"Show.S02E03.FRENCH.720p.HDTV.x264-ZT", Release{
+			Title: "Show", Seasons: []int{2}, Episodes: []int{3},
+			Resolution: "720p", Source: SourceHDTV, Codec: "x264", Languages: []string{"French"}, Group: "ZT",
+		}},
+		{"Show.S02E03.MULTi.1080p.WEB.H264-FW", Release{
+			Title: "Show", Seasons: []int{2}, Episodes: []int{3},
+			Resolution: "1080p", Source: SourceWEBDL, Codec: "x264", Languages: []string{"Multi"}, Group: "FW",
+		}},
+		{"Show.S01E01.ITA.ENG.1080p.AMZN.WEB-DL.DDP5.1.H.264-MeM", Release{
+			Title: "Show", Seasons: []int{1}, Episodes: []int{1},
+			Resolution: "1080p", Source: SourceWEBDL, Codec: "x264", Audio: []string{"DD+"}, Channels: "5.1",
+			Languages: []string{"Italian", "English"}, Group: "MeM",
+		}},
+		{"Show.S04E12.Russian.720p.WEB-DL", Release{
+			Title: "Show", Seasons: []int{4}, Episodes: []int{12},
+			Resolution: "720p", Source: SourceWEBDL, Languages: []string{"Russian"},
+		}},
+		{"Show.Name.S01E01.1080p.HMAX.WEB-DL.DD5.1.H.264-playWEB", Release{
+			Title: "Show Name", Seasons: []int{1}, Episodes: []int{1},
+			Resolution: "1080p", Source: SourceWEBDL, Codec: "x264", Audio: []string{"DD"}, Channels: "5.1", Group: "playWEB",
+		}},
+		{"Show.Name.S01E01.720p.BluRay.x264-DEMAND", Release{
+			Title: "Show Name", Seasons: []int{1}, Episodes: []int{1},
+			Resolution: "720p", Source: SourceBluRay, Codec: "x264", Group: "DEMAND",
+		}},
+		{"Show.Name.S01E01.DVDRip.XviD-SAiNTS", Release{
+			Title: "Show Name", Seasons: []int{1}, Episodes: []int{1}, Source: SourceDVD, Codec: "XviD", Group: "SAiNTS",
+		}},
+		{"Show.Name.S01E01.1080i.HDTV.MPEG2-GROUP", Release{
+			Title: "Show Name", Seasons: []int{1}, Episodes: []int{1},
+			Resolution: "1080p", Source: SourceHDTV, Codec: "MPEG-2", Group: "GROUP",
+		}},
+		{"Show.Name.S01E01.720p.WEB-DL-GROUP.mp4", Release{
+			Title: "Show Name", Seasons: []int{1}, Episodes: []int{1}, Resolution: "720p", Source: SourceWEBDL, Group: "GROUP",
+		}},
+		{"Show.Name.S01E01.720p.WEB-DL", Release{
+			Title: "Show Name", Seasons: []int{1}, Episodes: []int{1}, Resolution: "720p", Source: SourceWEBDL,
+		}},
+		{"Show.Name.S01E01.2160p.ATVP.WEB-DL.DDP5.1.Atmos.DoVi.HDR10.H.265-FLUX", Release{
+			Title: "Show Name", Seasons: []int{1}, Episodes: []int{1},
+			Resolution: "2160p", Source: SourceWEBDL, Codec: "x265", Audio: []string{"DD+", "Atmos"}, Channels: "5.1",
+			HDR: []string{"DV", "HDR10"}, Group: "FLUX",
+		}},
+		{"Show.Name.S01E01.2160p.WEB.H265.HLG-GROUP", Release{
+			Title: "Show Name", Seasons: []int{1}, Episodes: []int{1},
+			Resolution: "2160p", Source: SourceWEBDL, Codec: "x265", HDR: []string{"HLG"}, Group: "GROUP",
+		}},
+
+		// Season packs
+		{"Show.S04.COMPLETE.1080p.BluRay.x264-ROVERS", Release{
+			Title: "Show", Seasons: []int{4}, FullSeason: true,
+			Resolution: "1080p", Source: SourceBluRay, Codec: "x264", Group: "ROVERS",
+		}},
+		{"Show Season 5 Complete", Release{Title: "Show", Seasons: []int{5}, FullSeason: true}},
+		{"Show.Season.2.720p.WEBRip.x264-GROUP", Release{
+			Title: "Show", Seasons: []int{2}, FullSeason: true,
+			Resolution: "720p", Source: SourceWEBRip, Codec: "x264", Group: "GROUP",
+		}},
+		{"Show S01-S03 1080p BluRay", Release{
+			Title: "Show", Seasons: []int{1, 2, 3}, FullSeason: true, Resolution: "1080p", Source: SourceBluRay,
+		}},
+		{"Show.S01-03.720p.WEB-DL", Release{
+			Title: "Show", Seasons: []int{1, 2, 3}, FullSeason: true, Resolution: "720p", Source: SourceWEBDL,
+		}},
+		{"Show Seasons 1 to 4 480p DVDRip", Release{
+			Title: "Show", Seasons: []int{1, 2, 3, 4}, FullSeason: true, Resolution: "480p", Source: SourceDVD,
+		}},
+		{"Show.2005.S02.1080p.AMZN.WEB-DL.DDP2.0.H.264-NTb", Release{
+			Title: "Show", Year: 2005, Seasons: []int{2}, FullSeason: true,
+			Resolution: "1080p", Source: SourceWEBDL, Codec: "x264", Audio: []string{"DD+"}, Channels: "2.0", Group: "NTb",
+		}},
+
+		// Movies
+		{"Dune.2021.2160p.UHD.BluRay.REMUX.HDR10.HEVC.TrueHD.Atmos.7.1-FGT", Release{
+			Title: "Dune", Year: 2021, Resolution: "2160p", Source: SourceRemux, Codec: "x265",
+			Audio: []string{"TrueHD", "Atmos"}, Channels: "7.1", HDR: []string{"HDR10"}, Group: "FGT",
+		}},
+		{"Blade.Runner.2049.2017.1080p.BluRay.x264.DTS-HD.MA.7.1-SWTYBLZ", Release{
+			Title: "Blade Runner 2049", Year: 2017, Resolution: "1080p", Source: SourceBluRay, Codec: "x264",
+			Audio: []string{"DTS-HD MA"}, Channels: "7.1", Group: "SWTYBLZ",
+		}},
+		{"2001.A.Space.Odyssey.1968.REMASTERED.1080p.BluRay.x265.10bit.AAC5.1-RARBG", Release{
+			Title: "2001 A Space Odyssey", Year: 1968, Resolution: "1080p", Source: SourceBluRay, Codec: "x265",
+			Audio: []string{"AAC"}, Channels: "5.1", Group: "RARBG", Edition: "Remastered",
+		}},
+		{"1917.2019.1080p.WEBRip.x264.AAC-YTS", Release{
+			Title: "1917", Year: 2019, Resolution: "1080p", Source: SourceWEBRip, Codec: "x264", Audio: []string{"AAC"}, Group: "YTS",
+		}},
+		{"1917 1080p BluRay x264", Release{Title: "1917", Resolution: "1080p", Source: SourceBluRay, Codec: "x264"}},
+		{"2012.2009.720p.BluRay.x264-SiNNERS", Release{
+			Title: "2012", Year: 2009, Resolution: "720p", Source: SourceBluRay, Codec: "x264", Group: "SiNNERS",
+		}},
+		{"The.Italian.Job.2003.720p.BluRay.x264-SiNNERS", Release{
+			Title: "The Italian Job", Year: 2003, Resolution: "720p", Source: SourceBluRay, Codec: "x264", Group: "SiNNERS",
+		}},
+		{"Charlotte's Web 2006 1080p BluRay", Release{
+			Title: "Charlotte's Web", Year: 2006, Resolution: "1080p", Source: SourceBluRay,
+		}},
+		{"The.Matrix.1999.1080p.BluRay.x264.DTS-FGT", Release{
+			Title: "The Matrix", Year: 1999, Resolution: "1080p", Source: SourceBluRay, Codec: "x264", Audio: []string{"DTS"}, Group: "FGT",
+		}},
+		{"The Matrix (1999) 1080p BrRip x264 - YIFY", Release{
+			Title: "The Matrix", Year: 1999, Resolution: "1080p", Source: SourceBluRay, Codec: "x264",
+		}},
+		{"Movie.Title.2019.720p.BDRip.x264.AC3-GROUP", Release{
+			Title: "Movie Title", Year: 2019, Resolution: "720p", Source: SourceBluRay, Codec: "x264", Audio: []string{"DD"}, Group: "GROUP",
+		}},
+		{"Movie.Title.2019.1080p.BluRay.Remux.AVC.DTS-HD.MA.5.1-EPSiLON", Release{
+			Title: "Movie Title", Year: 2019, Resolution: "1080p", Source: SourceRemux, Codec: "x264",
+			Audio: []string{"DTS-HD MA"}, Channels: "5.1", Group: "EPSiLON",
+		}},
+		{"Movie.Title.2019.1080p.BluRay.VC-1.DTS-HD.MA.5.1-GROUP", Release{
+			Title: "Movie Title", Year: 2019, Resolution: "1080p", Source: SourceBluRay, Codec: "VC-1",
+			Audio: []string{"DTS-HD MA"}, Channels: "5.1", Group: "GROUP",
+		}},
+		{"Movie.Title.2022.2160p.WEB-DL.DDP5.1.DV.HDR.H.265-GROUP", Release{
+			Title: "Movie Title", Year: 2022, Resolution: "2160p", Source: SourceWEBDL, Codec: "x265",
+			Audio: []string{"DD+"}, Channels: "5.1", HDR: []string{"DV", "HDR"}, Group: "GROUP",
+		}},
+		{"Movie.Title.2022.2160p.UHD.BluRay.x265.10bit.HDR.DTS-X.7.1-GROUP", Release{
+			Title: "Movie Title", Year: 2022, Resolution: "2160p", Source: SourceBluRay, Codec: "x265",
+			Audio: []string{"DTS:X"}, Channels: "7.1", HDR: []string{"HDR"}, Group: "GROUP",
+		}},
+		{"Movie.Title.2022.4K.WEB.x265-GROUP", Release{
+			Title: "Movie Title", Year: 2022, Resolution: "2160p", Source: SourceWEBDL, Codec: "x265", Group: "GROUP",
+		}},
+		{"Movie.Title.2020.1080p.WEB-DL.AV1.Opus.5.1-GROUP", Release{
+			Title: "Movie Title", Year: 2020, Resolution: "1080p", Source: SourceWEBDL, Codec: "AV1",
+			Audio: []string{"Opus"}, Channels: "5.1", Group: "GROUP",
+		}},
+		{"Movie.Title.2019.1080p.BluRay.x264.FLAC.2.0-GROUP", Release{
+			Title: "Movie Title", Year: 2019, Resolution: "1080p", Source: SourceBluRay, Codec: "x264",
+			Audio: []string{"FLAC"}, Channels: "2.0", Group: "GROUP",
+		}},
+		{"Movie.Title.2019.1080p.BluRay.x264.LPCM.2.0-GROUP", Release{
+			Title: "Movie Title", Year: 2019, Resolution: "1080p", Source: SourceBluRay, Codec: "x264",
+			Audio: []string{"PCM"}, Channels: "2.0", Group: "GROUP",
+		}},
+		{"Movie.Title.2019.1080p.WEB-DL.EAC3.5.1.x264-GROUP", Release{
+			Title: "Movie Title", Year: 2019, Resolution: "1080p", Source: SourceWEBDL, Codec: "x264",
+			Audio: []string{"DD+"}, Channels: "5.1", Group: "GROUP",
+		}},
+		{"Movie Title 2019 1080p WEB-DL DD+ 5.1 H.264-GROUP", Release{
+			Title: "Movie Title", Year: 2019, Resolution: "1080p", Source: SourceWEBDL, Codec: "x264",
+			Audio: []string{"DD+"}, Channels: "5.1", Group: "GROUP",
+		}},
+		{"Movie.Title.2019.1080p.BluRay.x264.TrueHD.7.1.Atmos-GROUP", Release{
+			Title: "Movie Title", Year: 2019, Resolution: "1080p", Source: SourceBluRay, Codec: "x264",
+			Audio: []string{"TrueHD", "Atmos"}, Channels: "7.1", Group: "GROUP",
+		}},
+
+		// Editions
+		{"Aliens.1986.Special.Edition.PROPER.1080p.BluRay.x264-GROUP", Release{
+			Title: "Aliens", Year: 1986, Resolution: "1080p", Source: SourceBluRay, Codec: "x264", Group: "GROUP",
+			Proper: true, Edition: "Special Edition",
+		}},
+		{"Movie.2019.Directors.Cut.1080p.BluRay", Release{
+			Title: "Movie", Year: 2019, Resolution: "1080p", Source: SourceBluRay, Edition: "Director's Cut",
+		}},
+		{"Movie.2001.EXTENDED.1080p.BluRay.x264-GROUP", Release{
+			Title: "Movie", Year: 2001, Resolution: "1080p", Source: SourceBluRay, Codec: "x264", Group: "GROUP", Edition: "Extended",
+		}},
+		{"Movie.2001.Extended.Edition.720p.BluRay.x264-GROUP", Release{
+			Title: "Movie", Year: 2001, Resolution: "720p", Source: SourceBluRay, Codec: "x264", Group: "GROUP", Edition: "Extended",
+		}},
+		{"Movie.2004.UNRATED.720p.BluRay.x264-GROUP", Release{
+			Title: "Movie", Year: 2004, Resolution: "720p", Source: SourceBluRay, Codec: "x264", Group: "GROUP", Edition: "Unrated",
+		}},
+		{"Movie.2004.Theatrical.Cut.1080p.BluRay.x264-GROUP", Release{
+			Title: "Movie", Year: 2004, Resolution: "1080p", Source: SourceBluRay, Codec: "x264", Group: "GROUP", Edition: "Theatrical",
+		}},
+		{"Movie.2009.IMAX.2160p.WEB-DL.DDP5.1.H.265-GROUP", Release{
+			Title: "Movie", Year: 2009, Resolution: "2160p", Source: SourceWEBDL, Codec: "x265",
+			Audio: []string{"DD+"}, Channels: "5.1", Group: "GROUP", Edition: "IMAX",
+		}},
+		{"Movie.1982.Final.Cut.1080p.BluRay.x264-GROUP", Release{
+			Title: "Movie", Year: 1982, Resolution: "1080p", Source: SourceBluRay, Codec: "x264", Group: "GROUP", Edition: "Final Cut",
+		}},
+		{"Movie.1985.30th.Anniversary.Edition.1080p.BluRay.x264-GROUP", Release{
+			Title: "Movie", Year: 1985, Resolution: "1080p", Source: SourceBluRay, Codec: "x264", Group: "GROUP", Edition: "Anniversary",
+		}},
+		{"Movie.1960.Criterion.Collection.1080p.BluRay.x264-GROUP", Release{
+			Title: "Movie", Year: 1960, Resolution: "1080p", Source: SourceBluRay, Codec: "x264", Group: "GROUP", Edition: "Criterion",
+		}},
+
+		// Languages
+		{"Le.Fabuleux.Destin.2001.FRENCH.1080p.BluRay.x264-LOST", Release{
+			Title: "Le Fabuleux Destin", Year: 2001, Resolution: "1080p", Source: SourceBluRay, Codec: "x264",
+			Languages: []string{"French"}, Group: "LOST",
+		}},
+		{"Movie.2019.MULTi.1080p.BluRay.x264-LOST", Release{
+			Title: "Movie", Year: 2019, Resolution: "1080p", Source: SourceBluRay, Codec: "x264", Languages: []string{"Multi"}, Group: "LOST",
+		}},
+		{"Movie.2019.TRUEFRENCH.720p.BluRay.x264-GROUP", Release{
+			Title: "Movie", Year: 2019, Resolution: "720p", Source: SourceBluRay, Codec: "x264", Languages: []string{"French"}, Group: "GROUP",
+		}},
+		{"Movie.2019.SPANISH.1080p.WEB-DL.x264-GROUP", Release{
+			Title: "Movie", Year: 2019, Resolution: "1080p", Source: SourceWEBDL, Codec: "x264", Languages: []string{"Spanish"}, Group: "GROUP",
+		}},
+		{"Movie.2019.Japanese.1080p.BluRay.x264-GROUP", Release{
+			Title: "Movie", Year: 2019, Resolution: "1080p", Source: SourceBluRay, Codec: "x264", Languages: []string{"Japanese"}, Group: "GROUP",
+		}},
+		{"Movie.2019.KOREAN.1080p.WEBRip.x264-GROUP", Release{
+			Title: "Movie", Year: 2019, Resolution: "1080p", Source: SourceWEBRip, Codec: "x264", Languages: []string{"Korean"}, Group: "GROUP",
+		}},
+		{"Movie.2019.Hindi.720p.WEB-DL.x264-GROUP", Release{
+			Title: "Movie", Year: 2019, Resolution: "720p", Source: SourceWEBDL, Codec: "x264", Languages: []string{"Hindi"}, Group: "GROUP",
+		}},
+		{"Movie.2019.NORDiC.1080p.WEB-DL.H.264-GROUP", Release{
+			Title: "Movie", Year: 2019, Resolution: "1080p", Source: SourceWEBDL, Codec: "x264", Languages: []string{"Nordic"}, Group: "GROUP",
+		}},
+		{"Movie.2019.PL.1080p.BluRay.x264-GROUP", Release{
+			Title: "Movie", Year: 2019, Resolution: "1080p", Source: SourceBluRay, Codec: "x264", Languages: []string{"Polish"}, Group: "GROUP",
+		}},
+
+		// Low quality sources
+		{"Movie 2019 HDCAM x264-GROUP", Release{Title: "Movie", Year: 2019, Source: SourceCAM, Codec: "x264", Group: "GROUP"}},
+		{"Movie.2019.CAM.XviD-GROUP", Release{Title: "Movie", Year: 2019, Source: SourceCAM, Codec: "XviD", Group: "GROUP"}},
+		{"Movie.2019.HDTS.x264-GROUP", Release{Title: "Movie", Year: 2019, Source: SourceTelesync, Codec: "x264", Group: "GROUP"}},
+		{"Movie.2019.TS.XviD-GROUP", Release{Title: "Movie", Year: 2019, Source: SourceTelesync, Codec: "XviD", Group: "GROUP"}},
+		{"Movie.2019.TC.XviD-GROUP", Release{Title: "Movie", Year: 2019, Source: SourceTelecine, Codec: "XviD", Group: "GROUP"}},
+		{"Movie.2019.DVDSCR.XviD-GROUP", Release{Title: "Movie", Year: 2019, Source: SourceScreener, Codec: "XviD", Group: "GROUP"}},
+		{"Movie.2019.DVD9.NTSC-GROUP", Release{Title: "Movie", Year: 2019, Source: SourceDVD, Group: "GROUP"}},
+		{"Movie.2019.720p.HDTV.x264-GROUP", Release{
+			Title: "Movie", Year: 2019, Resolution: "720p", Source: SourceHDTV, Codec: "x264", Group: "GROUP",
+		}},
+
+		// Anime and other layouts
+		{"[SubsPlease] Frieren - S01E12 (1080p) [ABCD1234].mkv", Release{
+			Title: "Frieren", Seasons: []int{1}, Episodes: []int{12}, Resolution: "1080p", Group: "SubsPlease",
+		}},
+		{"[Erai-raws] Show - S02E05 [720p][Multiple Subtitle]", Release{
+			Title: "Show", Seasons: []int{2}, Episodes: []int{5}, Resolution: "720p", Group: "Erai-raws",
+		}},
+		{"Daft Punk - Discovery (2001) [FLAC]", Release{Title: "Daft Punk - Discovery", Year: 2001, Audio: []string{"FLAC"}}},
+		{"Daft Punk - Discovery (2001) [MP3 320kbps]", Release{Title: "Daft Punk - Discovery", Year: 2001, Audio: []string{"MP3"}}},
+		{"Some Random Upload", Release{Title: "Some Random Upload"}},
+		{"", Release{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			assert.Equal(t, tt.want, Parse(tt.title))
+		})
+	}
+}
+
+func TestParseYearOpeningTitle(t *testing.T) {
+	// A lone year at the start is the name, the release year is unknown
+	assert.Equal(t, 0, Parse("1917.1080p.BluRay").Year)
+	assert.Equal(t, "1917", Parse("1917.1080p.BluRay").Title)
+	assert.Equal(t, 2019, Parse("1917.2019.1080p.BluRay").Year)
+}
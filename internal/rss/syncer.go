@@ -0,0 +1,265 @@
+package rss
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/parser"
+	"vitoru.fun/torrents/internal/search"
+)
+
+const (
+	// seenRetention is how long a release no longer listed by an indexer is
+	// remembered, longer than it may take to drop off its latest page
+	seenRetention = 7 * 24 * time.Hour
+	// minWait keeps the sync loop from spinning on indexers due together
+	minWait = time.Second
+)
+
+var (
+	rssSyncsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "torrent_rss_syncs_total",
+			Help: "Total number of RSS syncs performed per indexer",
+		},
+		[]string{"indexer", "status"},
+	)
+
+	rssReleasesOfferedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "torrent_rss_releases_offered_total",
+			Help: "Total number of new releases the RSS sync offered to monitored requests",
+		},
+		[]string{"indexer"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(rssSyncsTotal, rssReleasesOfferedTotal)
+}
+
+// Source fetches the latest releases of an indexer
+type Source interface {
+	// LatestReleases lists the latest releases, details pages unresolved
+	LatestReleases(ctx context.Context, indexer *torrentsv1alpha1.Indexer) ([]parser.ParseResult, error)
+	// ResolveMagnets resolves the details pages of the releases to magnets
+	ResolveMagnets(ctx context.Context, indexer *torrentsv1alpha1.Indexer, results []parser.ParseResult) []parser.ParseResult
+}
+
+// Sink receives the new releases matching a monitored request
+type Sink interface {
+	Offer(ctx context.Context, request types.NamespacedName, results []parser.ParseResult)
+}
+
+// Syncer fetches the latest releases of every healthy Indexer on an
+// interval and offers the ones it has not seen before to the monitored
+// TorrentRequests they match, so those requests do not have to search
+// themselves. Indexers are only fetched while some request is monitoring.
+type Syncer struct {
+	Reader client.Reader
+	Source Source
+	Sink   Sink
+	// Interval between two syncs of an Indexer without its own rss.interval
+	Interval time.Duration
+
+	// seen holds, per indexer, when each release was last listed
+	seen map[types.NamespacedName]map[string]time.Time
+	// next is when each indexer is synced again
+	next map[types.NamespacedName]time.Time
+}
+
+// Start syncs the indexers until ctx is done, so the syncer can be added to
+// the manager as a Runnable
+func (s *Syncer) Start(ctx context.Context) error {
+	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithName("rss-sync"))
+	log.FromContext(ctx).Info("Starting RSS sync", "interval", s.Interval)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+			timer.Reset(s.syncDue(ctx, time.Now()))
+		}
+	}
+}
+
+// NeedLeaderElection lets only the leader sync, so indexers are fetched
+// once whatever the number of replicas
+func (s *Syncer) NeedLeaderElection() bool {
+	return true
+}
+
+// syncDue syncs the indexers whose interval has passed at now and returns
+// how long to wait before the next one is due
+func (s *Syncer) syncDue(ctx context.Context, now time.Time) time.Duration {
+	l := log.FromContext(ctx)
+	if s.seen == nil {
+		s.seen = map[types.NamespacedName]map[string]time.Time{}
+		s.next = map[types.NamespacedName]time.Time{}
+	}
+
+	requests, err := s.monitoredRequests(ctx)
+	if err != nil {
+		l.Error(err, "Failed to list monitored requests")
+		return s.Interval
+	}
+	if len(requests) == 0 {
+		return s.Interval
+	}
+
+	var indexers torrentsv1alpha1.IndexerList
+	if err := s.Reader.List(ctx, &indexers); err != nil {
+		l.Error(err, "Failed to list indexers")
+		return s.Interval
+	}
+
+	wait := s.Interval
+	synced := map[types.NamespacedName]bool{}
+	for i := range indexers.Items {
+		indexer := &indexers.Items[i]
+		if !apimeta.IsStatusConditionTrue(indexer.Status.Conditions, "Ready") || !Syncable(indexer) {
+			continue
+		}
+		key := client.ObjectKeyFromObject(indexer)
+		synced[key] = true
+		if next, ok := s.next[key]; !ok || !next.After(now) {
+			s.sync(ctx, indexer, requests, now)
+			s.next[key] = now.Add(s.interval(indexer))
+		}
+		wait = min(wait, s.next[key].Sub(now))
+	}
+
+	// Forget the indexers that were deleted or stopped syncing
+	for key := range s.next {
+		if !synced[key] {
+			delete(s.next, key)
+			delete(s.seen, key)
+		}
+	}
+	return max(wait, minWait)
+}
+
+// Syncable reports whether the latest releases of the indexer can be
+// fetched, from its rss.path or by a search without keywords
+func Syncable(indexer *torrentsv1alpha1.Indexer) bool {
+	if indexer.Spec.RSS != nil && indexer.Spec.RSS.Path != "" {
+		return true
+	}
+	return indexer.Spec.Search != nil && indexer.Spec.Search.AllowEmptyInputs
+}
+
+// interval is the delay between two syncs of the indexer
+func (s *Syncer) interval(indexer *torrentsv1alpha1.Indexer) time.Duration {
+	if indexer.Spec.RSS != nil && indexer.Spec.RSS.Interval != nil && indexer.Spec.RSS.Interval.Duration > 0 {
+		return indexer.Spec.RSS.Interval.Duration
+	}
+	return s.Interval
+}
+
+// monitoredRequests lists the monitored requests waiting for a release
+func (s *Syncer) monitoredRequests(ctx context.Context) ([]torrentsv1alpha1.TorrentRequest, error) {
+	var list torrentsv1alpha1.TorrentRequestList
+	if err := s.Reader.List(ctx, &list); err != nil {
+		return nil, err
+	}
+	var requests []torrentsv1alpha1.TorrentRequest
+	for _, tr := range list.Items {
+		if tr.Spec.Mode == torrentsv1alpha1.ModeMonitor && tr.Status.State == "Monitoring" {
+			requests = append(requests, tr)
+		}
+	}
+	return requests, nil
+}
+
+// sync fetches the latest releases of the indexer and offers the new ones to
+// the requests they match
+func (s *Syncer) sync(ctx context.Context, indexer *torrentsv1alpha1.Indexer, requests []torrentsv1alpha1.TorrentRequest, now time.Time) {
+	l := log.FromContext(ctx).WithValues("indexer", indexer.Name)
+
+	releases, err := s.Source.LatestReleases(ctx, indexer)
+	if err != nil {
+		l.Error(err, "Failed to fetch the latest releases")
+		rssSyncsTotal.WithLabelValues(indexer.Name, "failed").Inc()
+		return
+	}
+	rssSyncsTotal.WithLabelValues(indexer.Name, "success").Inc()
+
+	fresh := s.unseen(client.ObjectKeyFromObject(indexer), releases, now)
+	l.V(1).Info("Fetched the latest releases", "releases", len(releases), "new", len(fresh))
+	if len(fresh) == 0 {
+		return
+	}
+
+	for i := range requests {
+		tr := &requests[i]
+		if len(tr.Spec.Indexers) > 0 && !slices.Contains(tr.Spec.Indexers, indexer.Name) {
+			continue
+		}
+		matched := Match(tr, fresh)
+		if len(matched) == 0 {
+			continue
+		}
+		l.Info("Offering new releases", "request", tr.Name, "namespace", tr.Namespace, "releases", len(matched))
+		rssReleasesOfferedTotal.WithLabelValues(indexer.Name).Add(float64(len(matched)))
+		s.Sink.Offer(ctx, client.ObjectKeyFromObject(tr), s.Source.ResolveMagnets(ctx, indexer, matched))
+	}
+}
+
+// unseen records the releases listed by the indexer at now and returns the
+// ones it did not list before. Releases it stopped listing are forgotten
+// after seenRetention.
+func (s *Syncer) unseen(indexer types.NamespacedName, releases []parser.ParseResult, now time.Time) []parser.ParseResult {
+	seen := s.seen[indexer]
+	if seen == nil {
+		seen = map[string]time.Time{}
+		s.seen[indexer] = seen
+	}
+
+	var fresh []parser.ParseResult
+	for _, release := range releases {
+		id := releaseID(release)
+		if _, ok := seen[id]; !ok {
+			fresh = append(fresh, release)
+		}
+		seen[id] = now
+	}
+	for id, last := range seen {
+		if now.Sub(last) > seenRetention {
+			delete(seen, id)
+		}
+	}
+	return fresh
+}
+
+// releaseID identifies a release across syncs by its info hash, falling back
+// to its download link and title
+func releaseID(release parser.ParseResult) string {
+	if hash := parser.InfoHash(release.Magnet); hash != "" {
+		return hash
+	}
+	return release.Magnet + "\n" + release.Title
+}
+
+// Match returns the releases whose title fits the request search
+func Match(tr *torrentsv1alpha1.TorrentRequest, releases []parser.ParseResult) []parser.ParseResult {
+	q := search.RequestQuery(tr)
+	var matched []parser.ParseResult
+	for _, release := range releases {
+		if q.MatchesTerms(release.Title) && q.Matches(release.Title) {
+			matched = append(matched, release)
+		}
+	}
+	return matched
+}
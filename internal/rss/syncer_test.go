@@ -0,0 +1,154 @@
+package rss
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/parser"
+)
+
+// fakeSource returns canned releases per indexer and counts the fetches
+type fakeSource struct {
+	releases map[string][]parser.ParseResult
+	fetches  map[string]int
+}
+
+func (f *fakeSource) LatestReleases(ctx context.Context, indexer *torrentsv1alpha1.Indexer) ([]parser.ParseResult, error) {
+	f.fetches[indexer.Name]++
+	return f.releases[indexer.Name], nil
+}
+
+func (f *fakeSource) ResolveMagnets(ctx context.Context, indexer *torrentsv1alpha1.Indexer, results []parser.ParseResult) []parser.ParseResult {
+	for i := range results {
+		results[i].Indexer = indexer.Name
+	}
+	return results
+}
+
+// fakeSink records the titles offered to each request
+type fakeSink struct {
+	offers map[string][]string
+}
+
+func (f *fakeSink) Offer(ctx context.Context, request types.NamespacedName, results []parser.ParseResult) {
+	for _, r := range results {
+		f.offers[request.Name] = append(f.offers[request.Name], r.Title)
+	}
+}
+
+func release(title, hash string) parser.ParseResult {
+	return parser.ParseResult{Title: title, Magnet: "magnet:?xt=urn:btih:" + hash}
+}
+
+func newIndexer(name string, rss *torrentsv1alpha1.RSSSync) *torrentsv1alpha1.Indexer {
+	return &torrentsv1alpha1.Indexer{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "media"},
+		Spec: torrentsv1alpha1.IndexerSpec{
+			RSS:    rss,
+			Search: &torrentsv1alpha1.Search{AllowEmptyInputs: true},
+		},
+		Status: torrentsv1alpha1.IndexerStatus{
+			Conditions: []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Test"}},
+		},
+	}
+}
+
+func newRequest(name, state string, spec torrentsv1alpha1.TorrentRequestSpec) *torrentsv1alpha1.TorrentRequest {
+	spec.Mode = torrentsv1alpha1.ModeMonitor
+	return &torrentsv1alpha1.TorrentRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "media"},
+		Spec:       spec,
+		Status:     torrentsv1alpha1.TorrentRequestStatus{State: state},
+	}
+}
+
+func newSyncer(t *testing.T, source *fakeSource, sink *fakeSink, objs ...runtime.Object) *Syncer {
+	scheme := runtime.NewScheme()
+	require.NoError(t, torrentsv1alpha1.AddToScheme(scheme))
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build()
+	return &Syncer{Reader: reader, Source: source, Sink: sink, Interval: 15 * time.Minute}
+}
+
+func TestSyncOffersNewMatchingReleases(t *testing.T) {
+	ctx := context.Background()
+	source := &fakeSource{
+		releases: map[string][]parser.ParseResult{
+			"tracker": {
+				release("The.Show.S01E02.1080p.WEB-DL-NTb", "a"),
+				release("The.Show.S01E03.1080p.WEB-DL-NTb", "b"),
+				release("Other.Movie.2020.1080p", "c"),
+			},
+		},
+		fetches: map[string]int{},
+	}
+	sink := &fakeSink{offers: map[string][]string{}}
+	tv := torrentsv1alpha1.TorrentRequestSpec{TV: &torrentsv1alpha1.TVQuery{Series: "The Show", Season: 1, Episode: 2}}
+	s := newSyncer(t, source, sink,
+		newIndexer("tracker", nil),
+		newRequest("episode", "Monitoring", tv),
+		newRequest("done", "Completed", tv),
+		newRequest("elsewhere", "Monitoring", torrentsv1alpha1.TorrentRequestSpec{Keywords: "the show", Indexers: []string{"other"}}),
+		newRequest("movie", "Monitoring", torrentsv1alpha1.TorrentRequestSpec{Movie: &torrentsv1alpha1.MovieQuery{Title: "Other Movie", Year: 2020}}),
+	)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 15*time.Minute, s.syncDue(ctx, now))
+	assert.Equal(t, map[string][]string{
+		"episode": {"The.Show.S01E02.1080p.WEB-DL-NTb"},
+		"movie":   {"Other.Movie.2020.1080p"},
+	}, sink.offers)
+
+	// Not due yet
+	assert.Equal(t, 5*time.Minute, s.syncDue(ctx, now.Add(10*time.Minute)))
+	assert.Equal(t, 1, source.fetches["tracker"])
+
+	// Only releases not listed before are offered
+	source.releases["tracker"] = append(source.releases["tracker"], release("The.Show.S01E02.2160p.WEB-DL-NTb", "d"))
+	sink.offers = map[string][]string{}
+	s.syncDue(ctx, now.Add(15*time.Minute))
+	assert.Equal(t, 2, source.fetches["tracker"])
+	assert.Equal(t, map[string][]string{"episode": {"The.Show.S01E02.2160p.WEB-DL-NTb"}}, sink.offers)
+}
+
+func TestSyncIntervals(t *testing.T) {
+	ctx := context.Background()
+	source := &fakeSource{fetches: map[string]int{}}
+	sink := &fakeSink{offers: map[string][]string{}}
+	fast := newIndexer("fast", &torrentsv1alpha1.RSSSync{Interval: &metav1.Duration{Duration: 5 * time.Minute}})
+	unsyncable := newIndexer("unsyncable", nil)
+	unsyncable.Spec.Search.AllowEmptyInputs = false
+	s := newSyncer(t, source, sink, fast, newIndexer("slow", nil), unsyncable,
+		newRequest("show", "Monitoring", torrentsv1alpha1.TorrentRequestSpec{Keywords: "show"}))
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 5*time.Minute, s.syncDue(ctx, now))
+	assert.Equal(t, 5*time.Minute, s.syncDue(ctx, now.Add(5*time.Minute)))
+	assert.Equal(t, map[string]int{"fast": 2, "slow": 1}, source.fetches)
+}
+
+func TestSyncWithoutMonitoredRequests(t *testing.T) {
+	ctx := context.Background()
+	source := &fakeSource{fetches: map[string]int{}}
+	s := newSyncer(t, source, &fakeSink{offers: map[string][]string{}},
+		newIndexer("tracker", nil),
+		newRequest("done", "Completed", torrentsv1alpha1.TorrentRequestSpec{Keywords: "show"}))
+
+	assert.Equal(t, 15*time.Minute, s.syncDue(ctx, time.Now()))
+	assert.Empty(t, source.fetches)
+}
+
+func TestSyncable(t *testing.T) {
+	assert.True(t, Syncable(newIndexer("search", nil)))
+	assert.True(t, Syncable(&torrentsv1alpha1.Indexer{Spec: torrentsv1alpha1.IndexerSpec{RSS: &torrentsv1alpha1.RSSSync{Path: "/rss"}}}))
+	assert.False(t, Syncable(&torrentsv1alpha1.Indexer{Spec: torrentsv1alpha1.IndexerSpec{Search: &torrentsv1alpha1.Search{}}}))
+	assert.False(t, Syncable(&torrentsv1alpha1.Indexer{}))
+}
@@ -0,0 +1,169 @@
+package search
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+)
+
+// Category is a standard Newznab category
+type Category struct {
+	ID   int
+	Name string
+}
+
+// Parent returns the top level category ID, e.g. 2000 for 2040
+func (c Category) Parent() int {
+	return c.ID / 1000 * 1000
+}
+
+// Categories lists the standard Newznab categories
+var Categories = []Category{
+	{1000, "Console"},
+	{1010, "Console/NDS"},
+	{1020, "Console/PSP"},
+	{1030, "Console/Wii"},
+	{1040, "Console/XBox"},
+	{1050, "Console/XBox 360"},
+	{1060, "Console/Wiiware"},
+	{1070, "Console/XBox 360 DLC"},
+	{1080, "Console/PS3"},
+	{1090, "Console/Other"},
+	{1110, "Console/3DS"},
+	{1120, "Console/PS Vita"},
+	{1130, "Console/WiiU"},
+	{1140, "Console/XBox One"},
+	{1180, "Console/PS4"},
+	{2000, "Movies"},
+	{2010, "Movies/Foreign"},
+	{2020, "Movies/Other"},
+	{2030, "Movies/SD"},
+	{2040, "Movies/HD"},
+	{2045, "Movies/UHD"},
+	{2050, "Movies/BluRay"},
+	{2060, "Movies/3D"},
+	{2070, "Movies/DVD"},
+	{2080, "Movies/WEB-DL"},
+	{3000, "Audio"},
+	{3010, "Audio/MP3"},
+	{3020, "Audio/Video"},
+	{3030, "Audio/Audiobook"},
+	{3040, "Audio/Lossless"},
+	{3050, "Audio/Other"},
+	{3060, "Audio/Foreign"},
+	{4000, "PC"},
+	{4010, "PC/0day"},
+	{4020, "PC/ISO"},
+	{4030, "PC/Mac"},
+	{4040, "PC/Mobile-Other"},
+	{4050, "PC/Games"},
+	{4060, "PC/Mobile-iOS"},
+	{4070, "PC/Mobile-Android"},
+	{5000, "TV"},
+	{5010, "TV/WEB-DL"},
+	{5020, "TV/Foreign"},
+	{5030, "TV/SD"},
+	{5040, "TV/HD"},
+	{5045, "TV/UHD"},
+	{5050, "TV/Other"},
+	{5060, "TV/Sport"},
+	{5070, "TV/Anime"},
+	{5080, "TV/Documentary"},
+	{6000, "XXX"},
+	{6010, "XXX/DVD"},
+	{6020, "XXX/WMV"},
+	{6030, "XXX/XviD"},
+	{6040, "XXX/x264"},
+	{6045, "XXX/UHD"},
+	{6050, "XXX/Pack"},
+	{6060, "XXX/ImageSet"},
+	{6070, "XXX/Other"},
+	{6080, "XXX/SD"},
+	{6090, "XXX/WEB-DL"},
+	{7000, "Books"},
+	{7010, "Books/Mags"},
+	{7020, "Books/EBook"},
+	{7030, "Books/Comics"},
+	{7040, "Books/Technical"},
+	{7050, "Books/Other"},
+	{7060, "Books/Foreign"},
+	{8000, "Other"},
+	{8010, "Other/Misc"},
+	{8020, "Other/Hashed"},
+}
+
+// OtherCategory is reported for results of indexers without categories
+const OtherCategory = 8000
+
+// CategoryByName looks a standard category up by its Newznab name or ID,
+// as indexer definitions use either
+func CategoryByName(name string) (Category, bool) {
+	name = strings.TrimSpace(name)
+	id, err := strconv.Atoi(name)
+	for _, c := range Categories {
+		if strings.EqualFold(c.Name, name) || (err == nil && c.ID == id) {
+			return c, true
+		}
+	}
+	return Category{}, false
+}
+
+// categoryMappings returns the tracker category to Newznab category pairs
+// declared by the indexer, from both categorymappings and categories
+func categoryMappings(indexer *torrentsv1alpha1.Indexer) []torrentsv1alpha1.CategoryMapping {
+	caps := indexer.Spec.Caps
+	mappings := append([]torrentsv1alpha1.CategoryMapping(nil), caps.CategoryMappings...)
+
+	ids := make([]string, 0, len(caps.Categories))
+	for id := range caps.Categories {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		mappings = append(mappings, torrentsv1alpha1.CategoryMapping{ID: id, Cat: caps.Categories[id]})
+	}
+	return mappings
+}
+
+// IndexerCategories returns the Newznab categories the indexer maps its
+// tracker categories to, sorted by ID
+func IndexerCategories(indexer *torrentsv1alpha1.Indexer) []Category {
+	seen := map[int]bool{}
+	var cats []Category
+	for _, m := range categoryMappings(indexer) {
+		c, ok := CategoryByName(m.Cat)
+		if !ok || seen[c.ID] {
+			continue
+		}
+		seen[c.ID] = true
+		cats = append(cats, c)
+	}
+	sort.Slice(cats, func(i, j int) bool { return cats[i].ID < cats[j].ID })
+	return cats
+}
+
+// TrackerCategories maps Newznab category IDs to the tracker categories of
+// the indexer. Requesting a top level category selects its subcategories too.
+func TrackerCategories(indexer *torrentsv1alpha1.Indexer, ids []int) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+	var tracker []string
+	seen := map[string]bool{}
+	for _, m := range categoryMappings(indexer) {
+		c, ok := CategoryByName(m.Cat)
+		if !ok || seen[m.ID] {
+			continue
+		}
+		for _, id := range ids {
+			if c.ID == id || (id%1000 == 0 && c.Parent() == id) {
+				seen[m.ID] = true
+				tracker = append(tracker, m.ID)
+				break
+			}
+		}
+	}
+	return tracker
+}
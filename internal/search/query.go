@@ -0,0 +1,284 @@
+package search
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/releaseparse"
+)
+
+// Mode is the kind of search a query runs, named after the indexer
+// capabilities that advertise it
+type Mode string
+
+const (
+	ModeSearch Mode = "search"
+	ModeTV     Mode = "tv-search"
+	ModeMovie  Mode = "movie-search"
+	ModeMusic  Mode = "music-search"
+	ModeBook   Mode = "book-search"
+)
+
+// Query is a search run against an indexer, whether it comes from a
+// TorrentRequest or from a Torznab client
+type Query struct {
+	Mode     Mode
+	Keywords string
+	// Categories restricts the search to these Newznab category IDs
+	Categories []int
+
+	// Season and Episode narrow TV searches, zero when not requested
+	Season  int
+	Episode int
+	// SeasonPack drops single episodes of season searches
+	SeasonPack bool
+	// TVDBID identifies the series on TheTVDB
+	TVDBID int
+	// IMDBID identifies the series or movie on IMDb, e.g. "tt0944947"
+	IMDBID string
+	// TMDBID identifies the movie on TheMovieDB
+	TMDBID int
+	// Year the movie or album was released, zero when not requested
+	Year int
+
+	// Artist, Album and Label narrow music searches
+	Artist string
+	Album  string
+	Label  string
+	// Author and Title narrow book searches
+	Author string
+	Title  string
+}
+
+// Supports reports whether the indexer advertises the search mode. Plain
+// keyword search is assumed to be available on every indexer.
+func Supports(indexer *torrentsv1alpha1.Indexer, mode Mode) bool {
+	return mode == ModeSearch || mode == "" || len(modeParams(indexer, mode)) > 0
+}
+
+// modeParams returns the query parameters the indexer supports in the mode
+func modeParams(indexer *torrentsv1alpha1.Indexer, mode Mode) []string {
+	modes := indexer.Spec.Caps.Modes
+	switch mode {
+	case ModeSearch, "":
+		return modes.Search
+	case ModeTV:
+		return modes.TvSearch
+	case ModeMovie:
+		return modes.MovieSearch
+	case ModeMusic:
+		return modes.MusicSearch
+	case ModeBook:
+		return modes.BookSearch
+	}
+	return nil
+}
+
+// ForIndexer adapts the query to what the indexer supports. Structured
+// parameters the indexer does not accept are dropped, and when the mode or
+// its season and episode parameters are not advertised the query falls back
+// to a keyword search such as "Show S01E02".
+func (q Query) ForIndexer(indexer *torrentsv1alpha1.Indexer) Query {
+	if q.Mode == ModeSearch || q.Mode == "" {
+		return q
+	}
+
+	params := modeParams(indexer, q.Mode)
+	if len(params) == 0 ||
+		(q.Season > 0 && !slices.Contains(params, "season")) ||
+		(q.Episode > 0 && !slices.Contains(params, "ep")) ||
+		(q.Artist != "" && !slices.Contains(params, "artist")) ||
+		(q.Album != "" && !slices.Contains(params, "album")) ||
+		(q.Author != "" && !slices.Contains(params, "author")) ||
+		(q.Title != "" && !slices.Contains(params, "title")) {
+		return Query{Mode: ModeSearch, Keywords: q.SynthesizeKeywords(), Categories: q.Categories}
+	}
+
+	if !slices.Contains(params, "tvdbid") {
+		q.TVDBID = 0
+	}
+	if !slices.Contains(params, "imdbid") {
+		q.IMDBID = ""
+	}
+	if !slices.Contains(params, "tmdbid") {
+		q.TMDBID = 0
+	}
+	if !slices.Contains(params, "year") {
+		q.Year = 0
+	}
+	if !slices.Contains(params, "label") {
+		q.Label = ""
+	}
+	return q
+}
+
+// SearchTerm is the free text of the query, combining the keywords with the
+// artist and album of music searches or the author and title of book
+// searches
+func (q Query) SearchTerm() string {
+	parts := []string{q.Keywords}
+	switch q.Mode {
+	case ModeMusic:
+		parts = append(parts, q.Artist, q.Album)
+	case ModeBook:
+		parts = append(parts, q.Author, q.Title)
+	}
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
+
+// SynthesizeKeywords folds the structured parameters into free text for
+// indexers that only support keyword search
+func (q Query) SynthesizeKeywords() string {
+	keywords := q.TemplateKeywords()
+	switch q.Mode {
+	case ModeMovie, ModeMusic:
+		if q.Year > 0 {
+			keywords += fmt.Sprintf(" %d", q.Year)
+		}
+	}
+	return strings.TrimSpace(keywords)
+}
+
+// TemplateKeywords is the free text definitions see as .Keywords. Like
+// Jackett, TV searches append the episode, e.g. "Show S01E02", since most
+// definitions only search by keywords even when they advertise season and
+// episode parameters.
+func (q Query) TemplateKeywords() string {
+	if episode := q.episode(); q.Mode == ModeTV && episode != "" {
+		return q.SearchTerm() + " " + episode
+	}
+	return q.SearchTerm()
+}
+
+// episode is the season and episode of TV searches, e.g. "S01E02" or "S01"
+func (q Query) episode() string {
+	switch {
+	case q.Season > 0 && q.Episode > 0:
+		return fmt.Sprintf("S%02dE%02d", q.Season, q.Episode)
+	case q.Season > 0:
+		return fmt.Sprintf("S%02d", q.Season)
+	}
+	return ""
+}
+
+// TemplateValues exposes the query to indexer definition templates as .Query
+func (q Query) TemplateValues() map[string]string {
+	values := map[string]string{
+		"Type":        string(q.Mode),
+		"Q":           q.Keywords,
+		"Keywords":    q.TemplateKeywords(),
+		"Season":      itoa(q.Season),
+		"Ep":          itoa(q.Episode),
+		"Episode":     q.episode(),
+		"TVDBID":      itoa(q.TVDBID),
+		"IMDBID":      q.IMDBID,
+		"IMDBIDShort": strings.TrimPrefix(q.IMDBID, "tt"),
+		"TMDBID":      itoa(q.TMDBID),
+		"Year":        itoa(q.Year),
+		"Artist":      q.Artist,
+		"Album":       q.Album,
+		"Label":       q.Label,
+		"Author":      q.Author,
+		"Title":       q.Title,
+	}
+	return values
+}
+
+// EscapedTemplateValues are the TemplateValues escaped for the URLs of GET
+// search paths. POST searches send the raw values in their form.
+func (q Query) EscapedTemplateValues() map[string]string {
+	values := q.TemplateValues()
+	for k, v := range values {
+		values[k] = url.QueryEscape(v)
+	}
+	return values
+}
+
+// Matches reports whether a result title fits the structured parameters,
+// so releases of other seasons, episodes or same-named movies and albums are
+// not picked
+func (q Query) Matches(title string) bool {
+	release := releaseparse.Parse(title)
+	// Titles without a year cannot be told apart from the right movie
+	if q.Year > 0 && release.Year > 0 && release.Year != q.Year {
+		return false
+	}
+	if q.Season == 0 {
+		return true
+	}
+	if !slices.Contains(release.Seasons, q.Season) {
+		return false
+	}
+	if q.Episode == 0 {
+		return !q.SeasonPack || len(release.Episodes) == 0
+	}
+	return slices.Contains(release.Episodes, q.Episode)
+}
+
+// MatchesTerms reports whether every word of the search term appears in a
+// result title, for results that were not searched with the query, such as
+// the latest releases of an indexer
+func (q Query) MatchesTerms(title string) bool {
+	words := termWords(title)
+	for _, w := range termWords(q.SearchTerm()) {
+		if !slices.Contains(words, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// termWords splits text into lowercase words, dots, dashes and other
+// separators of release titles included
+func termWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// RequestQuery builds the query described by the TorrentRequest spec
+func RequestQuery(tr *torrentsv1alpha1.TorrentRequest) Query {
+	q := Query{Mode: ModeSearch, Keywords: tr.Spec.Keywords}
+	if tv := tr.Spec.TV; tv != nil {
+		q.Mode = ModeTV
+		q.Keywords = tv.Series
+		q.Season = tv.Season
+		q.Episode = tv.Episode
+		q.SeasonPack = tv.SeasonPack
+		q.TVDBID = tv.TVDBID
+		q.IMDBID = tv.IMDBID
+	}
+	if movie := tr.Spec.Movie; movie != nil {
+		q.Mode = ModeMovie
+		q.Keywords = movie.Title
+		q.Year = movie.Year
+		q.IMDBID = movie.IMDBID
+		q.TMDBID = movie.TMDBID
+	}
+	if music := tr.Spec.Music; music != nil {
+		q.Mode = ModeMusic
+		q.Artist = music.Artist
+		q.Album = music.Album
+		q.Label = music.Label
+		q.Year = music.Year
+	}
+	if book := tr.Spec.Book; book != nil {
+		q.Mode = ModeBook
+		q.Author = book.Author
+		q.Title = book.Title
+	}
+	return q
+}
+
+// itoa formats n, leaving unset values empty so templates can test them
+func itoa(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
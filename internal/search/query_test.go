@@ -0,0 +1,187 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+)
+
+func indexerWithModes(modes torrentsv1alpha1.Modes) *torrentsv1alpha1.Indexer {
+	return &torrentsv1alpha1.Indexer{Spec: torrentsv1alpha1.IndexerSpec{Caps: torrentsv1alpha1.Caps{Modes: modes}}}
+}
+
+func TestForIndexer(t *testing.T) {
+	q := Query{Mode: ModeTV, Keywords: "Show", Season: 1, Episode: 2, TVDBID: 121361, IMDBID: "tt0944947", Categories: []int{5000}}
+
+	tests := []struct {
+		name     string
+		modes    torrentsv1alpha1.Modes
+		expected Query
+	}{
+		{
+			name:     "full tv-search support",
+			modes:    torrentsv1alpha1.Modes{TvSearch: []string{"q", "season", "ep", "imdbid", "tvdbid"}},
+			expected: q,
+		},
+		{
+			name:     "unsupported ids are dropped",
+			modes:    torrentsv1alpha1.Modes{TvSearch: []string{"q", "season", "ep"}},
+			expected: Query{Mode: ModeTV, Keywords: "Show", Season: 1, Episode: 2, Categories: []int{5000}},
+		},
+		{
+			name:     "no episode param falls back to keywords",
+			modes:    torrentsv1alpha1.Modes{TvSearch: []string{"q", "season"}},
+			expected: Query{Mode: ModeSearch, Keywords: "Show S01E02", Categories: []int{5000}},
+		},
+		{
+			name:     "no tv-search falls back to keywords",
+			modes:    torrentsv1alpha1.Modes{Search: []string{"q"}},
+			expected: Query{Mode: ModeSearch, Keywords: "Show S01E02", Categories: []int{5000}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, q.ForIndexer(indexerWithModes(tt.modes)))
+		})
+	}
+}
+
+func TestSynthesizeKeywords(t *testing.T) {
+	assert.Equal(t, "Show S01E02", Query{Mode: ModeTV, Keywords: "Show", Season: 1, Episode: 2}.SynthesizeKeywords())
+	assert.Equal(t, "Show S03", Query{Mode: ModeTV, Keywords: "Show", Season: 3}.SynthesizeKeywords())
+	assert.Equal(t, "Show", Query{Mode: ModeTV, Keywords: "Show"}.SynthesizeKeywords())
+}
+
+func TestTemplateValues(t *testing.T) {
+	values := Query{Mode: ModeTV, Keywords: "Show", Season: 1, Episode: 2, IMDBID: "tt0944947"}.TemplateValues()
+	assert.Equal(t, "1", values["Season"])
+	assert.Equal(t, "2", values["Ep"])
+	assert.Equal(t, "S01E02", values["Episode"])
+	assert.Equal(t, "0944947", values["IMDBIDShort"])
+	assert.Equal(t, "", values["TVDBID"])
+}
+
+func TestTemplateValuesTVSearch(t *testing.T) {
+	indexer := indexerWithModes(torrentsv1alpha1.Modes{TvSearch: []string{"q", "season", "ep"}})
+
+	q := Query{Mode: ModeTV, Keywords: "Show", Season: 1, Episode: 2}.ForIndexer(indexer)
+	assert.Equal(t, ModeTV, q.Mode)
+	values := q.TemplateValues()
+	assert.Equal(t, "Show S01E02", values["Keywords"])
+	assert.Equal(t, "Show", values["Q"])
+	assert.Equal(t, "S01E02", values["Episode"])
+	assert.Equal(t, "Show S01E02", q.TemplateKeywords())
+
+	values = Query{Mode: ModeTV, Keywords: "Show", Season: 3}.ForIndexer(indexer).TemplateValues()
+	assert.Equal(t, "Show S03", values["Keywords"])
+	assert.Equal(t, "Show", Query{Mode: ModeTV, Keywords: "Show"}.TemplateKeywords())
+}
+
+func TestEscapedTemplateValues(t *testing.T) {
+	q := Query{Mode: ModeMusic, Keywords: "live", Artist: "AC/DC & Friends", Album: "Back in Black"}
+	values := q.EscapedTemplateValues()
+	assert.Equal(t, "AC%2FDC+%26+Friends", values["Artist"])
+	assert.Equal(t, "Back+in+Black", values["Album"])
+	assert.Equal(t, "live+AC%2FDC+%26+Friends+Back+in+Black", values["Keywords"])
+	assert.Equal(t, "live", values["Q"])
+	assert.Equal(t, "AC/DC & Friends", q.TemplateValues()["Artist"])
+}
+
+func TestMatches(t *testing.T) {
+	episode := Query{Mode: ModeTV, Keywords: "Show", Season: 1, Episode: 2}
+	assert.True(t, episode.Matches("Show.S01E02.1080p"))
+	assert.True(t, episode.Matches("Show.S01E01-E03.1080p"))
+	assert.False(t, episode.Matches("Show.S01E03.1080p"))
+	assert.False(t, episode.Matches("Show.S02E02.1080p"))
+	assert.False(t, episode.Matches("Show.S01.COMPLETE"))
+	assert.False(t, episode.Matches("Show 1080p"))
+
+	season := Query{Mode: ModeTV, Keywords: "Show", Season: 1}
+	assert.True(t, season.Matches("Show.S01.COMPLETE"))
+	assert.True(t, season.Matches("Show.S01E05.720p"))
+	assert.True(t, season.Matches("Show.S01-S03.1080p"))
+	assert.False(t, season.Matches("Show.S02.COMPLETE"))
+
+	pack := Query{Mode: ModeTV, Keywords: "Show", Season: 1, SeasonPack: true}
+	assert.True(t, pack.Matches("Show.S01.COMPLETE"))
+	assert.True(t, pack.Matches("Show.S01-S03.1080p"))
+	assert.False(t, pack.Matches("Show.S01E05.720p"))
+
+	assert.True(t, Query{Keywords: "anything"}.Matches("Show 1080p"))
+}
+
+func TestMovieForIndexer(t *testing.T) {
+	q := Query{Mode: ModeMovie, Keywords: "The Matrix", Year: 1999, IMDBID: "tt0133093", TMDBID: 603}
+
+	full := q.ForIndexer(indexerWithModes(torrentsv1alpha1.Modes{MovieSearch: []string{"q", "imdbid", "tmdbid", "year"}}))
+	assert.Equal(t, q, full)
+
+	imdbOnly := q.ForIndexer(indexerWithModes(torrentsv1alpha1.Modes{MovieSearch: []string{"q", "imdbid"}}))
+	assert.Equal(t, Query{Mode: ModeMovie, Keywords: "The Matrix", IMDBID: "tt0133093"}, imdbOnly)
+
+	keywords := q.ForIndexer(indexerWithModes(torrentsv1alpha1.Modes{Search: []string{"q"}}))
+	assert.Equal(t, Query{Mode: ModeSearch, Keywords: "The Matrix 1999"}, keywords)
+
+	values := q.TemplateValues()
+	assert.Equal(t, "tt0133093", values["IMDBID"])
+	assert.Equal(t, "603", values["TMDBID"])
+	assert.Equal(t, "1999", values["Year"])
+}
+
+func TestMatchesYear(t *testing.T) {
+	q := Query{Mode: ModeMovie, Keywords: "Dune", Year: 2021}
+	assert.True(t, q.Matches("Dune.2021.2160p.WEB-DL"))
+	assert.True(t, q.Matches("Dune 2160p WEB-DL"))
+	assert.False(t, q.Matches("Dune.1984.1080p.BluRay"))
+
+	odyssey := Query{Mode: ModeMovie, Keywords: "2001 A Space Odyssey", Year: 1968}
+	assert.True(t, odyssey.Matches("2001.A.Space.Odyssey.1968.1080p"))
+}
+
+func TestMusicAndBookForIndexer(t *testing.T) {
+	music := Query{Mode: ModeMusic, Artist: "Daft Punk", Album: "Discovery", Label: "Virgin", Year: 2001}
+	assert.Equal(t, "Daft Punk Discovery", music.SearchTerm())
+
+	supported := music.ForIndexer(indexerWithModes(torrentsv1alpha1.Modes{MusicSearch: []string{"q", "artist", "album"}}))
+	assert.Equal(t, Query{Mode: ModeMusic, Artist: "Daft Punk", Album: "Discovery"}, supported)
+	values := supported.TemplateValues()
+	assert.Equal(t, "Daft Punk", values["Artist"])
+	assert.Equal(t, "Discovery", values["Album"])
+	assert.Equal(t, "Daft Punk Discovery", values["Keywords"])
+
+	fallback := music.ForIndexer(indexerWithModes(torrentsv1alpha1.Modes{MusicSearch: []string{"q"}}))
+	assert.Equal(t, Query{Mode: ModeSearch, Keywords: "Daft Punk Discovery 2001"}, fallback)
+
+	book := Query{Mode: ModeBook, Author: "Frank Herbert", Title: "Dune"}
+	assert.Equal(t, book, book.ForIndexer(indexerWithModes(torrentsv1alpha1.Modes{BookSearch: []string{"q", "author", "title"}})))
+	assert.Equal(t, Query{Mode: ModeSearch, Keywords: "Frank Herbert Dune"},
+		book.ForIndexer(indexerWithModes(torrentsv1alpha1.Modes{Search: []string{"q"}})))
+	assert.Equal(t, "Frank Herbert", book.TemplateValues()["Author"])
+}
+
+func TestMatchesTerms(t *testing.T) {
+	q := Query{Mode: ModeMovie, Keywords: "The Matrix"}
+	assert.True(t, q.MatchesTerms("The.Matrix.1999.1080p.BluRay"))
+	assert.True(t, q.MatchesTerms("the matrix 1999"))
+	assert.False(t, q.MatchesTerms("Matrix.Resurrections.2021"))
+	assert.False(t, q.MatchesTerms("Matrixx.1999"))
+
+	music := Query{Mode: ModeMusic, Artist: "Daft Punk", Album: "Discovery"}
+	assert.True(t, music.MatchesTerms("Daft Punk - Discovery (2001) [FLAC]"))
+	assert.False(t, music.MatchesTerms("Daft Punk - Homework (1997) [FLAC]"))
+
+	assert.True(t, Query{}.MatchesTerms("Anything.1080p"))
+}
+
+func TestRequestQuery(t *testing.T) {
+	tv := RequestQuery(&torrentsv1alpha1.TorrentRequest{Spec: torrentsv1alpha1.TorrentRequestSpec{
+		TV: &torrentsv1alpha1.TVQuery{Series: "The Show", Season: 2, Episode: 1},
+	}})
+	assert.Equal(t, Query{Mode: ModeTV, Keywords: "The Show", Season: 2, Episode: 1}, tv)
+
+	keywords := RequestQuery(&torrentsv1alpha1.TorrentRequest{Spec: torrentsv1alpha1.TorrentRequestSpec{Keywords: "ubuntu"}})
+	assert.Equal(t, Query{Mode: ModeSearch, Keywords: "ubuntu"}, keywords)
+}
@@ -0,0 +1,218 @@
+package selection
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+)
+
+// maxExpressionCost bounds the work a single evaluation may do, so a request
+// cannot stall the controller
+const maxExpressionCost = 100000
+
+// Expression is a compiled CEL expression evaluated against candidates.
+// Candidates are described by these variables:
+//
+//	title        string
+//	sizeBytes    int, 0 when unknown
+//	seeders      int
+//	leechers     int
+//	indexer      string
+//	publishedAt  timestamp, 0001-01-01 when unknown
+//	quality      string, e.g. "WEB-DL 1080p"
+//	formats      list(string), the matched custom formats
+//	formatScore  int
+//	wordScore    int, the weight of the preferred words in the title
+//	release      map of the parsed release attributes: title, year, seasons,
+//	             episodes, fullSeason, resolution, source, codec, audio,
+//	             channels, hdr, group, proper, repack, languages, edition
+type Expression struct {
+	Source  string
+	program cel.Program
+}
+
+var celEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("title", cel.StringType),
+		cel.Variable("sizeBytes", cel.IntType),
+		cel.Variable("seeders", cel.IntType),
+		cel.Variable("leechers", cel.IntType),
+		cel.Variable("indexer", cel.StringType),
+		cel.Variable("publishedAt", cel.TimestampType),
+		cel.Variable("quality", cel.StringType),
+		cel.Variable("formats", cel.ListType(cel.StringType)),
+		cel.Variable("formatScore", cel.IntType),
+		cel.Variable("wordScore", cel.IntType),
+		cel.Variable("release", cel.MapType(cel.StringType, cel.DynType)),
+	)
+})
+
+// CompileFilter compiles an expression deciding whether a candidate is
+// acceptable. It must evaluate to a bool.
+func CompileFilter(source string) (*Expression, error) {
+	return compile(source, "bool", cel.BoolType)
+}
+
+// CompileSortBy compiles an expression ranking candidates, higher values
+// first. It must evaluate to a number.
+func CompileSortBy(source string) (*Expression, error) {
+	return compile(source, "a number", cel.IntType, cel.UintType, cel.DoubleType)
+}
+
+// compile checks the expression against the candidate variables and that it
+// returns one of the outputs, described by want
+func compile(source, want string, outputs ...*cel.Type) (*Expression, error) {
+	env, err := celEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := env.Compile(source)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+
+	out := ast.OutputType()
+	if !cel.DynType.IsExactType(out) && !slices.ContainsFunc(outputs, out.IsExactType) {
+		return nil, fmt.Errorf("expression returns %s, expected %s", out, want)
+	}
+
+	program, err := env.Program(ast, cel.CostLimit(maxExpressionCost))
+	if err != nil {
+		return nil, err
+	}
+	return &Expression{Source: source, program: program}, nil
+}
+
+// Match evaluates a filter expression against the candidate
+func (e *Expression) Match(c *Candidate) (bool, error) {
+	out, _, err := e.program.Eval(variables(c))
+	if err != nil {
+		return false, err
+	}
+	match, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("filter returned %s, expected bool", out.Type())
+	}
+	return match, nil
+}
+
+// Key evaluates a sort expression against the candidate
+func (e *Expression) Key(c *Candidate) (float64, error) {
+	out, _, err := e.program.Eval(variables(c))
+	if err != nil {
+		return 0, err
+	}
+	switch v := out.Value().(type) {
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	}
+	return 0, fmt.Errorf("sortBy returned %s, expected a number", out.Type())
+}
+
+// variables describes the candidate to expressions
+func variables(c *Candidate) map[string]any {
+	var published time.Time
+	if c.PublishedAt != nil {
+		published = *c.PublishedAt
+	}
+	r := c.Release
+	return map[string]any{
+		"title":       c.Title,
+		"sizeBytes":   c.SizeBytes,
+		"seeders":     c.Seeders,
+		"leechers":    c.Leechers,
+		"indexer":     c.Indexer,
+		"publishedAt": published,
+		"quality":     c.Quality,
+		"formats":     nonNil(c.Formats),
+		"formatScore": c.FormatScore,
+		"wordScore":   c.WordScore,
+		"release": map[string]any{
+			"title":      r.Title,
+			"year":       r.Year,
+			"seasons":    nonNil(r.Seasons),
+			"episodes":   nonNil(r.Episodes),
+			"fullSeason": r.FullSeason,
+			"resolution": r.Resolution,
+			"source":     r.Source,
+			"codec":      r.Codec,
+			"audio":      nonNil(r.Audio),
+			"channels":   r.Channels,
+			"hdr":        nonNil(r.HDR),
+			"group":      r.Group,
+			"proper":     r.Proper,
+			"repack":     r.Repack,
+			"languages":  nonNil(r.Languages),
+			"edition":    r.Edition,
+		},
+	}
+}
+
+// nonNil lets expressions use list functions on attributes that are absent
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// ExpressionCache keeps compiled expressions, since requests are
+// reconciled repeatedly with the same ones
+type ExpressionCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedExpression
+}
+
+type cachedExpression struct {
+	expr *Expression
+	err  error
+}
+
+// maxCachedExpressions bounds the cache, it is emptied when full
+const maxCachedExpressions = 256
+
+// Filter returns the compiled filter expression, nil when source is empty
+func (c *ExpressionCache) Filter(source string) (*Expression, error) {
+	return c.get("filter", source, CompileFilter)
+}
+
+// SortBy returns the compiled sort expression, nil when source is empty
+func (c *ExpressionCache) SortBy(source string) (*Expression, error) {
+	return c.get("sortBy", source, CompileSortBy)
+}
+
+func (c *ExpressionCache) get(kind, source string, compile func(string) (*Expression, error)) (*Expression, error) {
+	if source == "" {
+		return nil, nil
+	}
+	key := kind + "\x00" + source
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[key]; ok {
+		return entry.expr, entry.err
+	}
+	if c.entries == nil || len(c.entries) >= maxCachedExpressions {
+		c.entries = make(map[string]cachedExpression)
+	}
+	expr, err := compile(source)
+	c.entries[key] = cachedExpression{expr, err}
+	return expr, err
+}
+
+// sortKey evaluates the sort expression, ranking failures last
+func sortKey(e *Expression, c *Candidate) float64 {
+	key, err := e.Key(c)
+	if err != nil || math.IsNaN(key) {
+		return math.Inf(-1)
+	}
+	return key
+}
@@ -0,0 +1,107 @@
+package selection
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitoru.fun/torrents/internal/parser"
+)
+
+func TestCompileErrors(t *testing.T) {
+	_, err := CompileFilter("seeders >")
+	assert.Error(t, err)
+
+	_, err = CompileFilter("unknown > 5")
+	assert.ErrorContains(t, err, "undeclared reference to 'unknown'")
+
+	_, err = CompileFilter("seeders + 1")
+	assert.EqualError(t, err, "expression returns int, expected bool")
+
+	_, err = CompileSortBy("title")
+	assert.EqualError(t, err, "expression returns string, expected a number")
+
+	_, err = CompileSortBy("double(seeders) / 2.0")
+	assert.NoError(t, err)
+}
+
+func TestFilter(t *testing.T) {
+	published := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	result := parser.ParseResult{
+		Title:       "Show.S01E02.1080p.WEB-DL.DDP5.1.H.264-NTb",
+		Size:        "2 GiB",
+		Seeders:     40,
+		Leechers:    3,
+		Indexer:     "tracker",
+		PublishedAt: &published,
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"seeders > 5 && sizeBytes < 4 * 1024 * 1024 * 1024 && !title.contains('CAM')", true},
+		{"leechers == 3 && indexer == 'tracker'", true},
+		{"release.resolution == '1080p' && release.source == 'WEB-DL'", true},
+		{"2 in release.episodes && release.seasons == [1]", true},
+		{"release.group in ['NTb', 'FLUX']", true},
+		{"'DD+' in release.audio && release.channels == '5.1'", true},
+		{"release.hdr.size() == 0 && release.languages.size() == 0", true},
+		{"publishedAt > timestamp('2024-01-01T00:00:00Z')", true},
+		{"quality == 'WEB-DL 1080p' && formats.size() == 0 && formatScore == 0", true},
+		{"title.matches('(?i)\\\\bcam\\\\b')", false},
+		{"release.proper || release.repack", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := CompileFilter(tt.expr)
+			require.NoError(t, err)
+			got, err := expr.Match(candidate(result))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSelectFilterAndSortBy(t *testing.T) {
+	filter, err := CompileFilter("!title.contains('CAM')")
+	require.NoError(t, err)
+	sortBy, err := CompileSortBy("sizeBytes")
+	require.NoError(t, err)
+
+	candidates := Select([]parser.ParseResult{
+		{Title: "Movie.2020.1080p.WEB-DL-A", Size: "4 GB", Seeders: 10},
+		{Title: "Movie.2020.CAM-B", Size: "9 GB", Seeders: 1000},
+		{Title: "Movie.2020.1080p.BluRay-C", Size: "8 GB", Seeders: 5},
+		{Title: "Movie.2020.720p.WEB-DL-D", Size: "4 GB", Seeders: 50},
+	}, Options{Filter: filter, SortBy: sortBy})
+
+	// Ties on size fall back to seeders
+	assert.Equal(t, []string{
+		"Movie.2020.1080p.BluRay-C",
+		"Movie.2020.720p.WEB-DL-D",
+		"Movie.2020.1080p.WEB-DL-A",
+		"Movie.2020.CAM-B",
+	}, titles(candidates))
+	assert.Equal(t, "rejected by filter", candidates[3].Reason())
+}
+
+func TestExpressionCache(t *testing.T) {
+	var cache ExpressionCache
+
+	expr, err := cache.Filter("")
+	assert.NoError(t, err)
+	assert.Nil(t, expr)
+
+	first, err := cache.Filter("seeders > 1")
+	require.NoError(t, err)
+	second, err := cache.Filter("seeders > 1")
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	_, err = cache.SortBy("seeders > 1")
+	assert.EqualError(t, err, "expression returns bool, expected a number")
+}
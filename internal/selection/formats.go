@@ -0,0 +1,101 @@
+package selection
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+)
+
+// Format is a CustomFormat prepared for matching results
+type Format struct {
+	Name  string
+	Score int
+
+	conditions []condition
+}
+
+// condition reports whether a candidate meets one FormatCondition
+type condition func(c *Candidate) bool
+
+// NewFormat checks the custom format spec and prepares it for matching
+func NewFormat(name string, spec torrentsv1alpha1.CustomFormatSpec) (*Format, error) {
+	if len(spec.Conditions) == 0 {
+		return nil, fmt.Errorf("custom format %s has no conditions", name)
+	}
+	f := &Format{Name: name, Score: spec.Score}
+	for i, cond := range spec.Conditions {
+		match, err := newCondition(cond)
+		if err != nil {
+			return nil, fmt.Errorf("custom format %s condition %d: %w", name, i, err)
+		}
+		if cond.Negate {
+			f.conditions = append(f.conditions, func(c *Candidate) bool { return !match(c) })
+		} else {
+			f.conditions = append(f.conditions, match)
+		}
+	}
+	return f, nil
+}
+
+func newCondition(cond torrentsv1alpha1.FormatCondition) (condition, error) {
+	switch {
+	case cond.Title != "":
+		re, err := regexp.Compile("(?i)" + cond.Title)
+		if err != nil {
+			return nil, err
+		}
+		return func(c *Candidate) bool { return re.MatchString(c.Title) }, nil
+	case cond.ReleaseGroup != "":
+		re, err := regexp.Compile("(?i)" + cond.ReleaseGroup)
+		if err != nil {
+			return nil, err
+		}
+		return func(c *Candidate) bool { return c.Release.Group != "" && re.MatchString(c.Release.Group) }, nil
+	case cond.Size != nil:
+		return func(c *Candidate) bool {
+			size := c.SizeBytes
+			if size <= 0 {
+				return false
+			}
+			return (cond.Size.Min == nil || size >= cond.Size.Min.Value()) &&
+				(cond.Size.Max == nil || size <= cond.Size.Max.Value())
+		}, nil
+	case len(cond.Indexers) > 0:
+		return func(c *Candidate) bool { return slices.Contains(cond.Indexers, c.Indexer) }, nil
+	case len(cond.Languages) > 0:
+		return func(c *Candidate) bool {
+			languages := c.Release.Languages
+			if len(languages) == 0 {
+				languages = []string{"English"}
+			}
+			return slices.ContainsFunc(cond.Languages, func(want string) bool {
+				return slices.ContainsFunc(languages, func(l string) bool { return strings.EqualFold(l, want) })
+			})
+		}, nil
+	}
+	return nil, errors.New("condition checks nothing")
+}
+
+// Matches reports whether the candidate meets every condition of the format
+func (f *Format) Matches(c *Candidate) bool {
+	for _, match := range f.conditions {
+		if !match(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// score records the formats the candidate matches and sums their scores
+func score(c *Candidate, formats []*Format) {
+	for _, f := range formats {
+		if f.Matches(c) {
+			c.Formats = append(c.Formats, f.Name)
+			c.FormatScore += f.Score
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package selection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/parser"
+)
+
+func candidate(result parser.ParseResult) *Candidate {
+	c := newCandidate(result)
+	return &c
+}
+
+func TestFormatConditions(t *testing.T) {
+	web := parser.ParseResult{Title: "Movie.2020.1080p.WEB-DL.DDP5.1.Atmos.H.265-FLUX", Size: "6 GB", Indexer: "tracker-a"}
+	french := parser.ParseResult{Title: "Movie.2020.FRENCH.1080p.BluRay.x264-LOST", Size: "12 GB", Indexer: "tracker-b"}
+
+	tests := []struct {
+		name      string
+		condition torrentsv1alpha1.FormatCondition
+		web       bool
+		french    bool
+	}{
+		{"title", torrentsv1alpha1.FormatCondition{Title: `\batmos\b`}, true, false},
+		{"negated title", torrentsv1alpha1.FormatCondition{Title: `\batmos\b`, Negate: true}, false, true},
+		{"release group", torrentsv1alpha1.FormatCondition{ReleaseGroup: `^(flux|ntb)$`}, true, false},
+		{"min size", torrentsv1alpha1.FormatCondition{Size: &torrentsv1alpha1.SizeRange{Min: quantity("10Gi")}}, false, true},
+		{"max size", torrentsv1alpha1.FormatCondition{Size: &torrentsv1alpha1.SizeRange{Max: quantity("10Gi")}}, true, false},
+		{"indexer", torrentsv1alpha1.FormatCondition{Indexers: []string{"tracker-b"}}, false, true},
+		{"language", torrentsv1alpha1.FormatCondition{Languages: []string{"french"}}, false, true},
+		{"english by default", torrentsv1alpha1.FormatCondition{Languages: []string{"English"}}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFormat(tt.name, torrentsv1alpha1.CustomFormatSpec{
+				Conditions: []torrentsv1alpha1.FormatCondition{tt.condition},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.web, f.Matches(candidate(web)))
+			assert.Equal(t, tt.french, f.Matches(candidate(french)))
+		})
+	}
+}
+
+func TestFormatMatchesAllConditions(t *testing.T) {
+	f, err := NewFormat("x265 Atmos", torrentsv1alpha1.CustomFormatSpec{
+		Conditions: []torrentsv1alpha1.FormatCondition{
+			{Title: `x265|h\.?265|hevc`},
+			{Title: `atmos`},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, f.Matches(candidate(parser.ParseResult{Title: "Movie.2020.2160p.WEB-DL.Atmos.HEVC-GROUP"})))
+	assert.False(t, f.Matches(candidate(parser.ParseResult{Title: "Movie.2020.2160p.WEB-DL.Atmos.H.264-GROUP"})))
+}
+
+func TestNewFormatErrors(t *testing.T) {
+	_, err := NewFormat("empty", torrentsv1alpha1.CustomFormatSpec{})
+	assert.EqualError(t, err, "custom format empty has no conditions")
+
+	_, err = NewFormat("bad", torrentsv1alpha1.CustomFormatSpec{
+		Conditions: []torrentsv1alpha1.FormatCondition{{Title: `(`}},
+	})
+	assert.ErrorContains(t, err, "custom format bad condition 0")
+
+	_, err = NewFormat("nothing", torrentsv1alpha1.CustomFormatSpec{
+		Conditions: []torrentsv1alpha1.FormatCondition{{Negate: true}},
+	})
+	assert.EqualError(t, err, "custom format nothing condition 0: condition checks nothing")
+}
+
+func TestSelectFormatScore(t *testing.T) {
+	hevc, err := NewFormat("HEVC", torrentsv1alpha1.CustomFormatSpec{
+		Conditions: []torrentsv1alpha1.FormatCondition{{Title: `x265|h\.?265|hevc`}},
+		Score:      100,
+	})
+	require.NoError(t, err)
+	atmos, err := NewFormat("Atmos", torrentsv1alpha1.CustomFormatSpec{
+		Conditions: []torrentsv1alpha1.FormatCondition{{Title: `atmos`}},
+		Score:      50,
+	})
+	require.NoError(t, err)
+	badGroup, err := NewFormat("Bad Group", torrentsv1alpha1.CustomFormatSpec{
+		Conditions: []torrentsv1alpha1.FormatCondition{{ReleaseGroup: `^YIFY$`}},
+		Score:      -1000,
+	})
+	require.NoError(t, err)
+
+	profile, err := NewProfile(torrentsv1alpha1.QualityProfileSpec{
+		Qualities:      []torrentsv1alpha1.Quality{{Resolution: "1080p"}},
+		MinFormatScore: 0,
+	})
+	require.NoError(t, err)
+
+	candidates := Select([]parser.ParseResult{
+		{Title: "Movie.2020.1080p.WEB-DL.H.264-GROUP", Seeders: 500},
+		{Title: "Movie.2020.1080p.WEB-DL.Atmos.H.265-GROUP", Seeders: 10},
+		{Title: "Movie.2020.1080p.WEB-DL.H.265-GROUP", Seeders: 50},
+		{Title: "Movie.2020.1080p.BluRay.x265-YIFY", Seeders: 5000},
+	}, Options{Profile: profile, Formats: []*Format{hevc, atmos, badGroup}})
+
+	assert.Equal(t, []string{
+		"Movie.2020.1080p.WEB-DL.Atmos.H.265-GROUP",
+		"Movie.2020.1080p.WEB-DL.H.265-GROUP",
+		"Movie.2020.1080p.WEB-DL.H.264-GROUP",
+		"Movie.2020.1080p.BluRay.x265-YIFY",
+	}, titles(candidates))
+	assert.Equal(t, []string{"HEVC", "Atmos"}, candidates[0].Formats)
+	assert.Equal(t, 150, candidates[0].FormatScore)
+	assert.Equal(t, "quality WEB-DL 1080p, formats HEVC, Atmos (score 150), 10 seeders", candidates[0].Reason())
+	assert.Equal(t, "format score -900 is below the minimum of 0", candidates[3].Reason())
+}
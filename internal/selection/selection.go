@@ -0,0 +1,333 @@
+// Package selection ranks search results against what a TorrentRequest
+// accepts, so the best one can be picked and the others explained.
+package selection
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/parser"
+	"vitoru.fun/torrents/internal/releaseparse"
+)
+
+// Profile is a QualityProfile prepared for ranking results
+type Profile struct {
+	qualities []torrentsv1alpha1.Quality
+	// cutoff is the index of the cutoff quality, -1 when unset
+	cutoff    int
+	required  []string
+	forbidden []string
+	groups    []string
+	minScore  int
+}
+
+// NewProfile checks the profile spec and prepares it for ranking
+func NewProfile(spec torrentsv1alpha1.QualityProfileSpec) (*Profile, error) {
+	if len(spec.Qualities) == 0 {
+		return nil, errors.New("quality profile has no qualities")
+	}
+	p := &Profile{
+		qualities: spec.Qualities,
+		required:  lower(spec.RequiredWords),
+		forbidden: lower(spec.ForbiddenWords),
+		groups:    lower(spec.PreferredGroups),
+		minScore:  spec.MinFormatScore,
+		cutoff:    -1,
+	}
+	if spec.Cutoff != "" {
+		p.cutoff = p.IndexOf(spec.Cutoff)
+		if p.cutoff < 0 {
+			return nil, fmt.Errorf("cutoff %q is not one of the qualities", spec.Cutoff)
+		}
+	}
+	return p, nil
+}
+
+// QualityIndex is the index of the first quality of the profile matching
+// a release, lower is better. Releases matching none get the number of
+// qualities.
+func (p *Profile) QualityIndex(resolution, source string) int {
+	i := slices.IndexFunc(p.qualities, func(q torrentsv1alpha1.Quality) bool {
+		return (q.Resolution == "" || q.Resolution == resolution) &&
+			(q.Source == "" || q.Source == source)
+	})
+	if i < 0 {
+		return len(p.qualities)
+	}
+	return i
+}
+
+// IndexOf is the index of the quality of the profile with this name, -1 when
+// there is none
+func (p *Profile) IndexOf(name string) int {
+	return slices.IndexFunc(p.qualities, func(q torrentsv1alpha1.Quality) bool {
+		return strings.EqualFold(QualityName(q.Resolution, q.Source), name)
+	})
+}
+
+// ReachedCutoff reports whether a release of this resolution and source is
+// of the cutoff quality or better, so it is not worth upgrading. Profiles
+// without a cutoff are never reached.
+func (p *Profile) ReachedCutoff(resolution, source string) bool {
+	return p.cutoff >= 0 && p.QualityIndex(resolution, source) <= p.cutoff
+}
+
+// QualityName names a resolution and source combination, e.g. "WEB-DL 1080p"
+func QualityName(resolution, source string) string {
+	return strings.TrimSpace(source + " " + resolution)
+}
+
+// Candidate is a search result considered for a request
+type Candidate struct {
+	parser.ParseResult
+	// Release holds the attributes parsed from the title
+	Release releaseparse.Release
+	// SizeBytes is the parsed size, 0 when unknown
+	SizeBytes int64
+	// Quality is the name of the release resolution and source
+	Quality string
+	// Rank is the index of the quality in the profile, lower is better
+	Rank int
+	// Formats are the names of the custom formats the result matches, and
+	// FormatScore the sum of their scores
+	Formats     []string
+	FormatScore int
+	// PreferredWords are the preferred words of the request the title
+	// contains, and WordScore the sum of their weights
+	PreferredWords []string
+	WordScore      int
+	// Rejections explain why the request does not accept the result
+	Rejections []string
+
+	// rules name the rule behind each rejection
+	rules []string
+
+	// group is the index of the release group among the preferred ones
+	group int
+	// key is the value of the sortBy expression
+	key float64
+}
+
+// reject records why the request does not accept the result, and the rule
+// that decided it
+func (c *Candidate) reject(rule, reason string) {
+	c.Rejections = append(c.Rejections, reason)
+	c.rules = append(c.rules, rule)
+}
+
+// Rejected reports whether the request does not accept the result
+func (c *Candidate) Rejected() bool {
+	return len(c.Rejections) > 0
+}
+
+// Reason explains the rank of the candidate, or why it was rejected
+func (c *Candidate) Reason() string {
+	if c.Rejected() {
+		return strings.Join(c.Rejections, "; ")
+	}
+	var parts []string
+	if c.Quality != "" {
+		parts = append(parts, "quality "+c.Quality)
+	}
+	if len(c.Formats) > 0 {
+		parts = append(parts, fmt.Sprintf("formats %s (score %d)", strings.Join(c.Formats, ", "), c.FormatScore))
+	}
+	if len(c.PreferredWords) > 0 {
+		parts = append(parts, fmt.Sprintf("preferred words %s (score %d)", strings.Join(c.PreferredWords, ", "), c.WordScore))
+	}
+	if c.group < noGroup {
+		parts = append(parts, "preferred group "+c.Release.Group)
+	}
+	parts = append(parts, fmt.Sprintf("%d seeders", c.Seeders))
+	return strings.Join(parts, ", ")
+}
+
+// noGroup ranks release groups that are not preferred
+const noGroup = math.MaxInt
+
+func newCandidate(result parser.ParseResult) Candidate {
+	release := releaseparse.Parse(result.Title)
+	return Candidate{
+		ParseResult: result,
+		Release:     release,
+		SizeBytes:   parser.ParseSize(result.Size),
+		Quality:     QualityName(release.Resolution, release.Source),
+		group:       noGroup,
+	}
+}
+
+// Options are what a request accepts
+type Options struct {
+	// Profile ranks results by quality, nil ranks them by seeders only
+	Profile *Profile
+	// Formats score results, higher scores rank first within a quality
+	Formats    []*Format
+	MinSeeders int
+	// Words reject results by the words of their title, and score the
+	// preferred ones below the custom formats
+	Words *Words
+	// Filter rejects the results it evaluates to false for
+	Filter *Expression
+	// SortBy ranks results, highest value first. The quality, score, group
+	// and seeders order only breaks its ties.
+	SortBy *Expression
+}
+
+// Select ranks the results, best first. Accepted candidates come before
+// rejected ones; then candidates are ordered by the sortBy expression when
+// set, and by quality rank, custom format score, preferred words score,
+// preferred release group and seeders.
+func Select(results []parser.ParseResult, opts Options) []Candidate {
+	candidates := make([]Candidate, 0, len(results))
+	for _, result := range results {
+		c := newCandidate(result)
+		score(&c, opts.Formats)
+		if opts.Profile != nil {
+			opts.Profile.rank(&c)
+		}
+		if opts.Words != nil {
+			opts.Words.apply(&c)
+		}
+		if c.Seeders < opts.MinSeeders {
+			c.reject("minSeeders", fmt.Sprintf("%d seeders, %d required", c.Seeders, opts.MinSeeders))
+		}
+		if opts.Filter != nil {
+			if ok, err := opts.Filter.Match(&c); err != nil {
+				c.reject("filter", fmt.Sprintf("filter failed: %v", err))
+			} else if !ok {
+				c.reject("filter", "rejected by filter")
+			}
+		}
+		if opts.SortBy != nil {
+			c.key = sortKey(opts.SortBy, &c)
+		}
+		candidates = append(candidates, c)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := &candidates[i], &candidates[j]
+		if a.Rejected() != b.Rejected() {
+			return !a.Rejected()
+		}
+		if a.key != b.key {
+			return a.key > b.key
+		}
+		if a.Rank != b.Rank {
+			return a.Rank < b.Rank
+		}
+		if a.FormatScore != b.FormatScore {
+			return a.FormatScore > b.FormatScore
+		}
+		if a.WordScore != b.WordScore {
+			return a.WordScore > b.WordScore
+		}
+		if a.group != b.group {
+			return a.group < b.group
+		}
+		return a.Seeders > b.Seeders
+	})
+	return candidates
+}
+
+// Elimination is how many results a rule rejected
+type Elimination struct {
+	Rule  string
+	Count int
+}
+
+// Eliminations counts the results each rule rejected, most first. A result
+// rejected by several rules counts for each of them.
+func Eliminations(candidates []Candidate) []Elimination {
+	counts := map[string]int{}
+	for i := range candidates {
+		seen := map[string]bool{}
+		for _, rule := range candidates[i].rules {
+			if !seen[rule] {
+				seen[rule] = true
+				counts[rule]++
+			}
+		}
+	}
+	eliminations := make([]Elimination, 0, len(counts))
+	for rule, count := range counts {
+		eliminations = append(eliminations, Elimination{Rule: rule, Count: count})
+	}
+	sort.Slice(eliminations, func(i, j int) bool {
+		if eliminations[i].Count != eliminations[j].Count {
+			return eliminations[i].Count > eliminations[j].Count
+		}
+		return eliminations[i].Rule < eliminations[j].Rule
+	})
+	return eliminations
+}
+
+// rank places the candidate in the profile, rejecting it when the profile
+// does not allow it
+func (p *Profile) rank(c *Candidate) {
+	title := strings.ToLower(c.Title)
+
+	i := p.QualityIndex(c.Release.Resolution, c.Release.Source)
+	if i == len(p.qualities) {
+		quality := c.Quality
+		if quality == "" {
+			quality = "unknown"
+		}
+		c.Rank = len(p.qualities)
+		c.reject("quality", fmt.Sprintf("quality %s is not allowed", quality))
+	} else {
+		c.Rank = i
+		for _, reason := range sizeRejections(c, p.qualities[i]) {
+			c.reject("size", reason)
+		}
+	}
+
+	for _, word := range p.required {
+		if !strings.Contains(title, word) {
+			c.reject("requiredWords", fmt.Sprintf("missing required word %q", word))
+		}
+	}
+	for _, word := range p.forbidden {
+		if strings.Contains(title, word) {
+			c.reject("forbiddenWords", fmt.Sprintf("contains forbidden word %q", word))
+		}
+	}
+	if c.FormatScore < p.minScore {
+		c.reject("minFormatScore", fmt.Sprintf("format score %d is below the minimum of %d", c.FormatScore, p.minScore))
+	}
+	if g := slices.Index(p.groups, strings.ToLower(c.Release.Group)); g >= 0 {
+		c.group = g
+	}
+}
+
+// sizeRejections checks the candidate against the size bounds of its
+// quality. Results of unknown size are accepted.
+func sizeRejections(c *Candidate, q torrentsv1alpha1.Quality) []string {
+	size := c.SizeBytes
+	if size <= 0 {
+		return nil
+	}
+	name := QualityName(q.Resolution, q.Source)
+	var rejections []string
+	if q.MinSize != nil && size < q.MinSize.Value() {
+		rejections = append(rejections, fmt.Sprintf("size %s is below the %s minimum of %s", c.Size, name, q.MinSize))
+	}
+	if q.MaxSize != nil && size > q.MaxSize.Value() {
+		rejections = append(rejections, fmt.Sprintf("size %s is above the %s maximum of %s", c.Size, name, q.MaxSize))
+	}
+	return rejections
+}
+
+func lower(words []string) []string {
+	out := make([]string, 0, len(words))
+	for _, w := range words {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			out = append(out, w)
+		}
+	}
+	return out
+}
@@ -0,0 +1,171 @@
+package selection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/parser"
+)
+
+func quantity(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
+
+func titles(candidates []Candidate) []string {
+	out := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		out = append(out, c.Title)
+	}
+	return out
+}
+
+func TestSelectWithoutProfile(t *testing.T) {
+	results := []parser.ParseResult{
+		{Title: "Ubuntu.22.04.ISO", Seeders: 10},
+		{Title: "Ubuntu.22.04.Desktop.ISO", Seeders: 100},
+		{Title: "Ubuntu.22.04.Server.ISO", Seeders: 2},
+	}
+
+	candidates := Select(results, Options{MinSeeders: 5})
+	assert.Equal(t, []string{"Ubuntu.22.04.Desktop.ISO", "Ubuntu.22.04.ISO", "Ubuntu.22.04.Server.ISO"}, titles(candidates))
+	assert.False(t, candidates[0].Rejected())
+	assert.Equal(t, "100 seeders", candidates[0].Reason())
+	assert.True(t, candidates[2].Rejected())
+	assert.Equal(t, "2 seeders, 5 required", candidates[2].Reason())
+}
+
+func TestSelectWithProfile(t *testing.T) {
+	profile, err := NewProfile(torrentsv1alpha1.QualityProfileSpec{
+		Qualities: []torrentsv1alpha1.Quality{
+			{Resolution: "2160p", Source: "WEB-DL"},
+			{Resolution: "1080p", Source: "BluRay", MaxSize: quantity("20Gi")},
+			{Resolution: "1080p", Source: "WEB-DL", MinSize: quantity("1Gi")},
+			{Resolution: "720p"},
+		},
+		ForbiddenWords:  []string{"hardcoded"},
+		PreferredGroups: []string{"NTb"},
+	})
+	require.NoError(t, err)
+
+	results := []parser.ParseResult{
+		{Title: "Movie.2020.720p.HDTV.x264-LOL", Size: "1 GB", Seeders: 500},
+		{Title: "Movie.2020.1080p.WEB-DL.H.264-FLUX", Size: "4 GB", Seeders: 300},
+		{Title: "Movie.2020.1080p.WEB-DL.H.264-NTb", Size: "4 GB", Seeders: 50},
+		{Title: "Movie.2020.1080p.BluRay.x264-GROUP", Size: "30 GB", Seeders: 80},
+		{Title: "Movie.2020.1080p.WEB-DL.H.264-TINY", Size: "500 MB", Seeders: 900},
+		{Title: "Movie.2020.2160p.WEB-DL.HARDCODED.H.265-GROUP", Size: "15 GB", Seeders: 40},
+		{Title: "Movie.2020.480p.DVDRip.XviD-OLD", Size: "700 MB", Seeders: 1000},
+		{Title: "Movie.2020.2160p.WEB-DL.H.265-GROUP", Size: "15 GB", Seeders: 10},
+	}
+
+	candidates := Select(results, Options{Profile: profile})
+	assert.Equal(t, []string{
+		"Movie.2020.2160p.WEB-DL.H.265-GROUP",
+		// Preferred group first among the same quality
+		"Movie.2020.1080p.WEB-DL.H.264-NTb",
+		"Movie.2020.1080p.WEB-DL.H.264-FLUX",
+		"Movie.2020.720p.HDTV.x264-LOL",
+		"Movie.2020.2160p.WEB-DL.HARDCODED.H.265-GROUP",
+		"Movie.2020.1080p.BluRay.x264-GROUP",
+		"Movie.2020.1080p.WEB-DL.H.264-TINY",
+		"Movie.2020.480p.DVDRip.XviD-OLD",
+	}, titles(candidates))
+
+	assert.Equal(t, "quality WEB-DL 2160p, 10 seeders", candidates[0].Reason())
+	assert.Equal(t, "quality WEB-DL 1080p, preferred group NTb, 50 seeders", candidates[1].Reason())
+	assert.Equal(t, `contains forbidden word "hardcoded"`, candidates[4].Reason())
+	assert.Equal(t, "size 30 GB is above the BluRay 1080p maximum of 20Gi", candidates[5].Reason())
+	assert.Equal(t, "size 500 MB is below the WEB-DL 1080p minimum of 1Gi", candidates[6].Reason())
+	assert.Equal(t, "quality DVD 480p is not allowed", candidates[7].Reason())
+	for _, c := range candidates[4:] {
+		assert.True(t, c.Rejected(), c.Title)
+	}
+}
+
+func TestSelectCutoff(t *testing.T) {
+	profile, err := NewProfile(torrentsv1alpha1.QualityProfileSpec{
+		Qualities: []torrentsv1alpha1.Quality{
+			{Resolution: "2160p"},
+			{Resolution: "1080p"},
+			{Resolution: "720p"},
+		},
+		Cutoff: "1080p",
+	})
+	require.NoError(t, err)
+
+	results := []parser.ParseResult{
+		{Title: "Show.S01E01.720p.WEB-DL", Seeders: 900},
+		{Title: "Show.S01E01.2160p.WEB-DL", Seeders: 5},
+		{Title: "Show.S01E01.1080p.WEB-DL", Seeders: 200},
+	}
+
+	// The cutoff does not tie the qualities above it, they rank in order
+	candidates := Select(results, Options{Profile: profile})
+	assert.Equal(t, []string{"Show.S01E01.2160p.WEB-DL", "Show.S01E01.1080p.WEB-DL", "Show.S01E01.720p.WEB-DL"}, titles(candidates))
+
+	assert.True(t, profile.ReachedCutoff("2160p", "WEB-DL"))
+	assert.True(t, profile.ReachedCutoff("1080p", "WEB-DL"))
+	assert.False(t, profile.ReachedCutoff("720p", "WEB-DL"))
+
+	noCutoff, err := NewProfile(torrentsv1alpha1.QualityProfileSpec{Qualities: []torrentsv1alpha1.Quality{{Resolution: "1080p"}}})
+	require.NoError(t, err)
+	assert.False(t, noCutoff.ReachedCutoff("1080p", "WEB-DL"))
+}
+
+func TestSelectRequiredWords(t *testing.T) {
+	profile, err := NewProfile(torrentsv1alpha1.QualityProfileSpec{
+		Qualities:     []torrentsv1alpha1.Quality{{Resolution: "1080p"}},
+		RequiredWords: []string{"Atmos"},
+	})
+	require.NoError(t, err)
+
+	candidates := Select([]parser.ParseResult{
+		{Title: "Movie.2020.1080p.WEB-DL.DDP5.1-GROUP", Seeders: 100},
+		{Title: "Movie.2020.1080p.WEB-DL.DDP5.1.Atmos-GROUP", Seeders: 10},
+	}, Options{Profile: profile})
+	assert.Equal(t, "Movie.2020.1080p.WEB-DL.DDP5.1.Atmos-GROUP", candidates[0].Title)
+	assert.Equal(t, `missing required word "atmos"`, candidates[1].Reason())
+}
+
+func TestNewProfileErrors(t *testing.T) {
+	_, err := NewProfile(torrentsv1alpha1.QualityProfileSpec{})
+	assert.EqualError(t, err, "quality profile has no qualities")
+
+	_, err = NewProfile(torrentsv1alpha1.QualityProfileSpec{
+		Qualities: []torrentsv1alpha1.Quality{{Resolution: "1080p", Source: "WEB-DL"}},
+		Cutoff:    "BluRay 1080p",
+	})
+	assert.EqualError(t, err, `cutoff "BluRay 1080p" is not one of the qualities`)
+
+	_, err = NewProfile(torrentsv1alpha1.QualityProfileSpec{
+		Qualities: []torrentsv1alpha1.Quality{{Resolution: "1080p", Source: "WEB-DL"}},
+		Cutoff:    "web-dl 1080p",
+	})
+	assert.NoError(t, err)
+}
+
+func TestProfileQualityIndex(t *testing.T) {
+	profile, err := NewProfile(torrentsv1alpha1.QualityProfileSpec{
+		Qualities: []torrentsv1alpha1.Quality{
+			{Resolution: "2160p", Source: "Remux"},
+			{Resolution: "1080p", Source: "BluRay"},
+			{Resolution: "1080p"},
+		},
+		Cutoff: "1080p",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, profile.QualityIndex("2160p", "Remux"))
+	assert.Equal(t, 1, profile.QualityIndex("1080p", "BluRay"))
+	assert.Equal(t, 2, profile.QualityIndex("1080p", "WEB-DL"))
+	assert.Equal(t, 3, profile.QualityIndex("720p", "HDTV"))
+
+	assert.Equal(t, 1, profile.IndexOf("bluray 1080p"))
+	assert.Equal(t, 2, profile.IndexOf("1080p"))
+	assert.Equal(t, -1, profile.IndexOf("WEB-DL 1080p"))
+}
@@ -0,0 +1,129 @@
+package selection
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+)
+
+// Pattern matches titles containing a word, or a regular expression when
+// written between slashes. Both ignore case. Words only match whole, and the
+// spaces in them match any separator, so "directors cut" matches
+// "Movie.Directors.Cut.1080p".
+type Pattern struct {
+	Source string
+	re     *regexp.Regexp
+}
+
+// separator is what stands between the words of a title
+const separator = `[^\pL\pN]`
+
+// NewPattern compiles a word or /regular expression/
+func NewPattern(source string) (*Pattern, error) {
+	source = strings.TrimSpace(source)
+	var expr string
+	if len(source) > 2 && strings.HasPrefix(source, "/") && strings.HasSuffix(source, "/") {
+		expr = source[1 : len(source)-1]
+	} else {
+		words := strings.Fields(source)
+		if len(words) == 0 {
+			return nil, errors.New("pattern is empty")
+		}
+		for i, w := range words {
+			words[i] = regexp.QuoteMeta(w)
+		}
+		expr = strings.Join(words, separator+"+")
+		// Words ending with a symbol, like "DD+", need no boundary there
+		if alnum(source[0]) {
+			expr = "(?:^|" + separator + ")" + expr
+		}
+		if alnum(source[len(source)-1]) {
+			expr += "(?:$|" + separator + ")"
+		}
+	}
+	re, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{Source: source, re: re}, nil
+}
+
+func alnum(b byte) bool {
+	return b >= utf8.RuneSelf || unicode.IsLetter(rune(b)) || unicode.IsDigit(rune(b))
+}
+
+// Matches reports whether the title contains the pattern
+func (p *Pattern) Matches(title string) bool {
+	return p.re.MatchString(title)
+}
+
+// Words are the title constraints of a request
+type Words struct {
+	mustContain    []*Pattern
+	mustNotContain []*Pattern
+	preferred      []weightedPattern
+}
+
+type weightedPattern struct {
+	*Pattern
+	weight int
+}
+
+// NewWords compiles the mustContain, mustNotContain and preferredWords of a
+// request
+func NewWords(mustContain, mustNotContain []string, preferred []torrentsv1alpha1.PreferredWord) (*Words, error) {
+	w := &Words{}
+	var err error
+	if w.mustContain, err = patterns("mustContain", mustContain); err != nil {
+		return nil, err
+	}
+	if w.mustNotContain, err = patterns("mustNotContain", mustNotContain); err != nil {
+		return nil, err
+	}
+	for i, pw := range preferred {
+		p, err := NewPattern(pw.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("preferredWords[%d]: %w", i, err)
+		}
+		w.preferred = append(w.preferred, weightedPattern{p, pw.Weight})
+	}
+	return w, nil
+}
+
+func patterns(field string, sources []string) ([]*Pattern, error) {
+	out := make([]*Pattern, 0, len(sources))
+	for i, s := range sources {
+		p, err := NewPattern(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: %w", field, i, err)
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// apply rejects the candidate when it misses a required pattern or contains
+// a forbidden one, and scores the preferred patterns it contains
+func (w *Words) apply(c *Candidate) {
+	for _, p := range w.mustContain {
+		if !p.Matches(c.Title) {
+			c.reject(fmt.Sprintf("mustContain %q", p.Source), fmt.Sprintf("does not contain %q", p.Source))
+		}
+	}
+	for _, p := range w.mustNotContain {
+		if p.Matches(c.Title) {
+			c.reject(fmt.Sprintf("mustNotContain %q", p.Source), fmt.Sprintf("contains %q", p.Source))
+		}
+	}
+	for _, p := range w.preferred {
+		if p.Matches(c.Title) {
+			c.PreferredWords = append(c.PreferredWords, p.Source)
+			c.WordScore += p.weight
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package selection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/parser"
+)
+
+func TestPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		title   string
+		want    bool
+	}{
+		{"CAM", "Movie.2020.CAM.x264-GROUP", true},
+		{"cam", "Movie 2020 [CAM] x264", true},
+		{"CAM", "Movie.2020.Camera.Obscura.1080p", false},
+		{"CAM", "Candid.Cam.2020.1080p", true},
+		{"sample", "Movie.2020.1080p-GROUP.Sample", true},
+		{"directors cut", "Movie.2020.Directors.Cut.1080p", true},
+		{"directors cut", "Movie.2020.Directors_Cut.1080p", true},
+		{"directors cut", "Movie.2020.Directors.1080p.Cut", false},
+		{"DD+", "Movie.2020.1080p.WEB-DL.DD+5.1", true},
+		{"DD+", "Movie.2020.1080p.WEB-DL.ADD+5.1", false},
+		{"H.265", "Movie.2020.1080p.H.265-GROUP", true},
+		{"H.265", "Movie.2020.1080p.H2655-GROUP", false},
+		{"/x26[45]/", "Movie.2020.1080p.X265-GROUP", true},
+		{"/x26[45]/", "Movie.2020.1080p.AV1-GROUP", false},
+		{"/^movie\\./", "Movie.2020.1080p", true},
+		{"/", "Movie/2020", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.title, func(t *testing.T) {
+			p, err := NewPattern(tt.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, p.Matches(tt.title))
+		})
+	}
+}
+
+func TestNewWordsErrors(t *testing.T) {
+	_, err := NewWords([]string{"1080p", "  "}, nil, nil)
+	assert.EqualError(t, err, "mustContain[1]: pattern is empty")
+
+	_, err = NewWords(nil, []string{"/(/"}, nil)
+	assert.ErrorContains(t, err, "mustNotContain[0]: error parsing regexp")
+
+	_, err = NewWords(nil, nil, []torrentsv1alpha1.PreferredWord{{Pattern: "/[/"}})
+	assert.ErrorContains(t, err, "preferredWords[0]: error parsing regexp")
+}
+
+func TestSelectWords(t *testing.T) {
+	words, err := NewWords(
+		[]string{"1080p"},
+		[]string{"CAM", "sample", "/\\bfrench\\b/"},
+		[]torrentsv1alpha1.PreferredWord{{Pattern: "remux", Weight: 20}, {Pattern: "proper", Weight: 5}, {Pattern: "hc", Weight: -50}},
+	)
+	require.NoError(t, err)
+
+	candidates := Select([]parser.ParseResult{
+		{Title: "Movie.2020.1080p.BluRay.x264-GROUP", Seeders: 900},
+		{Title: "Movie.2020.1080p.BluRay.REMUX.AVC-GROUP", Seeders: 100},
+		{Title: "Movie.2020.1080p.PROPER.BluRay.REMUX.AVC-GROUP", Seeders: 50},
+		{Title: "Movie.2020.1080p.HC.WEB-DL.x264-GROUP", Seeders: 2000},
+		{Title: "Movie.2020.CAM.x264-GROUP", Seeders: 3000},
+		{Title: "Movie.2020.1080p.BluRay.x264-GROUP.Sample", Seeders: 50},
+		{Title: "Movie.2020.FRENCH.1080p.CAM.x264-GROUP", Seeders: 40},
+		{Title: "Movie.2020.1080p.WEB-DL.x264-GROUP", Seeders: 10},
+	}, Options{Words: words, MinSeeders: 45})
+
+	assert.Equal(t, []string{
+		"Movie.2020.1080p.PROPER.BluRay.REMUX.AVC-GROUP",
+		"Movie.2020.1080p.BluRay.REMUX.AVC-GROUP",
+		"Movie.2020.1080p.BluRay.x264-GROUP",
+		"Movie.2020.1080p.HC.WEB-DL.x264-GROUP",
+		"Movie.2020.CAM.x264-GROUP",
+		"Movie.2020.1080p.BluRay.x264-GROUP.Sample",
+		"Movie.2020.FRENCH.1080p.CAM.x264-GROUP",
+		"Movie.2020.1080p.WEB-DL.x264-GROUP",
+	}, titles(candidates))
+
+	assert.Equal(t, []string{"remux", "proper"}, candidates[0].PreferredWords)
+	assert.Equal(t, 25, candidates[0].WordScore)
+	assert.Equal(t, "quality WEB-DL 1080p, preferred words hc (score -50), 2000 seeders", candidates[3].Reason())
+	assert.Equal(t, `does not contain "1080p"; contains "CAM"`, candidates[4].Reason())
+
+	assert.Equal(t, []Elimination{
+		{Rule: "minSeeders", Count: 2},
+		{Rule: `mustNotContain "CAM"`, Count: 2},
+		{Rule: `mustContain "1080p"`, Count: 1},
+		{Rule: `mustNotContain "/\\bfrench\\b/"`, Count: 1},
+		{Rule: `mustNotContain "sample"`, Count: 1},
+	}, Eliminations(candidates))
+}
+
+func TestEliminationsCountResultsOnce(t *testing.T) {
+	profile, err := NewProfile(torrentsv1alpha1.QualityProfileSpec{
+		Qualities:     []torrentsv1alpha1.Quality{{Resolution: "1080p"}},
+		RequiredWords: []string{"remux", "atmos"},
+	})
+	require.NoError(t, err)
+
+	candidates := Select([]parser.ParseResult{
+		{Title: "Movie.2020.1080p.BluRay.x264-GROUP"},
+		{Title: "Movie.2020.720p.BluRay.REMUX-GROUP"},
+	}, Options{Profile: profile})
+
+	assert.Equal(t, []Elimination{
+		{Rule: "requiredWords", Count: 2},
+		{Rule: "quality", Count: 1},
+	}, Eliminations(candidates))
+}
@@ -0,0 +1,149 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Request is a page fetched on behalf of an indexer
+type Request struct {
+	// Method is GET or POST
+	Method string
+	URL    string
+	// PostData is the url encoded form sent with POST requests
+	PostData string
+	// Proxy both the solver browser and direct requests go through
+	Proxy *url.URL
+}
+
+// Solver fetches pages protected by anti-bot challenges
+type Solver interface {
+	// Do fetches the page for the indexer identified by key. httpClient is
+	// the indexer client, usable for direct requests once a challenge is solved.
+	Do(ctx context.Context, key string, httpClient *http.Client, req Request) ([]byte, error)
+	// Destroy releases what the solver keeps for the indexer identified by key
+	Destroy(ctx context.Context, key string) error
+	// DestroyAll releases what the solver keeps for every indexer
+	DestroyAll(ctx context.Context) error
+	// Ping checks the solver is reachable
+	Ping(ctx context.Context) error
+}
+
+// Entry is a solver registered from a ChallengeSolver resource
+type Entry struct {
+	Solver Solver
+	// Indexers lists indexers as "namespace/name", or a bare name matching
+	// the indexer in any namespace
+	Indexers []string
+	// Selector matches indexers by label, nil matches none
+	Selector labels.Selector
+	// Ready is false while the solver fails its health check
+	Ready bool
+}
+
+// matches reports whether the indexer uses this solver
+func (e *Entry) matches(namespace, name string, lbls labels.Set) bool {
+	for _, ref := range e.Indexers {
+		if ref == name || ref == namespace+"/"+name {
+			return true
+		}
+	}
+	return e.Selector != nil && !e.Selector.Empty() && e.Selector.Matches(lbls)
+}
+
+// ErrNotReady is returned when the solver of an indexer fails its health check
+var ErrNotReady = errors.New("challenge solver is not ready")
+
+// Registry holds the solvers available to indexers
+type Registry struct {
+	// Default is used by indexers that require a solver but are not selected
+	// by any registered one, nil when there is none
+	Default Solver
+
+	mu      sync.RWMutex
+	entries map[string]*Entry
+}
+
+// NewRegistry returns an empty registry falling back to def
+func NewRegistry(def Solver) *Registry {
+	return &Registry{Default: def}
+}
+
+// Set registers or replaces the solver called name
+func (r *Registry) Set(name string, entry *Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.entries == nil {
+		r.entries = make(map[string]*Entry)
+	}
+	r.entries[name] = entry
+}
+
+// Get returns the solver called name
+func (r *Registry) Get(name string) (*Entry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.entries[name]
+	return entry, ok
+}
+
+// Remove unregisters the solver called name and returns it
+func (r *Registry) Remove(name string) (*Entry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.entries[name]
+	delete(r.entries, name)
+	return entry, ok
+}
+
+// Lookup returns the registered solver selecting the indexer. Solvers are
+// considered by name, so the choice is stable when several match. ok is
+// false when no registered solver selects the indexer.
+func (r *Registry) Lookup(namespace, name string, lbls map[string]string) (solverName string, entry *Entry, ok bool) {
+	if r == nil {
+		return "", nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.entries))
+	for n := range r.entries {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		if r.entries[n].matches(namespace, name, labels.Set(lbls)) {
+			return n, r.entries[n], true
+		}
+	}
+	return "", nil, false
+}
+
+// Destroy releases what every solver keeps for the indexer identified by key
+func (r *Registry) Destroy(ctx context.Context, key string) error {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	solvers := make([]Solver, 0, len(r.entries)+1)
+	for _, entry := range r.entries {
+		solvers = append(solvers, entry.Solver)
+	}
+	r.mu.RUnlock()
+	if r.Default != nil {
+		solvers = append(solvers, r.Default)
+	}
+
+	var errs []error
+	for _, s := range solvers {
+		errs = append(errs, s.Destroy(ctx, key))
+	}
+	return errors.Join(errs...)
+}
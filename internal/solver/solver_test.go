@@ -0,0 +1,75 @@
+package solver
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type fakeSolver struct {
+	destroyed []string
+}
+
+func (f *fakeSolver) Do(ctx context.Context, key string, httpClient *http.Client, req Request) ([]byte, error) {
+	return nil, nil
+}
+
+func (f *fakeSolver) Destroy(ctx context.Context, key string) error {
+	f.destroyed = append(f.destroyed, key)
+	return nil
+}
+
+func (f *fakeSolver) DestroyAll(ctx context.Context) error { return nil }
+
+func (f *fakeSolver) Ping(ctx context.Context) error { return nil }
+
+func TestRegistryLookup(t *testing.T) {
+	r := NewRegistry(nil)
+	r.Set("by-name", &Entry{Solver: &fakeSolver{}, Indexers: []string{"media/tracker", "other"}})
+	r.Set("by-label", &Entry{Solver: &fakeSolver{}, Selector: labels.SelectorFromSet(labels.Set{"cloudflare": "true"})})
+	r.Set("nothing", &Entry{Solver: &fakeSolver{}, Selector: labels.Nothing()})
+
+	tests := []struct {
+		name      string
+		namespace string
+		indexer   string
+		labels    map[string]string
+		want      string
+	}{
+		{name: "namespaced name", namespace: "media", indexer: "tracker", want: "by-name"},
+		{name: "other namespace", namespace: "default", indexer: "tracker"},
+		{name: "bare name", namespace: "default", indexer: "other", want: "by-name"},
+		{name: "label", namespace: "default", indexer: "protected", labels: map[string]string{"cloudflare": "true"}, want: "by-label"},
+		{name: "first by name wins", namespace: "media", indexer: "tracker", labels: map[string]string{"cloudflare": "true"}, want: "by-label"},
+		{name: "no match", namespace: "default", indexer: "public"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, _, ok := r.Lookup(tt.namespace, tt.indexer, tt.labels)
+			assert.Equal(t, tt.want != "", ok)
+			assert.Equal(t, tt.want, name)
+		})
+	}
+
+	r.Remove("by-label")
+	_, _, ok := r.Lookup("default", "protected", map[string]string{"cloudflare": "true"})
+	assert.False(t, ok)
+}
+
+func TestRegistryDestroy(t *testing.T) {
+	def, registered := &fakeSolver{}, &fakeSolver{}
+	r := NewRegistry(def)
+	r.Set("flaresolverr", &Entry{Solver: registered})
+
+	require.NoError(t, r.Destroy(context.Background(), "default/tracker"))
+	assert.Equal(t, []string{"default/tracker"}, def.destroyed)
+	assert.Equal(t, []string{"default/tracker"}, registered.destroyed)
+
+	var nilRegistry *Registry
+	assert.NoError(t, nilRegistry.Destroy(context.Background(), "default/tracker"))
+}
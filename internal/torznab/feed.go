@@ -0,0 +1,256 @@
+package torznab
+
+import (
+	"encoding/xml"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/parser"
+	"vitoru.fun/torrents/internal/search"
+)
+
+// Torznab error codes, see the Newznab API specification
+const (
+	ErrIncorrectCredentials = 100
+	ErrMissingParameter     = 200
+	ErrIncorrectParameter   = 201
+	ErrNoSuchFunction       = 202
+	ErrFunctionUnavailable  = 203
+	ErrNoSuchItem           = 300
+	ErrUnknown              = 900
+)
+
+type apiError struct {
+	XMLName     xml.Name `xml:"error"`
+	Code        int      `xml:"code,attr"`
+	Description string   `xml:"description,attr"`
+}
+
+type caps struct {
+	XMLName    xml.Name       `xml:"caps"`
+	Server     capsServer     `xml:"server"`
+	Limits     capsLimits     `xml:"limits"`
+	Searching  capsSearching  `xml:"searching"`
+	Categories []capsCategory `xml:"categories>category"`
+}
+
+type capsServer struct {
+	Title string `xml:"title,attr"`
+}
+
+type capsLimits struct {
+	Default int `xml:"default,attr"`
+	Max     int `xml:"max,attr"`
+}
+
+type capsSearching struct {
+	Search      capsMode `xml:"search"`
+	TVSearch    capsMode `xml:"tv-search"`
+	MovieSearch capsMode `xml:"movie-search"`
+	MusicSearch capsMode `xml:"music-search"`
+	AudioSearch capsMode `xml:"audio-search"`
+	BookSearch  capsMode `xml:"book-search"`
+}
+
+type capsMode struct {
+	Available       string `xml:"available,attr"`
+	SupportedParams string `xml:"supportedParams,attr"`
+}
+
+type capsCategory struct {
+	ID      int            `xml:"id,attr"`
+	Name    string         `xml:"name,attr"`
+	Subcats []capsCategory `xml:"subcat,omitempty"`
+}
+
+type rss struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	Atom    string   `xml:"xmlns:atom,attr"`
+	Torznab string   `xml:"xmlns:torznab,attr"`
+	Channel channel  `xml:"channel"`
+}
+
+type channel struct {
+	Title       string `xml:"title"`
+	Description string `xml:"description"`
+	Items       []item `xml:"item"`
+}
+
+type item struct {
+	Title      string    `xml:"title"`
+	GUID       string    `xml:"guid"`
+	Link       string    `xml:"link"`
+	PubDate    string    `xml:"pubDate"`
+	Size       int64     `xml:"size"`
+	Categories []int     `xml:"category"`
+	Enclosure  enclosure `xml:"enclosure"`
+	Attrs      []attr    `xml:"torznab:attr"`
+}
+
+type enclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type attr struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// newCaps describes what the indexers support together
+func newCaps(title string, indexers []torrentsv1alpha1.Indexer) caps {
+	c := caps{
+		Server: capsServer{Title: title},
+		Limits: capsLimits{Default: defaultLimit, Max: maxLimit},
+		Searching: capsSearching{
+			Search:      capsMode{Available: "yes", SupportedParams: "q"},
+			TVSearch:    capsMode{Available: "no"},
+			MovieSearch: capsMode{Available: "no"},
+			MusicSearch: capsMode{Available: "no"},
+			AudioSearch: capsMode{Available: "no"},
+			BookSearch:  capsMode{Available: "no"},
+		},
+	}
+
+	var tv, movie, music, book []string
+	parents := map[int]*capsCategory{}
+	var order []int
+	for i := range indexers {
+		modes := indexers[i].Spec.Caps.Modes
+		tv = mergeParams(tv, modes.TvSearch)
+		movie = mergeParams(movie, modes.MovieSearch)
+		music = mergeParams(music, modes.MusicSearch)
+		book = mergeParams(book, modes.BookSearch)
+
+		for _, cat := range search.IndexerCategories(&indexers[i]) {
+			parentCat, _ := search.CategoryByName(strconv.Itoa(cat.Parent()))
+			parent, ok := parents[parentCat.ID]
+			if !ok {
+				parent = &capsCategory{ID: parentCat.ID, Name: parentCat.Name}
+				parents[parentCat.ID] = parent
+				order = append(order, parentCat.ID)
+			}
+			if cat.ID != parent.ID && !hasSubcat(parent, cat.ID) {
+				parent.Subcats = append(parent.Subcats, capsCategory{ID: cat.ID, Name: cat.Name})
+			}
+		}
+	}
+
+	c.Searching.TVSearch = modeCaps(tv)
+	c.Searching.MovieSearch = modeCaps(movie)
+	c.Searching.MusicSearch = modeCaps(music)
+	c.Searching.AudioSearch = modeCaps(music)
+	c.Searching.BookSearch = modeCaps(book)
+
+	sort.Ints(order)
+	for _, id := range order {
+		parent := parents[id]
+		sort.Slice(parent.Subcats, func(i, j int) bool { return parent.Subcats[i].ID < parent.Subcats[j].ID })
+		c.Categories = append(c.Categories, *parent)
+	}
+	return c
+}
+
+func modeCaps(params []string) capsMode {
+	if len(params) == 0 {
+		return capsMode{Available: "no"}
+	}
+	return capsMode{Available: "yes", SupportedParams: strings.Join(params, ",")}
+}
+
+func mergeParams(params, more []string) []string {
+	for _, p := range more {
+		found := false
+		for _, existing := range params {
+			if existing == p {
+				found = true
+				break
+			}
+		}
+		if !found {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+func hasSubcat(parent *capsCategory, id int) bool {
+	for _, sub := range parent.Subcats {
+		if sub.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// result is a search result along with the indexer it came from
+type result struct {
+	parser.ParseResult
+	indexer *torrentsv1alpha1.Indexer
+}
+
+// newItem renders a result with the standard Torznab attributes
+func newItem(r result, categories []int) item {
+	size := parser.ParseSize(r.Size)
+	published := time.Now()
+	if r.PublishedAt != nil {
+		published = *r.PublishedAt
+	}
+
+	category := search.OtherCategory
+	if len(categories) > 0 {
+		category = categories[0]
+	} else if cats := search.IndexerCategories(r.indexer); len(cats) > 0 {
+		category = cats[0].ID
+	}
+
+	it := item{
+		Title:      r.Title,
+		GUID:       r.Magnet,
+		Link:       r.Magnet,
+		PubDate:    published.Format(time.RFC1123Z),
+		Size:       size,
+		Categories: []int{category},
+		Enclosure: enclosure{
+			URL:    r.Magnet,
+			Length: size,
+			Type:   "application/x-bittorrent",
+		},
+		Attrs: []attr{
+			{Name: "category", Value: strconv.Itoa(category)},
+			{Name: "size", Value: strconv.FormatInt(size, 10)},
+			{Name: "seeders", Value: strconv.Itoa(r.Seeders)},
+			{Name: "peers", Value: strconv.Itoa(r.Seeders + r.Leechers)},
+			{Name: "downloadvolumefactor", Value: "1"},
+			{Name: "uploadvolumefactor", Value: "1"},
+		},
+	}
+
+	if strings.HasPrefix(r.Magnet, "magnet:") {
+		it.Attrs = append(it.Attrs, attr{Name: "magneturl", Value: r.Magnet})
+		if hash := infoHash(r.Magnet); hash != "" {
+			it.Attrs = append(it.Attrs, attr{Name: "infohash", Value: hash})
+		}
+	}
+	return it
+}
+
+// infoHash extracts the BitTorrent info hash from a magnet link
+func infoHash(magnet string) string {
+	u, err := url.Parse(magnet)
+	if err != nil {
+		return ""
+	}
+	for _, xt := range u.Query()["xt"] {
+		if hash, ok := strings.CutPrefix(xt, "urn:btih:"); ok {
+			return strings.ToLower(hash)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,349 @@
+package torznab
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/parser"
+	"vitoru.fun/torrents/internal/search"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 100
+
+	// APIKeySecretKey is the key of the API key in the Secret
+	APIKeySecretKey = "apikey"
+)
+
+// Searcher runs a query against a single indexer
+type Searcher interface {
+	Search(ctx context.Context, indexer *torrentsv1alpha1.Indexer, q search.Query) ([]parser.ParseResult, error)
+}
+
+// Server exposes the cluster Indexers to Sonarr, Radarr and other Torznab
+// clients. Every Indexer is served under /torznab/<namespace>/<name>/api and
+// all healthy Indexers together under /torznab/all/api.
+type Server struct {
+	// Addr the server listens on, e.g. ":9117"
+	Addr     string
+	Reader   client.Reader
+	Searcher Searcher
+	// APIKeySecret references the Secret holding the API key clients must
+	// present, nil serves the API without authentication
+	APIKeySecret *types.NamespacedName
+}
+
+// Start serves the API until ctx is done, so the server can be added to the
+// manager as a Runnable
+func (s *Server) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.Addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.FromContext(ctx).Info("Serving Torznab API", "addr", s.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// NeedLeaderElection lets every replica serve the API
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Handler returns the HTTP handler of the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /torznab/all/api", func(w http.ResponseWriter, r *http.Request) {
+		s.serve(w, r, "k8s-arr", s.readyIndexers)
+	})
+	mux.HandleFunc("GET /torznab/{namespace}/{name}/api", func(w http.ResponseWriter, r *http.Request) {
+		key := types.NamespacedName{Namespace: r.PathValue("namespace"), Name: r.PathValue("name")}
+		s.serve(w, r, key.Name, func(ctx context.Context) ([]torrentsv1alpha1.Indexer, error) {
+			var indexer torrentsv1alpha1.Indexer
+			if err := s.Reader.Get(ctx, key, &indexer); err != nil {
+				return nil, err
+			}
+			return []torrentsv1alpha1.Indexer{indexer}, nil
+		})
+	})
+	return mux
+}
+
+// readyIndexers lists the indexers passing their health check
+func (s *Server) readyIndexers(ctx context.Context) ([]torrentsv1alpha1.Indexer, error) {
+	var list torrentsv1alpha1.IndexerList
+	if err := s.Reader.List(ctx, &list); err != nil {
+		return nil, err
+	}
+	var ready []torrentsv1alpha1.Indexer
+	for _, indexer := range list.Items {
+		if apimeta.IsStatusConditionTrue(indexer.Status.Conditions, "Ready") {
+			ready = append(ready, indexer)
+		}
+	}
+	return ready, nil
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, title string, indexers func(context.Context) ([]torrentsv1alpha1.Indexer, error)) {
+	ctx := r.Context()
+	params := r.URL.Query()
+
+	if !s.authenticate(ctx, r) {
+		writeError(w, http.StatusUnauthorized, ErrIncorrectCredentials, "Incorrect user credentials")
+		return
+	}
+
+	t := params.Get("t")
+	if t == "" {
+		writeError(w, http.StatusBadRequest, ErrMissingParameter, "Missing parameter (t)")
+		return
+	}
+	mode, ok := searchModes[t]
+	if t != "caps" && !ok {
+		writeError(w, http.StatusBadRequest, ErrNoSuchFunction, fmt.Sprintf("No such function (%s)", t))
+		return
+	}
+
+	list, err := indexers(ctx)
+	if apierrors.IsNotFound(err) {
+		writeError(w, http.StatusNotFound, ErrNoSuchItem, "No such indexer")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ErrUnknown, err.Error())
+		return
+	}
+
+	if t == "caps" {
+		writeXML(w, http.StatusOK, newCaps(title, list))
+		return
+	}
+
+	q, limit, offset, err := parseQuery(mode, params)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrIncorrectParameter, "Incorrect parameter: "+err.Error())
+		return
+	}
+
+	var supported []torrentsv1alpha1.Indexer
+	for _, indexer := range list {
+		if search.Supports(&indexer, mode) {
+			supported = append(supported, indexer)
+		}
+	}
+	if len(supported) == 0 {
+		writeError(w, http.StatusBadRequest, ErrFunctionUnavailable, fmt.Sprintf("Function not available (%s)", t))
+		return
+	}
+
+	results, err := s.search(ctx, supported, q)
+	if err != nil && len(supported) == 1 {
+		writeError(w, http.StatusInternalServerError, ErrUnknown, err.Error())
+		return
+	}
+
+	// Best seeded first, like TorrentRequests pick them
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Seeders > results[j].Seeders })
+	if offset > len(results) {
+		offset = len(results)
+	}
+	results = results[offset:]
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	feed := rss{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Torznab: "http://torznab.com/schemas/2015/feed",
+		Channel: channel{Title: title, Description: title + " Torznab feed"},
+	}
+	for _, res := range results {
+		feed.Channel.Items = append(feed.Channel.Items, newItem(res, q.Categories))
+	}
+	writeXML(w, http.StatusOK, feed)
+}
+
+// search queries the indexers concurrently. Failing indexers are left out
+// of the results, the last failure is returned for callers searching a
+// single indexer.
+func (s *Server) search(ctx context.Context, indexers []torrentsv1alpha1.Indexer, q search.Query) ([]result, error) {
+	l := log.FromContext(ctx)
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results []result
+		lastErr error
+	)
+	for i := range indexers {
+		indexer := &indexers[i]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			found, err := s.Searcher.Search(ctx, indexer, q)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				l.Error(err, "Torznab search failed for indexer", "indexer", indexer.Name)
+				lastErr = err
+				return
+			}
+			for _, r := range found {
+				results = append(results, result{ParseResult: r, indexer: indexer})
+			}
+		}()
+	}
+	wg.Wait()
+	return results, lastErr
+}
+
+var searchModes = map[string]search.Mode{
+	"search":   search.ModeSearch,
+	"tvsearch": search.ModeTV,
+	"movie":    search.ModeMovie,
+	"music":    search.ModeMusic,
+	"audio":    search.ModeMusic,
+	"book":     search.ModeBook,
+}
+
+// parseQuery reads the search parameters common to every function
+func parseQuery(mode search.Mode, params map[string][]string) (search.Query, int, int, error) {
+	get := func(key string) string {
+		if v := params[key]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	q := search.Query{Mode: mode, Keywords: get("q")}
+	if cats := get("cat"); cats != "" {
+		for _, c := range strings.Split(cats, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(c))
+			if err != nil {
+				return q, 0, 0, fmt.Errorf("invalid category %q", c)
+			}
+			q.Categories = append(q.Categories, id)
+		}
+	}
+
+	number := func(key string) (int, error) {
+		v := get(key)
+		if v == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid %s %q", key, v)
+		}
+		return n, nil
+	}
+
+	var err error
+	if q.Season, err = number("season"); err != nil {
+		return q, 0, 0, err
+	}
+	if q.Episode, err = number("ep"); err != nil {
+		return q, 0, 0, err
+	}
+	if q.TVDBID, err = number("tvdbid"); err != nil {
+		return q, 0, 0, err
+	}
+	if q.TMDBID, err = number("tmdbid"); err != nil {
+		return q, 0, 0, err
+	}
+	if q.Year, err = number("year"); err != nil {
+		return q, 0, 0, err
+	}
+	q.Artist, q.Album, q.Label = get("artist"), get("album"), get("label")
+	q.Author, q.Title = get("author"), get("title")
+	if id := get("imdbid"); id != "" {
+		q.IMDBID = "tt" + strings.TrimPrefix(id, "tt")
+	}
+
+	// Some clients send limit=0 for the default page size
+	limit := defaultLimit
+	if v := get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return q, 0, 0, fmt.Errorf("invalid limit %q", v)
+		}
+		if n > 0 {
+			limit = min(n, maxLimit)
+		}
+	}
+
+	offset, err := number("offset")
+	if err != nil {
+		return q, 0, 0, err
+	}
+	return q, limit, offset, nil
+}
+
+// authenticate checks the API key from the apikey parameter or the
+// X-Api-Key header against the configured Secret
+func (s *Server) authenticate(ctx context.Context, r *http.Request) bool {
+	if s.APIKeySecret == nil {
+		return true
+	}
+
+	var secret corev1.Secret
+	if err := s.Reader.Get(ctx, *s.APIKeySecret, &secret); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to read Torznab API key", "secret", s.APIKeySecret)
+		return false
+	}
+	expected := secret.Data[APIKeySecretKey]
+
+	key := r.URL.Query().Get("apikey")
+	if key == "" {
+		key = r.Header.Get("X-Api-Key")
+	}
+	return len(expected) > 0 && subtle.ConstantTimeCompare([]byte(key), expected) == 1
+}
+
+func writeError(w http.ResponseWriter, status, code int, description string) {
+	writeXML(w, status, apiError{Code: code, Description: description})
+}
+
+func writeXML(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(v)
+}
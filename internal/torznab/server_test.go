@@ -0,0 +1,247 @@
+package torznab
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/parser"
+	"vitoru.fun/torrents/internal/search"
+)
+
+// fakeSearcher returns canned results per indexer and records the queries
+type fakeSearcher struct {
+	mu      sync.Mutex
+	results map[string][]parser.ParseResult
+	queries []search.Query
+}
+
+func (f *fakeSearcher) Search(ctx context.Context, indexer *torrentsv1alpha1.Indexer, q search.Query) ([]parser.ParseResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries = append(f.queries, q)
+	return f.results[indexer.Name], nil
+}
+
+func newIndexer(name string, ready bool, modes torrentsv1alpha1.Modes) *torrentsv1alpha1.Indexer {
+	status := metav1.ConditionFalse
+	if ready {
+		status = metav1.ConditionTrue
+	}
+	return &torrentsv1alpha1.Indexer{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "media"},
+		Spec: torrentsv1alpha1.IndexerSpec{
+			Caps: torrentsv1alpha1.Caps{
+				Modes: modes,
+				CategoryMappings: []torrentsv1alpha1.CategoryMapping{
+					{ID: "1", Cat: "Movies/HD"},
+					{ID: "2", Cat: "TV"},
+				},
+			},
+		},
+		Status: torrentsv1alpha1.IndexerStatus{
+			Conditions: []metav1.Condition{{Type: "Ready", Status: status, Reason: "Test"}},
+		},
+	}
+}
+
+func newServer(t *testing.T, searcher Searcher, objs ...runtime.Object) *Server {
+	scheme := runtime.NewScheme()
+	require.NoError(t, torrentsv1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build()
+	return &Server{Reader: reader, Searcher: searcher}
+}
+
+func get(t *testing.T, s *Server, target string) (*httptest.ResponseRecorder, string) {
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec, rec.Body.String()
+}
+
+func TestCaps(t *testing.T) {
+	s := newServer(t, &fakeSearcher{},
+		newIndexer("tracker", true, torrentsv1alpha1.Modes{Search: []string{"q"}, TvSearch: []string{"q", "season", "ep"}}))
+
+	rec, body := get(t, s, "/torznab/media/tracker/api?t=caps")
+	require.Equal(t, http.StatusOK, rec.Code, body)
+
+	var c caps
+	require.NoError(t, xml.Unmarshal([]byte(body), &c))
+	assert.Equal(t, "yes", c.Searching.TVSearch.Available)
+	assert.Equal(t, "q,season,ep", c.Searching.TVSearch.SupportedParams)
+	assert.Equal(t, "no", c.Searching.MovieSearch.Available)
+	require.Len(t, c.Categories, 2)
+	assert.Equal(t, 2000, c.Categories[0].ID)
+	assert.Equal(t, []capsCategory{{ID: 2040, Name: "Movies/HD"}}, c.Categories[0].Subcats)
+	assert.Equal(t, 5000, c.Categories[1].ID)
+}
+
+func TestSearchAll(t *testing.T) {
+	searcher := &fakeSearcher{results: map[string][]parser.ParseResult{
+		"tracker": {
+			{Title: "Debian 12", Magnet: "magnet:?xt=urn:btih:ABCDEF&dn=debian", Size: "1 GB", Seeders: 5, Leechers: 1},
+			{Title: "Debian 11", Magnet: "https://tracker.example.com/dl/11", Size: "900 MB", Seeders: 1},
+		},
+		"other": {
+			{Title: "Debian 12 DVD", Magnet: "magnet:?xt=urn:btih:123456", Size: "4 GB", Seeders: 20},
+		},
+		"down": {
+			{Title: "Never returned", Magnet: "magnet:?xt=urn:btih:000000", Seeders: 100},
+		},
+	}}
+	modes := torrentsv1alpha1.Modes{Search: []string{"q"}}
+	s := newServer(t, searcher,
+		newIndexer("tracker", true, modes), newIndexer("other", true, modes), newIndexer("down", false, modes))
+
+	rec, body := get(t, s, "/torznab/all/api?t=search&q=debian&cat=2000&limit=2")
+	require.Equal(t, http.StatusOK, rec.Code, body)
+	assert.Contains(t, body, `xmlns:torznab="http://torznab.com/schemas/2015/feed"`)
+	assert.Contains(t, body, `<torznab:attr name="infohash" value="abcdef"></torznab:attr>`)
+
+	var feed rss
+	require.NoError(t, xml.Unmarshal([]byte(body), &feed))
+	require.Len(t, feed.Channel.Items, 2)
+	assert.Equal(t, "Debian 12 DVD", feed.Channel.Items[0].Title)
+	assert.Equal(t, "Debian 12", feed.Channel.Items[1].Title)
+	assert.Equal(t, int64(1<<30), feed.Channel.Items[1].Size)
+	assert.Equal(t, []int{2000}, feed.Channel.Items[1].Categories)
+
+	require.Len(t, searcher.queries, 2)
+	assert.Equal(t, search.Query{Mode: search.ModeSearch, Keywords: "debian", Categories: []int{2000}}, searcher.queries[0])
+
+	_, body = get(t, s, "/torznab/all/api?t=search&q=debian&offset=2")
+	var page rss
+	require.NoError(t, xml.Unmarshal([]byte(body), &page))
+	require.Len(t, page.Channel.Items, 1)
+	assert.Equal(t, "Debian 11", page.Channel.Items[0].Title)
+
+	// A limit of zero or less is the default page size
+	for _, limit := range []string{"0", "-1"} {
+		_, body = get(t, s, "/torznab/all/api?t=search&q=debian&limit="+limit)
+		var all rss
+		require.NoError(t, xml.Unmarshal([]byte(body), &all), body)
+		assert.Len(t, all.Channel.Items, 3, limit)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	s := newServer(t, &fakeSearcher{},
+		newIndexer("tracker", true, torrentsv1alpha1.Modes{Search: []string{"q"}}))
+
+	tests := []struct {
+		name   string
+		target string
+		status int
+		code   int
+	}{
+		{"missing function", "/torznab/media/tracker/api", http.StatusBadRequest, ErrMissingParameter},
+		{"unknown function", "/torznab/media/tracker/api?t=details", http.StatusBadRequest, ErrNoSuchFunction},
+		{"unsupported mode", "/torznab/media/tracker/api?t=tvsearch&q=show", http.StatusBadRequest, ErrFunctionUnavailable},
+		{"unknown indexer", "/torznab/media/missing/api?t=caps", http.StatusNotFound, ErrNoSuchItem},
+		{"invalid category", "/torznab/media/tracker/api?t=search&cat=movies", http.StatusBadRequest, ErrIncorrectParameter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, body := get(t, s, tt.target)
+			assert.Equal(t, tt.status, rec.Code)
+			var e apiError
+			require.NoError(t, xml.Unmarshal([]byte(body), &e))
+			assert.Equal(t, tt.code, e.Code)
+		})
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "torznab", Namespace: "k8s-arr"},
+		Data:       map[string][]byte{APIKeySecretKey: []byte("s3cret")},
+	}
+	s := newServer(t, &fakeSearcher{}, secret,
+		newIndexer("tracker", true, torrentsv1alpha1.Modes{Search: []string{"q"}}))
+	s.APIKeySecret = &types.NamespacedName{Namespace: "k8s-arr", Name: "torznab"}
+
+	rec, _ := get(t, s, "/torznab/media/tracker/api?t=caps")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec, _ = get(t, s, "/torznab/media/tracker/api?t=caps&apikey=wrong")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec, _ = get(t, s, "/torznab/media/tracker/api?t=caps&apikey=s3cret")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/torznab/media/tracker/api?t=caps", nil)
+	req.Header.Set("X-Api-Key", "s3cret")
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestTVSearchParams(t *testing.T) {
+	searcher := &fakeSearcher{}
+	s := newServer(t, searcher,
+		newIndexer("tracker", true, torrentsv1alpha1.Modes{Search: []string{"q"}, TvSearch: []string{"q", "season", "ep", "imdbid"}}))
+
+	rec, body := get(t, s, "/torznab/media/tracker/api?t=tvsearch&q=Show&season=1&ep=2&imdbid=0944947&tvdbid=121361")
+	require.Equal(t, http.StatusOK, rec.Code, body)
+	require.Len(t, searcher.queries, 1)
+	assert.Equal(t, search.Query{
+		Mode:     search.ModeTV,
+		Keywords: "Show",
+		Season:   1,
+		Episode:  2,
+		TVDBID:   121361,
+		IMDBID:   "tt0944947",
+	}, searcher.queries[0])
+
+	rec, _ = get(t, s, "/torznab/media/tracker/api?t=tvsearch&q=Show&ep=10/25")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestMovieSearchParams(t *testing.T) {
+	searcher := &fakeSearcher{}
+	s := newServer(t, searcher,
+		newIndexer("tracker", true, torrentsv1alpha1.Modes{Search: []string{"q"}, MovieSearch: []string{"q", "imdbid", "tmdbid", "year"}}))
+
+	rec, body := get(t, s, "/torznab/media/tracker/api?t=movie&q=The+Matrix&imdbid=tt0133093&tmdbid=603&year=1999")
+	require.Equal(t, http.StatusOK, rec.Code, body)
+	require.Len(t, searcher.queries, 1)
+	assert.Equal(t, search.Query{
+		Mode:     search.ModeMovie,
+		Keywords: "The Matrix",
+		IMDBID:   "tt0133093",
+		TMDBID:   603,
+		Year:     1999,
+	}, searcher.queries[0])
+}
+
+func TestMusicAndBookSearchParams(t *testing.T) {
+	searcher := &fakeSearcher{}
+	s := newServer(t, searcher, newIndexer("tracker", true, torrentsv1alpha1.Modes{
+		Search:      []string{"q"},
+		MusicSearch: []string{"q", "artist", "album"},
+		BookSearch:  []string{"q", "author", "title"},
+	}))
+
+	rec, body := get(t, s, "/torznab/media/tracker/api?t=music&artist=Daft+Punk&album=Discovery&year=2001")
+	require.Equal(t, http.StatusOK, rec.Code, body)
+	rec, body = get(t, s, "/torznab/media/tracker/api?t=book&author=Frank+Herbert&title=Dune")
+	require.Equal(t, http.StatusOK, rec.Code, body)
+
+	require.Len(t, searcher.queries, 2)
+	assert.Equal(t, search.Query{Mode: search.ModeMusic, Artist: "Daft Punk", Album: "Discovery", Year: 2001}, searcher.queries[0])
+	assert.Equal(t, search.Query{Mode: search.ModeBook, Author: "Frank Herbert", Title: "Dune"}, searcher.queries[1])
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+	"vitoru.fun/torrents/internal/selection"
+)
+
+// SetupTorrentRequestWebhookWithManager registers the TorrentRequest webhook
+func SetupTorrentRequestWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&torrentsv1alpha1.TorrentRequest{}).
+		WithValidator(&TorrentRequestCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-torrents-vitoru-fun-v1alpha1-torrentrequest,mutating=false,failurePolicy=fail,sideEffects=None,groups=torrents.vitoru.fun,resources=torrentrequests,verbs=create;update,versions=v1alpha1,name=vtorrentrequest-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// TorrentRequestCustomValidator rejects TorrentRequests whose title patterns
+// or filter and sortBy expressions do not compile
+type TorrentRequestCustomValidator struct{}
+
+var _ admission.CustomValidator = &TorrentRequestCustomValidator{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *TorrentRequestCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, validateTorrentRequest(obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *TorrentRequestCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, validateTorrentRequest(newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *TorrentRequestCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateTorrentRequest(obj runtime.Object) error {
+	tr, ok := obj.(*torrentsv1alpha1.TorrentRequest)
+	if !ok {
+		return fmt.Errorf("expected a TorrentRequest but got %T", obj)
+	}
+
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+	errs = append(errs, validatePatterns(spec.Child("mustContain"), tr.Spec.MustContain)...)
+	errs = append(errs, validatePatterns(spec.Child("mustNotContain"), tr.Spec.MustNotContain)...)
+	for i, pw := range tr.Spec.PreferredWords {
+		if _, err := selection.NewPattern(pw.Pattern); err != nil {
+			path := spec.Child("preferredWords").Index(i).Child("pattern")
+			errs = append(errs, field.Invalid(path, pw.Pattern, err.Error()))
+		}
+	}
+	if tr.Spec.Filter != "" {
+		if _, err := selection.CompileFilter(tr.Spec.Filter); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("filter"), tr.Spec.Filter, err.Error()))
+		}
+	}
+	if tr.Spec.SortBy != "" {
+		if _, err := selection.CompileSortBy(tr.Spec.SortBy); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("sortBy"), tr.Spec.SortBy, err.Error()))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(torrentsv1alpha1.GroupVersion.WithKind("TorrentRequest").GroupKind(), tr.Name, errs)
+}
+
+func validatePatterns(path *field.Path, patterns []string) field.ErrorList {
+	var errs field.ErrorList
+	for i, p := range patterns {
+		if _, err := selection.NewPattern(p); err != nil {
+			errs = append(errs, field.Invalid(path.Index(i), p, err.Error()))
+		}
+	}
+	return errs
+}
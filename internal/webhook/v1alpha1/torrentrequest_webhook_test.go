@@ -0,0 +1,65 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	torrentsv1alpha1 "vitoru.fun/torrents/api/v1alpha1"
+)
+
+func TestValidateTorrentRequest(t *testing.T) {
+	tests := []struct {
+		name           string
+		mustContain    []string
+		mustNotContain []string
+		preferredWords []torrentsv1alpha1.PreferredWord
+		filter         string
+		sortBy         string
+		wantErr        []string
+	}{
+		{name: "no expressions"},
+		{name: "valid", filter: `seeders > 10 && release.resolution == "1080p"`, sortBy: "seeders + formatScore"},
+		{name: "syntax error", filter: "seeders >", wantErr: []string{"spec.filter"}},
+		{name: "unknown variable", filter: "peers > 1", wantErr: []string{"spec.filter", "undeclared reference"}},
+		{name: "filter not bool", filter: "seeders", wantErr: []string{"spec.filter", "expected bool"}},
+		{name: "sortBy not a number", sortBy: "title", wantErr: []string{"spec.sortBy", "expected a number"}},
+		{name: "both invalid", filter: "1", sortBy: "true", wantErr: []string{"spec.filter", "spec.sortBy"}},
+		{name: "valid words", mustContain: []string{"1080p", "/x26[45]/"}, mustNotContain: []string{"CAM"},
+			preferredWords: []torrentsv1alpha1.PreferredWord{{Pattern: "remux", Weight: 10}}},
+		{name: "invalid regex", mustNotContain: []string{"CAM", "/(/"}, wantErr: []string{"spec.mustNotContain[1]"}},
+		{name: "empty word", mustContain: []string{" "}, wantErr: []string{"spec.mustContain[0]", "pattern is empty"}},
+		{name: "invalid preferred word", preferredWords: []torrentsv1alpha1.PreferredWord{{Pattern: "/[/"}},
+			wantErr: []string{"spec.preferredWords[0].pattern"}},
+	}
+
+	v := &TorrentRequestCustomValidator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &torrentsv1alpha1.TorrentRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: torrentsv1alpha1.TorrentRequestSpec{
+					Keywords:       "ubuntu",
+					MustContain:    tt.mustContain,
+					MustNotContain: tt.mustNotContain,
+					PreferredWords: tt.preferredWords,
+					Filter:         tt.filter,
+					SortBy:         tt.sortBy,
+				},
+			}
+			_, createErr := v.ValidateCreate(context.Background(), tr)
+			_, updateErr := v.ValidateUpdate(context.Background(), tr, tr)
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, createErr)
+				assert.NoError(t, updateErr)
+				return
+			}
+			for _, want := range tt.wantErr {
+				assert.ErrorContains(t, createErr, want)
+				assert.ErrorContains(t, updateErr, want)
+			}
+		})
+	}
+}